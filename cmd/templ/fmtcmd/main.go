@@ -21,12 +21,13 @@ type Arguments struct {
 	Files       []string
 	LogLevel    string
 	WorkerCount int
+	QuoteStyle  parser.QuoteStyle
 }
 
 func Run(w io.Writer, args Arguments) (err error) {
 	// If no files are provided, read from stdin and write to stdout.
 	if len(args.Files) == 0 {
-		return format(writeToStdout, readFromStdin)
+		return format(writeToStdout, readFromStdin, args.QuoteStyle)
 	}
 
 	level := slog.LevelInfo.Level()
@@ -51,7 +52,7 @@ func Run(w io.Writer, args Arguments) (err error) {
 		if args.ToStdout {
 			write = writeToStdout
 		}
-		return format(write, read)
+		return format(write, read, args.QuoteStyle)
 	}
 	dir := args.Files[0]
 	return NewFormatter(log, dir, process, args.WorkerCount).Run()
@@ -134,7 +135,7 @@ func writeToFile(fileName, tgt string) error {
 	return atomic.WriteFile(fileName, bytes.NewBufferString(tgt))
 }
 
-func format(write writer, read reader) (err error) {
+func format(write writer, read reader, quoteStyle parser.QuoteStyle) (err error) {
 	fileName, src, err := read()
 	if err != nil {
 		return err
@@ -144,7 +145,7 @@ func format(write writer, read reader) (err error) {
 		return err
 	}
 	w := new(bytes.Buffer)
-	if err = t.Write(w); err != nil {
+	if err = t.Format(w, parser.FormatOptions{QuoteStyle: quoteStyle}); err != nil {
 		return fmt.Errorf("formatting error: %w", err)
 	}
 	return write(fileName, w.String())
@@ -73,6 +73,9 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	if cmd.Args.IncludeTimestamp {
 		opts = append(opts, generator.WithTimestamp(time.Now()))
 	}
+	if cmd.Args.RecordIDs {
+		opts = append(opts, generator.WithRecordIDs())
+	}
 
 	// Check the version of the templ module.
 	if err := modcheck.Check(cmd.Args.Path); err != nil {
@@ -23,6 +23,7 @@ type Arguments struct {
 	GenerateSourceMapVisualisations bool
 	IncludeVersion                  bool
 	IncludeTimestamp                bool
+	RecordIDs                       bool
 	LogLevel                        string
 	// PPROFPort is the port to run the pprof server on.
 	PPROFPort         int
@@ -14,6 +14,7 @@ import (
 	"github.com/a-h/templ/cmd/templ/generatecmd"
 	"github.com/a-h/templ/cmd/templ/lspcmd"
 	"github.com/a-h/templ/cmd/templ/migratecmd"
+	"github.com/a-h/templ/parser/v2"
 	"github.com/fatih/color"
 )
 
@@ -78,6 +79,8 @@ Args:
     Set to false to skip inclusion of the templ version in the generated code. (default true)
   -include-timestamp
     Set to true to include the current time in the generated code.
+  -record-ids
+    Set to true to record the id attributes that are rendered, so that duplicates can be reported by templ.DetectDuplicateIDs.
   -watch
     Set to true to watch the path for changes and regenerate code.
   -cmd <cmd>
@@ -122,6 +125,7 @@ func generateCmd(w io.Writer, args []string) (code int) {
 	sourceMapVisualisationsFlag := cmd.Bool("source-map-visualisations", false, "")
 	includeVersionFlag := cmd.Bool("include-version", true, "")
 	includeTimestampFlag := cmd.Bool("include-timestamp", false, "")
+	recordIDsFlag := cmd.Bool("record-ids", false, "")
 	watchFlag := cmd.Bool("watch", false, "")
 	openBrowserFlag := cmd.Bool("open-browser", true, "")
 	cmdFlag := cmd.String("cmd", "", "")
@@ -164,6 +168,7 @@ func generateCmd(w io.Writer, args []string) (code int) {
 		GenerateSourceMapVisualisations: *sourceMapVisualisationsFlag,
 		IncludeVersion:                  *includeVersionFlag,
 		IncludeTimestamp:                *includeTimestampFlag,
+		RecordIDs:                       *recordIDsFlag,
 		LogLevel:                        logLevel,
 		PPROFPort:                       *pprofPortFlag,
 		KeepOrphanedFiles:               *keepOrphanedFilesFlag,
@@ -237,6 +242,8 @@ Args:
     Set log verbosity level. (default "info", options: "debug", "info", "warn", "error")
   -w
     Number of workers to use when formatting code. (default runtime.NumCPUs).
+  -quote-style
+    Quotes to use around attribute values. (default "default", options: "default", "double", "single", "preserve")
   -help
     Print help and exit.
 `
@@ -252,6 +259,7 @@ func fmtCmd(w io.Writer, args []string) (code int) {
 	verboseFlag := cmd.Bool("v", false, "")
 	logLevelFlag := cmd.String("log-level", "info", "")
 	stdout := cmd.Bool("stdout", false, "")
+	quoteStyleFlag := cmd.String("quote-style", "default", "")
 
 	err := cmd.Parse(args)
 	if err != nil || *helpFlag {
@@ -259,6 +267,12 @@ func fmtCmd(w io.Writer, args []string) (code int) {
 		return
 	}
 
+	quoteStyle, err := parser.ParseQuoteStyle(*quoteStyleFlag)
+	if err != nil {
+		fmt.Fprintln(w, err.Error())
+		return 1
+	}
+
 	logLevel := *logLevelFlag
 	if *verboseFlag {
 		logLevel = "debug"
@@ -269,6 +283,7 @@ func fmtCmd(w io.Writer, args []string) (code int) {
 		Files:       cmd.Args(),
 		LogLevel:    logLevel,
 		WorkerCount: *workerCountFlag,
+		QuoteStyle:  quoteStyle,
 	})
 	if err != nil {
 		return 1
@@ -4,6 +4,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
 	"html"
 	"io"
 	"path/filepath"
@@ -48,6 +51,16 @@ func WithFileName(name string) GenerateOpt {
 	}
 }
 
+// WithRecordIDs generates a call to templ.RecordID before each id attribute, so that duplicate
+// ids can be reported by templ.DetectDuplicateIDs. It's off by default, since the calls are made
+// on every render, and split up the constant HTML around each id.
+func WithRecordIDs() GenerateOpt {
+	return func(g *generator) error {
+		g.recordIDs = true
+		return nil
+	}
+}
+
 func WithExtractStrings() GenerateOpt {
 	return func(g *generator) error {
 		g.w.literalWriter = &watchLiteralWriter{
@@ -61,9 +74,10 @@ func WithExtractStrings() GenerateOpt {
 // to the location of the generated Go code in the output.
 func Generate(template parser.TemplateFile, w io.Writer, opts ...GenerateOpt) (sm *parser.SourceMap, literals string, err error) {
 	g := &generator{
-		tf:        template,
-		w:         NewRangeWriter(w),
-		sourceMap: parser.NewSourceMap(),
+		tf:            template,
+		w:             NewRangeWriter(w),
+		sourceMap:     parser.NewSourceMap(),
+		templateSlots: templateSlots(template),
 	}
 	for _, opt := range opts {
 		if err = opt(g); err != nil {
@@ -89,6 +103,19 @@ type generator struct {
 	generatedDate string
 	// fileName to include in error messages if string expressions return an error.
 	fileName string
+	// recordIDs is set to record the id attributes that are rendered, see WithRecordIDs.
+	recordIDs bool
+	// textMode is set when the template being written outputs text instead of HTML, so
+	// string expressions aren't HTML escaped.
+	textMode bool
+	// slotsVar is the name of the variable that holds the slot content passed to the template
+	// being written, or empty if the template doesn't have any slots.
+	slotsVar string
+	// htmlSlotDepth is the number of <template> and custom elements that contain the node being
+	// written. Slots within them are left for the browser, e.g. in declarative shadow DOM.
+	htmlSlotDepth int
+	// templateSlots are the slots declared by each template in the file, see templateSlots.
+	templateSlots map[string]map[string]bool
 }
 
 func (g *generator) generate() (err error) {
@@ -349,6 +376,11 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 	var err error
 	var indentLevel int
 
+	if err = checkUnconditionalRecursion(t); err != nil {
+		return err
+	}
+	g.textMode = t.TextMode
+
 	// func
 	if _, err = g.w.Write("func "); err != nil {
 		return err
@@ -372,8 +404,21 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 		if err := g.writeTemplBuffer(indentLevel); err != nil {
 			return err
 		}
-		// ctx = templ.InitializeContext(ctx)
-		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.InitializeContext(ctx)\n"); err != nil {
+		// EnterComponent initializes the context, as well as checking the render depth.
+		// if ctx, templ_7745c5c3_Err = templ.EnterComponent(ctx); templ_7745c5c3_Err != nil {
+		//   return templ_7745c5c3_Err
+		// }
+		if _, err = g.w.WriteIndent(indentLevel, "if ctx, templ_7745c5c3_Err = templ.EnterComponent(ctx); templ_7745c5c3_Err != nil {\n"); err != nil {
+			return err
+		}
+		{
+			indentLevel++
+			if _, err = g.w.WriteIndent(indentLevel, "return templ_7745c5c3_Err\n"); err != nil {
+				return err
+			}
+			indentLevel--
+		}
+		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 			return err
 		}
 		g.childrenVar = g.createVariableName()
@@ -397,6 +442,14 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 			return err
 		}
+		// templ_7745c5c3_Var2 := templ.GetSlots(ctx)
+		g.slotsVar = ""
+		if name, ok := calledName(t.Expression.Value); ok && g.templateSlots[name] != nil {
+			g.slotsVar = g.createVariableName()
+			if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("%s := templ.GetSlots(ctx)\n", g.slotsVar)); err != nil {
+				return err
+			}
+		}
 		// ctx = templ.ClearChildren(children)
 		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.ClearChildren(ctx)\n"); err != nil {
 			return err
@@ -446,6 +499,52 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 	return nil
 }
 
+// checkUnconditionalRecursion returns an error if a template always calls itself, since it would
+// recurse infinitely regardless of the data it's rendered with. Recursive calls within if, for and
+// switch expressions are driven by data, so they're allowed, and guarded at runtime by
+// templ.WithMaxRenderDepth.
+func checkUnconditionalRecursion(t parser.HTMLTemplate) error {
+	name := calledFuncName(t.Expression.Value)
+	if name == "" {
+		return nil
+	}
+	if callsTemplateUnconditionally(t.Children, name) {
+		return fmt.Errorf("%s: template calls itself unconditionally, which results in infinite recursion, only call it within an if, for or switch expression", name)
+	}
+	return nil
+}
+
+func callsTemplateUnconditionally(nodes []parser.Node, name string) bool {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case parser.Element:
+			if callsTemplateUnconditionally(n.Children, name) {
+				return true
+			}
+		case parser.TemplElementExpression:
+			if calledFuncName(n.Expression.Value) == name {
+				return true
+			}
+		case parser.CallTemplateExpression:
+			if calledFuncName(n.Expression.Value) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// calledFuncName returns the name of the function in a function call or declaration,
+// e.g. `Name` for `Name(a, b)`. Method declarations and calls return an empty string.
+func calledFuncName(expr string) string {
+	expr = strings.TrimSpace(expr)
+	index := strings.Index(expr, "(")
+	if index < 1 {
+		return ""
+	}
+	return strings.TrimSpace(expr[:index])
+}
+
 func stripWhitespace(input []parser.Node) (output []parser.Node) {
 	for i, n := range input {
 		if _, isWhiteSpace := n.(parser.Whitespace); !isWhiteSpace {
@@ -500,8 +599,15 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 	case parser.DocType:
 		err = g.writeDocType(indentLevel, n)
 	case parser.Element:
+		if n.Ignored {
+			// Do not render elements excluded by a templ:ignore comment.
+			return
+		}
 		err = g.writeElement(indentLevel, n)
 	case parser.HTMLComment:
+		if n.IsIgnoreDirective() {
+			return
+		}
 		err = g.writeComment(indentLevel, n)
 	case parser.ChildrenExpression:
 		err = g.writeChildrenExpression(indentLevel)
@@ -513,12 +619,28 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 		err = g.writeCallTemplateExpression(indentLevel, n)
 	case parser.TemplElementExpression:
 		err = g.writeTemplElementExpression(indentLevel, n)
+	case parser.OnceExpression:
+		err = g.writeOnceExpression(indentLevel, n, next)
+	case parser.FragmentBlock:
+		err = g.writeFragmentBlock(indentLevel, n)
+	case parser.JSONScriptExpression:
+		err = g.writeJSONScriptExpression(indentLevel, n)
+	case parser.AssetExpression:
+		err = g.writeAssetExpression(indentLevel, n)
+	case parser.CData:
+		err = g.writeText(indentLevel, parser.Text{Value: "<![CDATA[" + n.Value + "]]>"})
+	case parser.RawHTML:
+		err = g.writeText(indentLevel, parser.Text{Value: n.Value})
 	case parser.IfExpression:
 		err = g.writeIfExpression(indentLevel, n, next)
 	case parser.SwitchExpression:
 		err = g.writeSwitchExpression(indentLevel, n, next)
 	case parser.StringExpression:
 		err = g.writeStringExpression(indentLevel, n.Expression)
+	case parser.InlineIfExpression:
+		err = g.writeInlineIfExpression(indentLevel, n)
+	case parser.TranslationExpression:
+		err = g.writeTranslationExpression(indentLevel, n)
 	case parser.Whitespace:
 		err = g.writeWhitespace(indentLevel, n)
 	case parser.Text:
@@ -526,6 +648,10 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 	case parser.GoComment:
 		// Do not render Go comments in the output HTML.
 		return
+	case parser.ConstBlock:
+		err = g.writeConstBlock(indentLevel, n)
+	case parser.GoCode:
+		err = g.writeGoCode(indentLevel, n)
 	default:
 		return fmt.Errorf("unhandled type: %v", reflect.TypeOf(n))
 	}
@@ -560,6 +686,10 @@ func isInlineOrText(next parser.Node) bool {
 		return true
 	case parser.StringExpression:
 		return true
+	case parser.InlineIfExpression:
+		return true
+	case parser.TranslationExpression:
+		return true
 	}
 	return false
 }
@@ -570,7 +700,7 @@ func (g *generator) writeWhitespaceTrailer(indentLevel int, n parser.TrailingSpa
 	}
 	// Normalize whitespace for minified output. In HTML, a single space is equivalent to
 	// any number of spaces, tabs, or newlines.
-	if n == parser.SpaceVertical {
+	if n == parser.SpaceVertical || n == parser.SpaceVerticalDouble {
 		n = parser.SpaceHorizontal
 	}
 	if _, err = g.w.WriteStringLiteral(indentLevel, string(n)); err != nil {
@@ -707,49 +837,256 @@ func (g *generator) writeTemplElementExpression(indentLevel int, n parser.TemplE
 
 func (g *generator) writeBlockTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
 	var r parser.Range
-	childrenName := g.createVariableName()
-	if _, err = g.w.WriteIndent(indentLevel, childrenName+" := templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {\n"); err != nil {
+	var declared map[string]bool
+	if name, ok := calledName(n.Expression.Value); ok {
+		declared = g.templateSlots[name]
+	}
+	children, fills := slotFills(n.Children, declared)
+	childrenName, err := g.writeChildrenComponent(indentLevel, children)
+	if err != nil {
 		return err
 	}
-	indentLevel++
-	if err := g.writeTemplBuffer(indentLevel); err != nil {
+	// map[string]templ.Component{"header": fill}
+	var slots []string
+	for _, fill := range fills {
+		fillName, err := g.writeChildrenComponent(indentLevel, fill.nodes)
+		if err != nil {
+			return err
+		}
+		slots = append(slots, createGoString(fill.name)+": "+fillName)
+	}
+	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
+		return err
+	}
+	if r, err = g.w.Write(n.Expression.Value); err != nil {
 		return err
 	}
-	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), nil); err != nil {
+	g.sourceMap.Add(n.Expression, r)
+	// .Render(templ.WithChildren(ctx, children), templ_7745c5c3_Buffer)
+	ctx := "templ.WithChildren(ctx, " + childrenName + ")"
+	if len(slots) > 0 {
+		ctx = "templ.WithSlots(" + ctx + ", map[string]templ.Component{" + strings.Join(slots, ", ") + "})"
+	}
+	if _, err = g.w.Write(".Render(" + ctx + ", templ_7745c5c3_Buffer)\n"); err != nil {
 		return err
 	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+// slotName returns the name of a <slot name="..."> element, which renders the content passed
+// to the template for that slot.
+func slotName(n parser.Element) (name string, ok bool) {
+	if !strings.EqualFold(n.Name, "slot") {
+		return "", false
+	}
+	attr, ok := n.Attr("name")
+	if !ok {
+		return "", false
+	}
+	ca, ok := attr.(parser.ConstantAttribute)
+	if !ok || ca.Value == "" {
+		return "", false
+	}
+	return ca.Value, true
+}
+
+// declaredSlots adds the names of the slot elements within the nodes to slots. Slots within
+// <template> elements and custom elements, e.g. <sl-card>, are left for the browser, e.g. in
+// declarative shadow DOM, so they aren't included.
+func declaredSlots(nodes []parser.Node, slots map[string]bool) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case parser.Element:
+			if name, ok := slotName(n); ok {
+				slots[name] = true
+			}
+			if !isHTMLSlotContainer(n) {
+				declaredSlots(n.Children, slots)
+			}
+		case parser.TemplElementExpression:
+			declaredSlots(n.Children, slots)
+		case parser.OnceExpression:
+			declaredSlots(n.Children, slots)
+		case parser.FragmentBlock:
+			declaredSlots(n.Children, slots)
+		case parser.IfExpression:
+			declaredSlots(n.Then, slots)
+			for _, elseIf := range n.ElseIfs {
+				declaredSlots(elseIf.Then, slots)
+			}
+			declaredSlots(n.Else, slots)
+		case parser.SwitchExpression:
+			for _, c := range n.Cases {
+				declaredSlots(c.Children, slots)
+			}
+		case parser.ForExpression:
+			declaredSlots(n.Children, slots)
+		}
+	}
+}
+
+// isHTMLSlotContainer returns true if slot elements within the element belong to the browser
+// rather than to the template, i.e. the element is a <template> or a custom element.
+func isHTMLSlotContainer(n parser.Element) bool {
+	return strings.EqualFold(n.Name, "template") || strings.Contains(n.Name, "-")
+}
+
+// templateSlots returns the slots declared by each template in the file, by template name.
+// Templates without slots, and methods, aren't included.
+func templateSlots(tf parser.TemplateFile) map[string]map[string]bool {
+	op := map[string]map[string]bool{}
+	for _, node := range tf.Nodes {
+		t, ok := node.(parser.HTMLTemplate)
+		if !ok {
+			continue
+		}
+		name, ok := calledName(t.Expression.Value)
+		if !ok {
+			continue
+		}
+		slots := map[string]bool{}
+		declaredSlots(t.Children, slots)
+		if len(slots) > 0 {
+			op[name] = slots
+		}
+	}
+	return op
+}
+
+// calledName returns the name of the function in a template declaration or call, e.g. "card"
+// in "card(title)". It returns false if the expression isn't a plain function name, e.g. a
+// method or a function from another package.
+func calledName(expr string) (name string, ok bool) {
+	i := strings.IndexByte(expr, '(')
+	if i < 0 {
+		return "", false
+	}
+	name = strings.TrimSpace(expr[:i])
+	return name, token.IsIdentifier(name)
+}
+
+func (g *generator) writeSlot(indentLevel int, n parser.Element) (err error) {
+	name, _ := slotName(n)
+	fallbackName := "templ.NopComponent"
+	if len(n.Children) > 0 {
+		if fallbackName, err = g.writeChildrenComponent(indentLevel, n.Children); err != nil {
+			return err
+		}
+	}
+	// templ_7745c5c3_Err = templ.Slot(slots, "header", fallback).Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.Slot("+g.slotsVar+", "+createGoString(name)+", "+fallbackName+").Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+type slotFill struct {
+	name  string
+	nodes []parser.Node
+}
+
+// slotFills separates the elements with a slot attribute, e.g. `<h1 slot="header">`, from the
+// children of a template call, grouping them by slot name. Only slots declared by the called
+// template are filled, other elements are left in the children as they are, e.g. for a web
+// component that the template wraps.
+func slotFills(nodes []parser.Node, declared map[string]bool) (children []parser.Node, fills []slotFill) {
+	for _, node := range nodes {
+		e, ok := node.(parser.Element)
+		if !ok {
+			children = append(children, node)
+			continue
+		}
+		attr, ok := e.Attr("slot")
+		ca, isConstant := attr.(parser.ConstantAttribute)
+		if !ok || !isConstant || !declared[ca.Value] {
+			children = append(children, node)
+			continue
+		}
+		i := 0
+		for i < len(fills) && fills[i].name != ca.Value {
+			i++
+		}
+		if i == len(fills) {
+			fills = append(fills, slotFill{name: ca.Value})
+		}
+		fills[i].nodes = append(fills[i].nodes, e)
+	}
+	return children, fills
+}
+
+func (g *generator) writeFragmentBlock(indentLevel int, n parser.FragmentBlock) (err error) {
+	childrenName, err := g.writeChildrenComponent(indentLevel, n.Children)
+	if err != nil {
+		return err
+	}
+	// templ_7745c5c3_Err = templ.Fragment("name").Render(templ.WithChildren(ctx, children), templ_7745c5c3_Buffer)
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.Fragment("+strconv.Quote(n.Name)+").Render(templ.WithChildren(ctx, "+childrenName+"), templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeChildrenComponent writes a templ.ComponentFunc that renders the nodes, and returns the
+// name of the variable it's assigned to.
+func (g *generator) writeChildrenComponent(indentLevel int, nodes []parser.Node) (childrenName string, err error) {
+	childrenName = g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, childrenName+" := templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {\n"); err != nil {
+		return childrenName, err
+	}
+	indentLevel++
+	if err = g.writeTemplBuffer(indentLevel); err != nil {
+		return childrenName, err
+	}
+	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(nodes), nil); err != nil {
+		return childrenName, err
+	}
 	// Return the buffer.
 	if _, err = g.w.WriteIndent(indentLevel, "if !templ_7745c5c3_IsBuffer {\n"); err != nil {
-		return err
+		return childrenName, err
 	}
 	{
 		indentLevel++
 		// _, templ_7745c5c3_Err = io.Copy(templ_7745c5c3_W, templ_7745c5c3_Buffer)
 		if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = io.Copy(templ_7745c5c3_W, templ_7745c5c3_Buffer)\n"); err != nil {
-			return err
+			return childrenName, err
 		}
 		indentLevel--
 	}
 	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
-		return err
+		return childrenName, err
 	}
 	// return nil
 	if _, err = g.w.WriteIndent(indentLevel, "return templ_7745c5c3_Err\n"); err != nil {
-		return err
+		return childrenName, err
 	}
 	indentLevel--
 	if _, err = g.w.WriteIndent(indentLevel, "})\n"); err != nil {
-		return err
+		return childrenName, err
 	}
+	return childrenName, nil
+}
+
+func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
 		return err
 	}
+	// Template expression.
+	var r parser.Range
 	if r, err = g.w.Write(n.Expression.Value); err != nil {
 		return err
 	}
 	g.sourceMap.Add(n.Expression, r)
-	// .Render(templ.WithChildren(ctx, children), templ_7745c5c3_Buffer)
-	if _, err = g.w.Write(".Render(templ.WithChildren(ctx, " + childrenName + "), templ_7745c5c3_Buffer)\n"); err != nil {
+	// .Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
 		return err
 	}
 	if err = g.writeErrorHandler(indentLevel); err != nil {
@@ -758,18 +1095,82 @@ func (g *generator) writeBlockTemplElementExpression(indentLevel int, n parser.T
 	return nil
 }
 
-func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
-	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
+func (g *generator) writeAssetExpression(indentLevel int, n parser.AssetExpression) (err error) {
+	vn := g.createVariableName()
+	// var vn string
+	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
+		return err
+	}
+	// vn, templ_7745c5c3_Err = templ.ResolveAsset(ctx, "images/logo.png")
+	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.ResolveAsset(ctx, "+createGoString(n.Path)+")\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(vn))
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("+vn+"))\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *generator) writeTranslationExpression(indentLevel int, n parser.TranslationExpression) (err error) {
+	vn := g.createVariableName()
+	// var vn string = templ.Translate(ctx, key, args...)
+	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string = templ.Translate(ctx, "); err != nil {
+		return err
+	}
+	for i, e := range append([]parser.Expression{n.Key}, n.Args...) {
+		if i > 0 {
+			if _, err = g.w.Write(", "); err != nil {
+				return err
+			}
+		}
+		var r parser.Range
+		if r, err = g.w.Write(e.Value); err != nil {
+			return err
+		}
+		g.sourceMap.Add(e, r)
+	}
+	if _, err = g.w.Write(")\n"); err != nil {
+		return err
+	}
+	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(vn))
+	value := "templ.EscapeString(" + vn + ")"
+	if g.textMode {
+		value = vn
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("+value+")\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *generator) writeJSONScriptExpression(indentLevel int, n parser.JSONScriptExpression) (err error) {
+	// templ_7745c5c3_Err = templ.JSONScript(id, data).Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = templ.JSONScript(`); err != nil {
 		return err
 	}
-	// Template expression.
 	var r parser.Range
-	if r, err = g.w.Write(n.Expression.Value); err != nil {
+	if r, err = g.w.Write(n.ID.Value); err != nil {
 		return err
 	}
-	g.sourceMap.Add(n.Expression, r)
-	// .Render(ctx, templ_7745c5c3_Buffer)
-	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+	g.sourceMap.Add(n.ID, r)
+	if _, err = g.w.Write(", "); err != nil {
+		return err
+	}
+	if r, err = g.w.Write(n.Data.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(n.Data, r)
+	if _, err = g.w.Write(").Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
 		return err
 	}
 	if err = g.writeErrorHandler(indentLevel); err != nil {
@@ -779,6 +1180,9 @@ func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n pa
 }
 
 func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTemplateExpression) (err error) {
+	if len(n.NamedArgs) > 0 {
+		return g.writeNamedArgsCallTemplateExpression(indentLevel, n)
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
 		return err
 	}
@@ -798,6 +1202,86 @@ func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTe
 	return nil
 }
 
+// writeNamedArgsCallTemplateExpression reorders named arguments to match the parameters of
+// the called template, since Go doesn't support named arguments.
+func (g *generator) writeNamedArgsCallTemplateExpression(indentLevel int, n parser.CallTemplateExpression) (err error) {
+	name := strings.TrimSpace(n.Expression.Value[:strings.Index(n.Expression.Value, "(")])
+	params, ok := g.templateParameterNames(name)
+	if !ok {
+		return fmt.Errorf("%s: named arguments can only be used to call templates defined in the same file", name)
+	}
+	args := make(map[string]parser.Expression, len(n.NamedArgs))
+	for _, arg := range n.NamedArgs {
+		if _, isDuplicate := args[arg.Name]; isDuplicate {
+			return fmt.Errorf("%s: duplicate argument %q", name, arg.Name)
+		}
+		args[arg.Name] = arg.Value
+	}
+	isParam := make(map[string]bool, len(params))
+	for _, param := range params {
+		isParam[param] = true
+	}
+	for _, arg := range n.NamedArgs {
+		if !isParam[arg.Name] {
+			return fmt.Errorf("%s: unknown parameter %q", name, arg.Name)
+		}
+	}
+	// templ_7745c5c3_Err = Template(
+	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `+name+"("); err != nil {
+		return err
+	}
+	for i, param := range params {
+		arg, ok := args[param]
+		if !ok {
+			return fmt.Errorf("%s: missing argument %q", name, param)
+		}
+		if i > 0 {
+			if _, err = g.w.Write(", "); err != nil {
+				return err
+			}
+		}
+		var r parser.Range
+		if r, err = g.w.Write(arg.Value); err != nil {
+			return err
+		}
+		g.sourceMap.Add(arg, r)
+	}
+	// ).Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.Write(").Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+// templateParameterNames returns the parameter names of the named template, if it's defined in
+// the file being generated.
+func (g *generator) templateParameterNames(name string) (params []string, ok bool) {
+	for _, n := range g.tf.Nodes {
+		t, isTemplate := n.(parser.HTMLTemplate)
+		if !isTemplate {
+			continue
+		}
+		f, err := goparser.ParseFile(token.NewFileSet(), "", "package main\nfunc "+t.Expression.Value+" {}", 0)
+		if err != nil || len(f.Decls) == 0 {
+			continue
+		}
+		fn, isFunc := f.Decls[0].(*ast.FuncDecl)
+		if !isFunc || fn.Recv != nil || fn.Name.Name != name {
+			continue
+		}
+		for _, field := range fn.Type.Params.List {
+			for _, n := range field.Names {
+				params = append(params, n.Name)
+			}
+		}
+		return params, true
+	}
+	return nil, false
+}
+
 func (g *generator) writeForExpression(indentLevel int, n parser.ForExpression, next parser.Node) (err error) {
 	var r parser.Range
 	// for
@@ -826,6 +1310,78 @@ func (g *generator) writeForExpression(indentLevel int, n parser.ForExpression,
 	return nil
 }
 
+func (g *generator) writeOnceExpression(indentLevel int, n parser.OnceExpression, next parser.Node) (err error) {
+	// Blocks with the same contents share an ID, so that they're only rendered once, even if
+	// they're in different templates.
+	contents := new(strings.Builder)
+	if err = n.Write(contents, 0); err != nil {
+		return err
+	}
+	h := sha256.Sum256([]byte(contents.String()))
+	id := "once_" + hex.EncodeToString(h[:])[0:16]
+	// if templ.Once(ctx, "once_0123456789abcdef") {
+	if _, err = g.w.WriteIndent(indentLevel, "if templ.Once(ctx, "+strconv.Quote(id)+") {\n"); err != nil {
+		return err
+	}
+	// Children.
+	indentLevel++
+	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), next); err != nil {
+		return err
+	}
+	indentLevel--
+	// }
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *generator) writeConstBlock(indentLevel int, n parser.ConstBlock) (err error) {
+	var r parser.Range
+	// const (
+	if _, err = g.w.WriteIndent(indentLevel, "const (\n"); err != nil {
+		return err
+	}
+	// title = "Home"
+	indentLevel++
+	for _, d := range n.Declarations {
+		if _, err = g.w.WriteIndent(indentLevel, ""); err != nil {
+			return err
+		}
+		if r, err = g.w.Write(d.Value); err != nil {
+			return err
+		}
+		g.sourceMap.Add(d, r)
+		if _, err = g.w.Write("\n"); err != nil {
+			return err
+		}
+	}
+	indentLevel--
+	// )
+	if _, err = g.w.WriteIndent(indentLevel, ")\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *generator) writeGoCode(indentLevel int, n parser.GoCode) (err error) {
+	if strings.TrimSpace(n.Expression.Value) == "" {
+		return nil
+	}
+	var r parser.Range
+	if _, err = g.w.WriteIndent(indentLevel, ""); err != nil {
+		return err
+	}
+	if r, err = g.w.Write(n.Expression.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(n.Expression, r)
+	if _, err = g.w.Write("\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (g *generator) writeErrorHandler(indentLevel int) (err error) {
 	_, err = g.w.WriteIndent(indentLevel, "if templ_7745c5c3_Err != nil {\n")
 	if err != nil {
@@ -845,6 +1401,9 @@ func (g *generator) writeErrorHandler(indentLevel int) (err error) {
 }
 
 func (g *generator) writeElement(indentLevel int, n parser.Element) (err error) {
+	if _, isSlot := slotName(n); isSlot && g.slotsVar != "" && g.htmlSlotDepth == 0 {
+		return g.writeSlot(indentLevel, n)
+	}
 	if n.IsVoidElement() {
 		return g.writeVoidElement(indentLevel, n)
 	}
@@ -912,6 +1471,10 @@ func (g *generator) writeStandardElement(indentLevel int, n parser.Element) (err
 		}
 	}
 	// Children.
+	if isHTMLSlotContainer(n) {
+		g.htmlSlotDepth++
+		defer func() { g.htmlSlotDepth-- }()
+	}
 	if err = g.writeNodes(indentLevel, stripWhitespace(n.Children), nil); err != nil {
 		return err
 	}
@@ -960,6 +1523,65 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAtt
 	return attr, true, nil
 }
 
+// writeClassAttributeCSS writes the classes of a ClassAttribute, using templ.KV for the
+// conditional classes, so that they're handled in the same way as other class expressions.
+func (g *generator) writeClassAttributeCSS(indentLevel int, attr parser.ClassAttribute) (result parser.ExpressionAttribute, err error) {
+	// Without conditional classes, the expression is valid Go, so it's written as it is.
+	if !attr.HasConditionalClasses() && attr.Expression.Value != "" {
+		result, _, err = g.writeAttributeCSS(indentLevel, parser.ExpressionAttribute{Name: attr.Name, Expression: attr.Expression})
+		return result, err
+	}
+	var r parser.Range
+	// var templ_7745c5c3_CSSClasses = []any{
+	classesName := g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, "var "+classesName+" = []any{"); err != nil {
+		return
+	}
+	for i, c := range attr.Classes {
+		if i > 0 {
+			if _, err = g.w.Write(", "); err != nil {
+				return
+			}
+		}
+		if !c.IsConditional() {
+			// "btn"
+			if r, err = g.w.Write(c.Expression.Value); err != nil {
+				return
+			}
+			g.sourceMap.Add(c.Expression, r)
+			continue
+		}
+		// templ.KV("active", isActive)
+		if _, err = g.w.Write("templ.KV(" + strconv.Quote(c.Class) + ", "); err != nil {
+			return
+		}
+		if r, err = g.w.Write(c.Cond.Value); err != nil {
+			return
+		}
+		g.sourceMap.Add(c.Cond, r)
+		if _, err = g.w.Write(")"); err != nil {
+			return
+		}
+	}
+	// }\n
+	if _, err = g.w.Write("}\n"); err != nil {
+		return
+	}
+	// templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_CSSClasses...)
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, "+classesName+"...)\n"); err != nil {
+		return
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return
+	}
+	return parser.ExpressionAttribute{
+		Name: attr.Name,
+		Expression: parser.Expression{
+			Value: "templ.CSSClasses(" + classesName + ").String()",
+		},
+	}, nil
+}
+
 func (g *generator) writeAttributesCSS(indentLevel int, attrs []parser.Attribute) (err error) {
 	for i := 0; i < len(attrs); i++ {
 		if attr, ok := attrs[i].(parser.ExpressionAttribute); ok {
@@ -971,6 +1593,11 @@ func (g *generator) writeAttributesCSS(indentLevel int, attrs []parser.Attribute
 				attrs[i] = attr
 			}
 		}
+		if cattr, ok := attrs[i].(parser.ClassAttribute); ok {
+			if attrs[i], err = g.writeClassAttributeCSS(indentLevel, cattr); err != nil {
+				return err
+			}
+		}
 		if cattr, ok := attrs[i].(parser.ConditionalAttribute); ok {
 			err = g.writeAttributesCSS(indentLevel, cattr.Then)
 			if err != nil {
@@ -1044,7 +1671,24 @@ func (g *generator) writeBoolConstantAttribute(indentLevel int, attr parser.Bool
 	return nil
 }
 
+func isIDAttribute(name string) bool {
+	return strings.EqualFold(name, "id")
+}
+
+// writeRecordID writes a call that records an element id that's about to be rendered, so that
+// duplicate ids can be reported by templ.DetectDuplicateIDs.
+func (g *generator) writeRecordID(indentLevel int, id string, pos parser.Position) (err error) {
+	// templ.RecordID(ctx, id, "template.templ", 1, 2)
+	_, err = g.w.WriteIndent(indentLevel, "templ.RecordID(ctx, "+id+", "+createGoString(g.fileName)+", "+strconv.Itoa(int(pos.Line))+", "+strconv.Itoa(int(pos.Col))+")\n")
+	return err
+}
+
 func (g *generator) writeConstantAttribute(indentLevel int, attr parser.ConstantAttribute) (err error) {
+	if g.recordIDs && isIDAttribute(attr.Name) {
+		if err = g.writeRecordID(indentLevel, createGoString(attr.Value), attr.Range.From); err != nil {
+			return err
+		}
+	}
 	name := html.EscapeString(attr.Name)
 	value := html.EscapeString(attr.Value)
 	value = strings.ReplaceAll(value, "\n", "\\n")
@@ -1139,6 +1783,31 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 			if err = g.writeErrorHandler(indentLevel); err != nil {
 				return err
 			}
+		} else if g.recordIDs && isIDAttribute(attr.Name) {
+			// The id is recorded, so that duplicates can be detected at runtime.
+			vn := g.createVariableName()
+			// var vn string =
+			if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string = "); err != nil {
+				return err
+			}
+			// p.ID
+			var r parser.Range
+			if r, err = g.w.Write(attr.Expression.Value); err != nil {
+				return err
+			}
+			g.sourceMap.Add(attr.Expression, r)
+			if _, err = g.w.Write("\n"); err != nil {
+				return err
+			}
+			if err = g.writeRecordID(indentLevel, vn, attr.Expression.Range.From); err != nil {
+				return err
+			}
+			if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("+vn+"))\n"); err != nil {
+				return err
+			}
+			if err = g.writeErrorHandler(indentLevel); err != nil {
+				return err
+			}
 		} else {
 			// templ_7745c5c3_Buffer.WriteString(templ.EscapeString(
 			if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("); err != nil {
@@ -1236,6 +1905,10 @@ func (g *generator) writeElementAttributes(indentLevel int, name string, attrs [
 			err = g.writeBoolConstantAttribute(indentLevel, attr)
 		case parser.ConstantAttribute:
 			err = g.writeConstantAttribute(indentLevel, attr)
+		case parser.StyleAttribute:
+			err = g.writeConstantAttribute(indentLevel, parser.ConstantAttribute{Name: "style", Value: attr.Value()})
+		case parser.SrcsetAttribute:
+			err = g.writeConstantAttribute(indentLevel, parser.ConstantAttribute{Name: "srcset", Value: attr.Value()})
 		case parser.BoolExpressionAttribute:
 			err = g.writeBoolExpressionAttribute(indentLevel, attr)
 		case parser.ExpressionAttribute:
@@ -1271,8 +1944,21 @@ func (g *generator) writeRawElement(indentLevel int, n parser.RawElement) (err e
 		}
 	}
 	// Contents.
-	if err = g.writeText(indentLevel, parser.Text{Value: n.Contents}); err != nil {
-		return err
+	if n.Parts == nil {
+		if err = g.writeText(indentLevel, parser.Text{Value: n.Contents}); err != nil {
+			return err
+		}
+	}
+	for _, part := range n.Parts {
+		switch part := part.(type) {
+		case parser.Text:
+			err = g.writeText(indentLevel, part)
+		case parser.StringExpression:
+			err = g.writeScriptExpression(indentLevel, part.Expression)
+		}
+		if err != nil {
+			return err
+		}
 	}
 	// </div>
 	if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`</%s>`, html.EscapeString(n.Name))); err != nil {
@@ -1281,6 +1967,46 @@ func (g *generator) writeRawElement(indentLevel int, n parser.RawElement) (err e
 	return err
 }
 
+// writeScriptExpression writes a Go expression interpolated within a <script> element, as JSON.
+func (g *generator) writeScriptExpression(indentLevel int, e parser.Expression) (err error) {
+	var r parser.Range
+	vn := g.createVariableName()
+	// var vn string
+	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
+		return err
+	}
+	// vn, templ_7745c5c3_Err = templ.JSONString(
+	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JSONString("); err != nil {
+		return err
+	}
+	// data
+	if r, err = g.w.Write(e.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(e, r)
+	// )
+	if _, err = g.w.Write(")\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "if templ_7745c5c3_Err != nil {\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel+1, "return templ.Error{Err: templ_7745c5c3_Err, FileName: "+createGoString(g.fileName)+", Line: "+strconv.Itoa(int(e.Range.To.Line))+", Col: "+strconv.Itoa(int(e.Range.To.Col))+"}\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+		return err
+	}
+	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(vn)
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("+vn+")\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (g *generator) writeComment(indentLevel int, c parser.HTMLComment) (err error) {
 	// <!--
 	if _, err = g.w.WriteStringLiteral(indentLevel, "<!--"); err != nil {
@@ -1302,6 +2028,44 @@ func (g *generator) createVariableName() string {
 	return "templ_7745c5c3_Var" + strconv.Itoa(g.variableID)
 }
 
+func (g *generator) writeInlineIfExpression(indentLevel int, n parser.InlineIfExpression) (err error) {
+	var r parser.Range
+	// if
+	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
+		return err
+	}
+	// x == y
+	if r, err = g.w.Write(n.Expression.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(n.Expression, r)
+	// {
+	if _, err = g.w.Write(` {` + "\n"); err != nil {
+		return err
+	}
+	indentLevel++
+	if err = g.writeStringExpression(indentLevel, n.Then); err != nil {
+		return err
+	}
+	indentLevel--
+	if n.Else.Value != "" {
+		// } else {
+		if _, err = g.w.WriteIndent(indentLevel, `} else {`+"\n"); err != nil {
+			return err
+		}
+		indentLevel++
+		if err = g.writeStringExpression(indentLevel, n.Else); err != nil {
+			return err
+		}
+		indentLevel--
+	}
+	// }
+	if _, err = g.w.WriteIndent(indentLevel, `}`+"\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (g *generator) writeStringExpression(indentLevel int, e parser.Expression) (err error) {
 	if strings.TrimSpace(e.Value) == "" {
 		return
@@ -1343,7 +2107,11 @@ func (g *generator) writeStringExpression(indentLevel int, e parser.Expression)
 	}
 
 	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(vn)
-	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("+vn+"))\n"); err != nil {
+	value := "templ.EscapeString(" + vn + ")"
+	if g.textMode {
+		value = vn
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("+value+")\n"); err != nil {
 		return err
 	}
 	if err = g.writeErrorHandler(indentLevel); err != nil {
@@ -1364,7 +2132,7 @@ func (g *generator) writeWhitespace(indentLevel int, n parser.Whitespace) (err e
 }
 
 func (g *generator) writeText(indentLevel int, n parser.Text) (err error) {
-	quoted := strconv.Quote(n.Value)
+	quoted := strconv.Quote(n.HTML())
 	_, err = g.w.WriteStringLiteral(indentLevel, quoted[1:len(quoted)-1])
 	return err
 }
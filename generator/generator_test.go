@@ -2,8 +2,10 @@ package generator
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/a-h/parse"
 	"github.com/a-h/templ/parser/v2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -47,3 +49,145 @@ templ h1() {
 		t.Fatalf("failed to write Go expression: %v", err)
 	}
 }
+
+func TestCheckUnconditionalRecursion(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectError bool
+	}{
+		{
+			name: "templates that don't call themselves are allowed",
+			input: `package main
+
+templ page() {
+	<div>
+		@other()
+	</div>
+}`,
+		},
+		{
+			name: "recursive calls within a for loop are allowed",
+			input: `package main
+
+templ tree(n Node) {
+	for _, c := range n.Children {
+		@tree(c)
+	}
+}`,
+		},
+		{
+			name: "recursive calls within an if expression are allowed",
+			input: `package main
+
+templ tree(n Node) {
+	if n.Next != nil {
+		@tree(*n.Next)
+	}
+}`,
+		},
+		{
+			name: "unconditional recursive calls are an error",
+			input: `package main
+
+templ tree(n Node) {
+	<div>
+		@tree(n)
+	</div>
+}`,
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			var templates int
+			for _, n := range tf.Nodes {
+				ht, ok := n.(parser.HTMLTemplate)
+				if !ok {
+					continue
+				}
+				templates++
+				err = checkUnconditionalRecursion(ht)
+				if tt.expectError && err == nil {
+					t.Error("expected an error, got nil")
+				}
+				if !tt.expectError && err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+			if templates == 0 {
+				t.Fatal("no templates were parsed")
+			}
+		})
+	}
+}
+
+func TestGeneratorTextMode(t *testing.T) {
+	p := parser.NewTemplateFileParser("main")
+	p.TextMode = true
+	tf, _, err := p.Parse(parse.NewInput(`package main
+
+templ query(table string) {
+	SELECT * FROM { table } WHERE a <> 1;
+}
+`))
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	actual := w.String()
+	if strings.Contains(actual, "templ.EscapeString") {
+		t.Error("expected text mode string expressions not to be HTML escaped")
+	}
+	if !strings.Contains(actual, `"SELECT * FROM "`) || !strings.Contains(actual, `" WHERE a <> 1;\n"`) {
+		t.Errorf("expected the text to be written as is, got:\n%s", actual)
+	}
+}
+
+func TestGeneratorRecordIDs(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ page(id string) {
+	<div id="main" class="a"><p id={ id }></p></div>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	t.Run("ids aren't recorded by default", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, _, err = Generate(tf, w); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		actual := w.String()
+		if strings.Contains(actual, "templ.RecordID") {
+			t.Errorf("expected no calls to templ.RecordID, got:\n%s", actual)
+		}
+		if !strings.Contains(actual, `<div id=\"main\" class=\"a\"><p id=\"`) {
+			t.Errorf("expected the constant HTML to be written in one piece, got:\n%s", actual)
+		}
+	})
+	t.Run("ids are recorded at their attribute", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, _, err = Generate(tf, w, WithFileName("page.templ"), WithRecordIDs()); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		actual := w.String()
+		for _, expected := range []string{
+			"templ.RecordID(ctx, `main`, `page.templ`, 3, 6)",
+			", `page.templ`, 3, 34)",
+		} {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("expected %q, got:\n%s", expected, actual)
+			}
+		}
+	})
+}
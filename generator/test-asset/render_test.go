@@ -0,0 +1,45 @@
+package testasset
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+var errAssetNotFound = errors.New("asset not found")
+
+func resolver(path string) (string, error) {
+	if path == "images/logo.png" {
+		return "/static/images/logo.3f2a1b.png", nil
+	}
+	return "", errAssetNotFound
+}
+
+func Test(t *testing.T) {
+	ctx := templ.WithAssetResolver(context.Background(), resolver)
+	diff, err := htmldiff.DiffCtx(ctx, page(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMissingAsset(t *testing.T) {
+	ctx := templ.WithAssetResolver(context.Background(), func(path string) (string, error) {
+		return "", errAssetNotFound
+	})
+	err := page().Render(ctx, io.Discard)
+	if !errors.Is(err, errAssetNotFound) {
+		t.Errorf("expected the resolver error to be returned, got %v", err)
+	}
+}
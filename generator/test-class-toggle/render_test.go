@@ -0,0 +1,44 @@
+package testclasstoggle
+
+import (
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+func Test(t *testing.T) {
+	var tests = []struct {
+		name       string
+		isActive   bool
+		isDisabled bool
+		expected   string
+	}{
+		{
+			name:     "conditional classes are excluded when false",
+			expected: `<button class="btn">Click</button>`,
+		},
+		{
+			name:     "a conditional class is included when true",
+			isActive: true,
+			expected: `<button class="btn active">Click</button>`,
+		},
+		{
+			name:       "all conditional classes can be included",
+			isActive:   true,
+			isDisabled: true,
+			expected:   `<button class="btn active disabled">Click</button>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := htmldiff.Diff(button("Click", tt.isActive, tt.isDisabled), tt.expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package testcontextnested
+
+import (
+	"context"
+	_ "embed"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := page()
+
+	// The value is set once, when the top-level component is rendered, and is read by a component
+	// nested several levels deep, without being passed as a parameter.
+	ctx := context.WithValue(context.Background(), localeKey, "fr-FR")
+
+	diff, err := htmldiff.DiffCtx(ctx, component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
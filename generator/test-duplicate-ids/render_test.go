@@ -0,0 +1,85 @@
+package testduplicateids
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+	"github.com/google/go-cmp/cmp"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	ctx, check := templ.DetectDuplicateIDs(context.Background())
+	component := list([]string{"a", "b", "a"})
+
+	diff, err := htmldiff.DiffCtx(ctx, component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+
+	err = check()
+	var duplicateErr templ.DuplicateIDsError
+	if !errors.As(err, &duplicateErr) {
+		t.Fatalf("expected a DuplicateIDsError, got %v", err)
+	}
+	expectedDuplicates := []templ.DuplicateID{
+		{
+			ID: "a",
+			Locations: []templ.IDLocation{
+				{FileName: "generator/test-duplicate-ids/template.templ", Line: 3, Col: 10},
+				{FileName: "generator/test-duplicate-ids/template.templ", Line: 3, Col: 10},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDuplicates, duplicateErr.Duplicates); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNoDuplicates(t *testing.T) {
+	ctx, check := templ.DetectDuplicateIDs(context.Background())
+	if err := list([]string{"a", "b"}).Render(ctx, new(discard)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := check(); err != nil {
+		t.Errorf("expected no duplicates, got %v", err)
+	}
+}
+
+func TestConstantIDsAreLocatedAtTheAttribute(t *testing.T) {
+	ctx, check := templ.DetectDuplicateIDs(context.Background())
+	for i := 0; i < 2; i++ {
+		if err := list(nil).Render(ctx, new(discard)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var duplicateErr templ.DuplicateIDsError
+	if !errors.As(check(), &duplicateErr) {
+		t.Fatal("expected a DuplicateIDsError")
+	}
+	expectedDuplicates := []templ.DuplicateID{
+		{
+			ID: "items",
+			Locations: []templ.IDLocation{
+				{FileName: "generator/test-duplicate-ids/template.templ", Line: 7, Col: 5},
+				{FileName: "generator/test-duplicate-ids/template.templ", Line: 7, Col: 5},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDuplicates, duplicateErr.Duplicates); diff != "" {
+		t.Error(diff)
+	}
+}
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,47 @@
+package testfragment
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+	"github.com/google/go-cmp/cmp"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := page([]string{"a", "b"})
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRenderFragments(t *testing.T) {
+	t.Run("only the named fragment is rendered", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		err := templ.RenderFragments(context.Background(), w, page([]string{"a", "b"}), "item-list")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<ul><li>a</li><li>b</li></ul>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("missing fragments return an error", func(t *testing.T) {
+		err := templ.RenderFragments(context.Background(), new(bytes.Buffer), page(nil), "missing")
+		if !errors.Is(err, templ.ErrFragmentNotFound) {
+			t.Errorf("expected ErrFragmentNotFound, got %v", err)
+		}
+	})
+}
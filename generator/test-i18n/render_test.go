@@ -0,0 +1,38 @@
+package testi18n
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+var messages = map[string]string{
+	"greeting": "Bonjour, %s",
+	"items":    "%d articles",
+	"home":     "Accueil",
+}
+
+func Test(t *testing.T) {
+	var keys []string
+	ctx := templ.WithTranslator(context.Background(), func(key string, args ...any) string {
+		keys = append(keys, key)
+		return fmt.Sprintf(messages[key], args...)
+	})
+	diff, err := htmldiff.DiffCtx(ctx, greeting("Alice & co", 3), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+	if len(keys) == 0 {
+		t.Error("expected the translator to be called")
+	}
+}
@@ -0,0 +1,38 @@
+package testinlineif
+
+import (
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+func Test(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *User
+		expected string
+	}{
+		{
+			name:     "the guest fallback is used when the user is nil",
+			user:     nil,
+			expected: `<p>Hello, Guest!</p><p>Sign in to continue</p>`,
+		},
+		{
+			name:     "the user's name is used when the user is set",
+			user:     &User{Name: "<Alice>"},
+			expected: `<p>Hello, &lt;Alice&gt;!</p><p></p>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := htmldiff.Diff(greeting(tt.user), tt.expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package testrecursion
+
+type Node struct {
+	Name     string
+	Children []*Node
+}
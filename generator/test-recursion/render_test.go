@@ -0,0 +1,46 @@
+package testrecursion
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	root := &Node{
+		Name: "root",
+		Children: []*Node{
+			{Name: "a", Children: []*Node{{Name: "a1"}}},
+			{Name: "b"},
+		},
+	}
+	ctx := templ.WithMaxRenderDepth(context.Background(), 3)
+
+	diff, err := htmldiff.DiffCtx(ctx, tree(root), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMaxRenderDepthExceeded(t *testing.T) {
+	// The node is its own child, so rendering would never terminate without a limit.
+	cyclic := &Node{Name: "cyclic"}
+	cyclic.Children = []*Node{cyclic}
+	ctx := templ.WithMaxRenderDepth(context.Background(), 10)
+
+	err := tree(cyclic).Render(ctx, io.Discard)
+	if !errors.Is(err, templ.ErrMaxRenderDepthExceeded) {
+		t.Errorf("expected ErrMaxRenderDepthExceeded, got %v", err)
+	}
+}
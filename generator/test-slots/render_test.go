@@ -0,0 +1,62 @@
+package testslots
+
+import (
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+func TestFilledSlot(t *testing.T) {
+	expected := `<div class="card"><header><h2 slot="header">Welcome</h2></header><div class="body"><p>Content</p></div></div>`
+	diff, err := htmldiff.Diff(filled(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestUnfilledSlotUsesFallback(t *testing.T) {
+	expected := `<div class="card"><header><h2>Untitled</h2></header><div class="body"><p>Content</p></div></div>`
+	diff, err := htmldiff.Diff(unfilled(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSlotsInTemplateElementsAreRenderedAsHTML(t *testing.T) {
+	expected := `<my-card><template shadowrootmode="open"><slot name="header"></slot></template><h2 slot="header">Shadow</h2></my-card>`
+	diff, err := htmldiff.Diff(shadow(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSlotAttributesAreKeptForTemplatesWithoutSlots(t *testing.T) {
+	expected := `<sl-card><h2 slot="header">Header</h2><p>Content</p></sl-card>`
+	diff, err := htmldiff.Diff(wrapped(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSlotsInCustomElementsAreRenderedAsHTML(t *testing.T) {
+	expected := `<my-panel><slot name="footer"></slot></my-panel>`
+	diff, err := htmldiff.Diff(lightDOMSlot(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
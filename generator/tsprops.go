@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// GenerateTSProps returns a TypeScript interface declaration describing the parameters of the
+// template, so that client code can be kept in sync with server-rendered components. The
+// interface is named after the template with a "Props" suffix. Go types that don't have a
+// TypeScript equivalent are mapped to unknown.
+func GenerateTSProps(t parser.HTMLTemplate) (string, error) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package main\nfunc "+t.Expression.Value+" {}", 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template parameters: %w", err)
+	}
+	if len(f.Decls) == 0 {
+		return "", fmt.Errorf("failed to parse template parameters: no declaration found")
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		return "", fmt.Errorf("failed to parse template parameters: expected a function declaration")
+	}
+	sb := new(strings.Builder)
+	sb.WriteString("export interface ")
+	sb.WriteString(fn.Name.Name)
+	sb.WriteString("Props {\n")
+	for _, field := range fn.Type.Params.List {
+		tsType := goTypeToTS(field.Type)
+		for _, name := range field.Names {
+			sb.WriteString("  ")
+			sb.WriteString(name.Name)
+			sb.WriteString(": ")
+			sb.WriteString(tsType)
+			sb.WriteString(";\n")
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+func goTypeToTS(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64", "byte", "rune":
+			return "number"
+		}
+	case *ast.ArrayType:
+		return goTypeToTS(e.Elt) + "[]"
+	}
+	return "unknown"
+}
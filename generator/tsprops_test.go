@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateTSProps(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Card(title string, count int, ratio float64, visible bool, tags []string, matrix [][]int, user User) {
+	<div>{ title }</div>
+}`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	actual, err := GenerateTSProps(tf.Nodes[0].(parser.HTMLTemplate))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `export interface CardProps {
+  title: string;
+  count: number;
+  ratio: number;
+  visible: boolean;
+  tags: string[];
+  matrix: number[][];
+  user: unknown;
+}
+`
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/a-h/parse"
+)
+
+var assetExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	// Check the prefix first.
+	if !peekPrefix(pi, "@asset \"", "@asset `") {
+		return n, false, nil
+	}
+	pi.Take(len("@asset "))
+
+	// Parse the path, which is a Go string literal.
+	var r AssetExpression
+	src, _ := pi.Peek(-1)
+	quoted, err := strconv.QuotedPrefix(src)
+	if err != nil {
+		return r, false, parse.Error("@asset: invalid path, expected a string literal", pi.Position())
+	}
+	if r.Path, err = strconv.Unquote(quoted); err != nil {
+		return r, false, parse.Error("@asset: invalid path, expected a string literal", pi.Position())
+	}
+	pi.Take(len(quoted))
+
+	return r, true, nil
+})
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAssetExpressionParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected AssetExpression
+	}{
+		{
+			name:     "asset: double quoted path",
+			input:    `@asset "images/logo.png"`,
+			expected: AssetExpression{Path: "images/logo.png"},
+		},
+		{
+			name:     "asset: raw string path",
+			input:    "@asset `css/site.css`",
+			expected: AssetExpression{Path: "css/site.css"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := assetExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAssetExpressionParserErrors(t *testing.T) {
+	_, _, err := assetExpression.Parse(parse.NewInput(`@asset "images/logo.png`))
+	if err == nil {
+		t.Error("expected an error for an unterminated path")
+	}
+}
+
+func TestAssetExpressionParserDoesNotMatchTemplates(t *testing.T) {
+	input := parse.NewInput(`@assetList()`)
+	_, ok, err := assetExpression.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected no match")
+	}
+}
@@ -0,0 +1,100 @@
+package parser
+
+import "fmt"
+
+// ElementBuilder builds an Element in code, e.g. in tests or code generators, instead of writing
+// out the struct literal. The built element is the same as the element parsed from the
+// equivalent template, apart from source ranges, so the two are Equal.
+//
+//	E("div").Attr("class", "x").Children(E("span").Text("hi")).Build()
+//
+// Each method returns a new builder, so a builder can be reused as the start of several elements.
+type ElementBuilder struct {
+	e Element
+}
+
+// E starts building an element with the given name.
+func E(name string) ElementBuilder {
+	return ElementBuilder{e: Element{Name: name}}
+}
+
+// Attr adds a constant attribute, e.g. `class="x"`.
+func (b ElementBuilder) Attr(name, value string) ElementBuilder {
+	b.e.Attributes = appendAttribute(b.e.Attributes, ConstantAttribute{Name: name, Value: value})
+	return b
+}
+
+// BoolAttr adds a boolean attribute, e.g. `disabled`.
+func (b ElementBuilder) BoolAttr(name string) ElementBuilder {
+	b.e.Attributes = appendAttribute(b.e.Attributes, BoolConstantAttribute{Name: name})
+	return b
+}
+
+// Text adds a text child, see T.
+func (b ElementBuilder) Text(text string) ElementBuilder {
+	return b.Children(T(text))
+}
+
+// Children adds the children to the element. Each child is a Node, or an ElementBuilder, which is
+// built, see Frag.
+func (b ElementBuilder) Children(children ...any) ElementBuilder {
+	nodes := make([]Node, 0, len(b.e.Children)+len(children))
+	nodes = append(nodes, b.e.Children...)
+	b.e.Children = append(nodes, Frag(children...)...)
+	return b
+}
+
+// Build returns the element. Like the parser, it sets the element's Key from its attributes, and
+// marks the elements within <pre> and <textarea> to preserve their whitespace.
+func (b ElementBuilder) Build() Element {
+	e := b.e
+	e.Key = elementKey(e.Attributes)
+	if !e.IsVoidElement() && isWhitespaceSensitive(e.Name) {
+		e = preserveWhitespace(e)
+	}
+	return e
+}
+
+// T returns a text node. The text is HTML, as it would be written in a template, so it isn't
+// encoded, e.g. T("&amp;") is displayed as "&".
+func T(text string) Text {
+	return Text{Value: text}
+}
+
+// Frag returns the nodes as a list, e.g. for the children of a template. Each of nodes is a Node,
+// or an ElementBuilder, which is built, so that the builder itself never ends up in a tree. Frag
+// panics if passed anything else.
+func Frag(nodes ...any) []Node {
+	op := make([]Node, len(nodes))
+	for i, n := range nodes {
+		switch n := n.(type) {
+		case ElementBuilder:
+			op[i] = n.Build()
+		case Node:
+			op[i] = n
+		default:
+			panic(fmt.Sprintf("parser: %T is not a Node or an ElementBuilder", n))
+		}
+	}
+	return op
+}
+
+// appendAttribute adds the attribute to a copy of attrs, so that builders don't share attributes.
+func appendAttribute(attrs []Attribute, attr Attribute) []Attribute {
+	op := make([]Attribute, 0, len(attrs)+1)
+	op = append(op, attrs...)
+	return append(op, attr)
+}
+
+// preserveWhitespace sets PreserveWhitespace on the element, and the elements within it, apart
+// from void elements, as the parser does within whitespace sensitive elements.
+func preserveWhitespace(e Element) Element {
+	e.PreserveWhitespace = true
+	e.Children = mapElements(e.Children, func(e Element) Element {
+		if !e.IsVoidElement() {
+			e.PreserveWhitespace = true
+		}
+		return e
+	})
+	return e
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestElementBuilder(t *testing.T) {
+	t.Run("built elements are equal to parsed elements", func(t *testing.T) {
+		tem, ok, err := template.Parse(parse.NewInput(`templ x() {
+	<div class="x" key="main"><span>hi</span>, <b>there</b><input type="checkbox" checked/><pre><b>a  b</b></pre></div>
+}`))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		parsed := firstElement(t, tem.Children)
+		// The newline before the template's closing brace follows the element.
+		parsed.TrailingSpace = SpaceNone
+
+		built := E("div").Attr("class", "x").Attr("key", "main").Children(
+			E("span").Text("hi"),
+			T(", "),
+			E("b").Text("there"),
+			E("input").Attr("type", "checkbox").BoolAttr("checked"),
+			E("pre").Children(E("b").Text("a  b")),
+		).Build()
+		if !Equal(parsed, built) {
+			t.Errorf("expected the built element to equal the parsed element:\n%s", cmp.Diff(parsed, built))
+		}
+	})
+	t.Run("builders can be reused", func(t *testing.T) {
+		base := E("a").Attr("class", "link")
+		home := base.Attr("href", "/").Build()
+		about := base.Attr("href", "/about").Build()
+		expected := []Attribute{
+			ConstantAttribute{Name: "class", Value: "link"},
+			ConstantAttribute{Name: "href", Value: "/about"},
+		}
+		if diff := cmp.Diff(expected, about.Attributes); diff != "" {
+			t.Error(diff)
+		}
+		if href, _ := home.Attr("href"); href != (ConstantAttribute{Name: "href", Value: "/"}) {
+			t.Errorf("expected the first element to be unchanged, got %v", href)
+		}
+	})
+	t.Run("fragments build the nodes within them", func(t *testing.T) {
+		expected := []Node{
+			Element{Name: "p", Children: []Node{Text{Value: "a"}}},
+			Text{Value: "b"},
+		}
+		if diff := cmp.Diff(expected, Frag(E("p").Text("a"), T("b"))); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("builders aren't nodes, so they can't be left in a tree unbuilt", func(t *testing.T) {
+		if _, ok := any(E("p")).(Node); ok {
+			t.Error("expected the builder not to be a Node")
+		}
+	})
+	t.Run("fragments of other types panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		Frag("text")
+	})
+}
@@ -18,9 +18,12 @@ func (p callTemplateExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, e
 	}
 
 	// Once we have a prefix, we must have an expression that returns a template.
-	var r CallTemplateExpression
-	if r.Expression, err = parseGo("call template expression", pi, goexpression.Expression); err != nil {
-		return
+	// Named arguments aren't valid Go expressions, so they're checked for first.
+	r, hasNamedArgs := parseNamedArgsCall(pi)
+	if !hasNamedArgs {
+		if r.Expression, err = parseGo("call template expression", pi, goexpression.Expression); err != nil {
+			return
+		}
 	}
 
 	// Eat the final brace.
@@ -31,3 +34,22 @@ func (p callTemplateExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, e
 
 	return r, true, nil
 }
+
+// parseNamedArgsCall parses a call such as `Card(title: "Hi", body: content)`.
+func parseNamedArgsCall(pi *parse.Input) (r CallTemplateExpression, ok bool) {
+	from := pi.Index()
+	src, _ := pi.Peek(-1)
+	_, args, end, err := goexpression.NamedArgs(src)
+	if err != nil {
+		return r, false
+	}
+	for _, arg := range args {
+		r.NamedArgs = append(r.NamedArgs, NamedArg{
+			Name:  src[arg.NameStart:arg.NameEnd],
+			Value: NewExpression(src[arg.ValueStart:arg.ValueEnd], pi.PositionAt(from+arg.ValueStart), pi.PositionAt(from+arg.ValueEnd)),
+		})
+	}
+	r.Expression = NewExpression(src[:end], pi.PositionAt(from), pi.PositionAt(from+end))
+	pi.Take(end)
+	return r, true
+}
@@ -34,6 +34,27 @@ func TestCallTemplateExpressionParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "call: multi-line arguments with a trailing comma",
+			input: "{! Card(\n\ta,\n\tb,\n) }",
+			expected: CallTemplateExpression{
+				Expression: Expression{
+					Value: "Card(\n\ta,\n\tb,\n)",
+					Range: Range{
+						From: Position{
+							Index: 3,
+							Line:  0,
+							Col:   3,
+						},
+						To: Position{
+							Index: 18,
+							Line:  3,
+							Col:   1,
+						},
+					},
+				},
+			},
+		},
 		{
 			name:  "call: simple, missing start space",
 			input: `{!Other(p.Test) }`,
@@ -76,6 +97,126 @@ func TestCallTemplateExpressionParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "call: named arguments",
+			input: `{! Card(title: "Hi", body: content) }`,
+			expected: CallTemplateExpression{
+				Expression: Expression{
+					Value: `Card(title: "Hi", body: content)`,
+					Range: Range{
+						From: Position{
+							Index: 3,
+							Line:  0,
+							Col:   3,
+						},
+						To: Position{
+							Index: 35,
+							Line:  0,
+							Col:   35,
+						},
+					},
+				},
+				NamedArgs: []NamedArg{
+					{
+						Name: "title",
+						Value: Expression{
+							Value: `"Hi"`,
+							Range: Range{
+								From: Position{
+									Index: 15,
+									Line:  0,
+									Col:   15,
+								},
+								To: Position{
+									Index: 19,
+									Line:  0,
+									Col:   19,
+								},
+							},
+						},
+					},
+					{
+						Name: "body",
+						Value: Expression{
+							Value: `content`,
+							Range: Range{
+								From: Position{
+									Index: 27,
+									Line:  0,
+									Col:   27,
+								},
+								To: Position{
+									Index: 34,
+									Line:  0,
+									Col:   34,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "call: struct and slice literal arguments",
+			input: `{! Card(User{Name: "x", Tags: []string{"a","b"}}) }`,
+			expected: CallTemplateExpression{
+				Expression: Expression{
+					Value: `Card(User{Name: "x", Tags: []string{"a","b"}})`,
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 49, Line: 0, Col: 49},
+					},
+				},
+			},
+		},
+		{
+			name:  "call: nested calls and commas within literals",
+			input: `{! Card(f(g(a, b), c), "a, b", ',') }`,
+			expected: CallTemplateExpression{
+				Expression: Expression{
+					Value: `Card(f(g(a, b), c), "a, b", ',')`,
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 35, Line: 0, Col: 35},
+					},
+				},
+			},
+		},
+		{
+			name:  "call: named arguments containing commas and brackets",
+			input: `{! Card(title: "a, b)", body: map[string][]int{"a": {1, 2}}) }`,
+			expected: CallTemplateExpression{
+				Expression: Expression{
+					Value: `Card(title: "a, b)", body: map[string][]int{"a": {1, 2}})`,
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 60, Line: 0, Col: 60},
+					},
+				},
+				NamedArgs: []NamedArg{
+					{
+						Name: "title",
+						Value: Expression{
+							Value: `"a, b)"`,
+							Range: Range{
+								From: Position{Index: 15, Line: 0, Col: 15},
+								To:   Position{Index: 22, Line: 0, Col: 22},
+							},
+						},
+					},
+					{
+						Name: "body",
+						Value: Expression{
+							Value: `map[string][]int{"a": {1, 2}}`,
+							Range: Range{
+								From: Position{Index: 30, Line: 0, Col: 30},
+								To:   Position{Index: 59, Line: 0, Col: 59},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
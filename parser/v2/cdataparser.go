@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+var cDataStart = parse.String("<![CDATA[")
+
+// cData parses a CDATA section, e.g. `<![CDATA[ x < y ]]>`, as used in SVG and MathML. The
+// contents aren't parsed, and only `]]>` ends the section.
+var cData = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Position()
+	if _, ok, err = cDataStart.Parse(pi); err != nil || !ok {
+		return
+	}
+
+	src, _ := pi.Peek(-1)
+	end := strings.Index(src, "]]>")
+	if end < 0 {
+		err = parse.Error("expected end of CDATA section ']]>' not found", start)
+		return
+	}
+	var c CData
+	c.Value = src[:end]
+	pi.Take(end + len("]]>"))
+	c.Range = NewRange(start, pi.Position())
+
+	return c, true, nil
+})
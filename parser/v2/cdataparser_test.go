@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCDataParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected CData
+	}{
+		{
+			name:  "cdata: angle brackets are not parsed",
+			input: `<![CDATA[ if (a < b && c > d) { <p>x</p> } ]]>`,
+			expected: CData{
+				Value: ` if (a < b && c > d) { <p>x</p> } `,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 46, Line: 0, Col: 46},
+				},
+			},
+		},
+		{
+			name:  "cdata: only ]]> ends the section",
+			input: `<![CDATA[a > b ]] c ]> d]]>`,
+			expected: CData{
+				Value: `a > b ]] c ]> d`,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 27, Line: 0, Col: 27},
+				},
+			},
+		},
+		{
+			name:  "cdata: empty",
+			input: `<![CDATA[]]>`,
+			expected: CData{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 12, Line: 0, Col: 12},
+				},
+			},
+		},
+		{
+			name: "cdata: multiline",
+			input: `<![CDATA[
+	<data/>
+]]>`,
+			expected: CData{
+				Value: "\n\t<data/>\n",
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 22, Line: 2, Col: 3},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := cData.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCDataParserErrors(t *testing.T) {
+	_, _, err := cData.Parse(parse.NewInput(`<![CDATA[ a > b ]]`))
+	expected := parse.Error("expected end of CDATA section ']]>' not found", parse.Position{Index: 0, Line: 0, Col: 0})
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestCDataWithinElement(t *testing.T) {
+	input := parse.NewInput(`<svg><style><![CDATA[ a > b { fill: red; } ]]></style><![CDATA[<g>]]></svg>`)
+	result, ok, err := element.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse element: %v", err)
+	}
+	svg := result.(Element)
+	if len(svg.Children) != 2 {
+		t.Fatalf("expected 2 children, got %#v", svg.Children)
+	}
+	if _, ok := svg.Children[0].(RawElement); !ok {
+		t.Errorf("expected the <style> contents to be kept raw, got %T", svg.Children[0])
+	}
+	expected := CData{
+		Value: "<g>",
+		Range: Range{
+			From: Position{Index: 54, Line: 0, Col: 54},
+			To:   Position{Index: 69, Line: 0, Col: 69},
+		},
+	}
+	if diff := cmp.Diff(expected, svg.Children[1]); diff != "" {
+		t.Error(diff)
+	}
+}
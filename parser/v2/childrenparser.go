@@ -13,9 +13,13 @@ var childrenExpressionParser = parse.StringFrom(
 )
 
 var childrenExpression = parse.Func(func(in *parse.Input) (n Node, ok bool, err error) {
+	start := in.Index()
+	takeSpaces(in)
+	from := in.Position()
 	_, ok, err = childrenExpressionParser.Parse(in)
 	if err != nil || !ok {
+		in.Seek(start)
 		return
 	}
-	return ChildrenExpression{}, true, nil
+	return ChildrenExpression{Range: NewRange(from, in.Position())}, true, nil
 })
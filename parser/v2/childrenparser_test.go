@@ -14,19 +14,34 @@ func TestChildrenExpressionParser(t *testing.T) {
 		expected ChildrenExpression
 	}{
 		{
-			name:     "standard",
-			input:    `{ children...}`,
-			expected: ChildrenExpression{},
+			name:  "standard",
+			input: `{ children...}`,
+			expected: ChildrenExpression{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 14, Line: 0, Col: 14},
+				},
+			},
 		},
 		{
-			name:     "condensed",
-			input:    `{children...}`,
-			expected: ChildrenExpression{},
+			name:  "condensed",
+			input: `{children...}`,
+			expected: ChildrenExpression{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 13, Line: 0, Col: 13},
+				},
+			},
 		},
 		{
-			name:     "extra spaces",
-			input:    `{  children...  }`,
-			expected: ChildrenExpression{},
+			name:  "extra spaces",
+			input: `{  children...  }`,
+			expected: ChildrenExpression{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 17, Line: 0, Col: 17},
+				},
+			},
 		},
 	}
 	for _, tt := range tests {
@@ -47,6 +62,37 @@ func TestChildrenExpressionParser(t *testing.T) {
 	}
 }
 
+func TestChildrenExpressionIsDistinctFromStringExpression(t *testing.T) {
+	input := parse.NewInput(`templ x() {
+	<div>{ children... }{ "children" }{ children }</div>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	div, ok := tem.Children[1].(Element)
+	if !ok {
+		t.Fatalf("expected an element, got %T", tem.Children[1])
+	}
+	if len(div.Children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(div.Children))
+	}
+	expected := ChildrenExpression{
+		Range: Range{
+			From: Position{Index: 18, Line: 1, Col: 6},
+			To:   Position{Index: 33, Line: 1, Col: 21},
+		},
+	}
+	if diff := cmp.Diff(expected, div.Children[0]); diff != "" {
+		t.Error(diff)
+	}
+	for _, n := range div.Children[1:] {
+		if _, ok := n.(StringExpression); !ok {
+			t.Errorf("expected a string expression, got %T", n)
+		}
+	}
+}
+
 func TestChildrenExpressionParserAllocsOK(t *testing.T) {
 	RunParserAllocTest[Node](t, childrenExpression, true, 2, `{ children... }`)
 }
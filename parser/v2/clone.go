@@ -0,0 +1,70 @@
+package parser
+
+import "reflect"
+
+// Clone returns a deep copy of the node, so that changes to the copy, e.g. to its attributes,
+// children, or the branches of an if expression, don't change the original.
+func Clone(n Node) Node {
+	if n == nil {
+		return nil
+	}
+	return cloneValue(reflect.ValueOf(n)).Interface().(Node)
+}
+
+// CloneNodes returns a deep copy of the nodes, see Clone.
+func CloneNodes(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	return cloneValue(reflect.ValueOf(nodes)).Interface().([]Node)
+}
+
+// cloneValue copies slices, maps and pointers within v, including those within the exported
+// fields of structs. Other values, e.g. strings and ranges, are copied by value.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				c.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return c
+	}
+	return v
+}
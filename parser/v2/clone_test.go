@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClone(t *testing.T) {
+	input := `templ x(items []Item) {
+	<div class="a">
+		if ok {
+			<span>Then</span>
+		} else if other {
+			<span>Else if</span>
+		}
+		for _, item := range items {
+			<p>{ item.Name }</p>
+		}
+		switch x {
+			case 1:
+				<b>One</b>
+		}
+	</div>
+}`
+	tem, ok, err := template.Parse(parse.NewInput(input))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	unchanged, _, _ := template.Parse(parse.NewInput(input))
+	original := unchanged.Children
+
+	cloned := CloneNodes(tem.Children)
+	if diff := cmp.Diff(original, cloned); diff != "" {
+		t.Fatalf("expected the clone to equal the original:\n%s", diff)
+	}
+
+	div := firstElement(t, cloned)
+	div.Attributes[0] = ConstantAttribute{Name: "class", Value: "b"}
+	for _, n := range div.Children {
+		switch n := n.(type) {
+		case IfExpression:
+			n.Then[1] = Text{Value: "changed"}
+			n.ElseIfs[0].Expression.Value = "changed"
+			n.ElseIfs[0].Then[1] = Text{Value: "changed"}
+		case ForExpression:
+			p := n.Children[1].(Element)
+			p.Children[0] = Text{Value: "changed"}
+		case SwitchExpression:
+			n.Cases[0].Children[1] = Text{Value: "changed"}
+		}
+	}
+
+	if diff := cmp.Diff(original, tem.Children); diff != "" {
+		t.Errorf("expected changes to the clone not to change the original:\n%s", diff)
+	}
+}
+
+func TestCloneNil(t *testing.T) {
+	if n := Clone(nil); n != nil {
+		t.Errorf("expected nil, got %#v", n)
+	}
+	if nodes := CloneNodes(nil); nodes != nil {
+		t.Errorf("expected nil, got %#v", nodes)
+	}
+}
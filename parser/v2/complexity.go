@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+)
+
+// ExpressionComplexity returns a rule that reports each Go expression with more than maxNodes
+// syntax tree nodes, or nested more than maxDepth levels deep. Complex expressions are harder to
+// read than a call to a Go function that does the same work. A limit of zero or less is not
+// checked. It isn't one of the DefaultRules, e.g.
+//
+//	Validate(t, append(DefaultRules, ExpressionComplexity(50, 10)))
+func ExpressionComplexity(maxNodes, maxDepth int) Rule {
+	return func(n Node) (errs []ValidationError) {
+		nodeExpressions(n, func(e Expression) {
+			nodes, depth, ok := expressionComplexity(e.Value)
+			if !ok {
+				return
+			}
+			if (maxNodes > 0 && nodes > maxNodes) || (maxDepth > 0 && depth > maxDepth) {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("expression is too complex (%d nodes, nested %d levels deep), consider moving it to a Go function", nodes, depth),
+					Node:    n,
+					Range:   e.Range,
+				})
+			}
+		})
+		return errs
+	}
+}
+
+// nodeExpressions calls fn with each Go expression of the node, including those of its
+// attributes, but not those of the nodes within it.
+func nodeExpressions(node Node, fn func(e Expression)) {
+	switch n := node.(type) {
+	case Element:
+		attributeExpressions(n.Attributes, fn)
+	case RawElement:
+		attributeExpressions(n.Attributes, fn)
+		for _, part := range n.Parts {
+			if se, ok := part.(StringExpression); ok {
+				fn(se.Expression)
+			}
+		}
+	case StringExpression:
+		fn(n.Expression)
+	case InlineIfExpression:
+		fn(n.Expression)
+		fn(n.Then)
+		fn(n.Else)
+	case IfExpression:
+		fn(n.Expression)
+		for _, elseIf := range n.ElseIfs {
+			fn(elseIf.Expression)
+		}
+	case SwitchExpression:
+		fn(n.Expression)
+	case ForExpression:
+		fn(n.Expression)
+	case TemplElementExpression:
+		fn(n.Expression)
+	case TranslationExpression:
+		fn(n.Key)
+		for _, arg := range n.Args {
+			fn(arg)
+		}
+	case JSONScriptExpression:
+		fn(n.ID)
+		fn(n.Data)
+	case CallTemplateExpression:
+		fn(n.Expression)
+	}
+}
+
+// attributeExpressions calls fn with each Go expression of the attributes, including those
+// within conditional attributes.
+func attributeExpressions(attrs []Attribute, fn func(e Expression)) {
+	for _, a := range attrs {
+		switch a := a.(type) {
+		case ExpressionAttribute:
+			fn(a.Expression)
+		case BoolExpressionAttribute:
+			fn(a.Expression)
+		case ClassAttribute:
+			for _, c := range a.Classes {
+				if c.IsConditional() {
+					fn(c.Cond)
+				} else {
+					fn(c.Expression)
+				}
+			}
+		case SpreadAttributes:
+			fn(a.Expression)
+		case ConditionalAttribute:
+			fn(a.Expression)
+			attributeExpressions(a.Then, fn)
+			attributeExpressions(a.Else, fn)
+		}
+	}
+}
+
+// expressionComplexity returns the number of nodes in the syntax tree of the Go expression,
+// and the depth of the tree. ok is false if the expression can't be parsed.
+func expressionComplexity(expr string) (nodes, depth int, ok bool) {
+	if expr == "" {
+		return 0, 0, false
+	}
+	e, err := goparser.ParseExpr(expr)
+	if err != nil {
+		return 0, 0, false
+	}
+	var current int
+	ast.Inspect(e, func(n ast.Node) bool {
+		if n == nil {
+			current--
+			return false
+		}
+		nodes++
+		current++
+		if current > depth {
+			depth = current
+		}
+		return true
+	})
+	return nodes, depth, true
+}
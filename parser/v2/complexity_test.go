@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpressionComplexity(t *testing.T) {
+	input := `package main
+
+templ Name(p Person) {
+	<div class={ p.Class }>{ p.Name }</div>
+	<div>{ strings.ToUpper(strings.TrimSpace(fmt.Sprintf("%s %s", p.First, strings.Join(append([]string{p.Middle}, p.Last), " ")))) }</div>
+}
+`
+	var tests = []struct {
+		name     string
+		maxNodes int
+		maxDepth int
+		expected []string
+	}{
+		{
+			name:     "zero limits aren't checked",
+			expected: nil,
+		},
+		{
+			name:     "simple expressions are within the limit",
+			maxNodes: 100,
+			maxDepth: 20,
+			expected: nil,
+		},
+		{
+			name:     "complex expressions exceed the node limit",
+			maxNodes: 10,
+			expected: []string{`strings.ToUpper(`},
+		},
+		{
+			name:     "deeply nested expressions exceed the depth limit",
+			maxDepth: 5,
+			expected: []string{`strings.ToUpper(`},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := ParseString(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			errs := Validate(tf.Nodes[0].(HTMLTemplate), []Rule{ExpressionComplexity(tt.maxNodes, tt.maxDepth)})
+			if len(errs) != len(tt.expected) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.expected), len(errs), errs)
+			}
+			for i, d := range errs {
+				if !strings.Contains(d.Message, "too complex") {
+					t.Errorf("unexpected message: %q", d.Message)
+				}
+				from := int(d.Range.From.Index)
+				if !strings.HasPrefix(input[from:], tt.expected[i]) {
+					t.Errorf("expected diagnostic at %q, got %q", tt.expected[i], input[from:from+len(tt.expected[i])])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strings"
+)
+
+// ComponentDef describes a templ component declared in a template file.
+type ComponentDef struct {
+	// Name of the component, e.g. `Button`.
+	Name string
+	// Receiver of the component, if it's a method, e.g. `(b Button)`.
+	Receiver string
+	// Parameters of the component.
+	Parameters []ComponentParameter
+	// Doc is the text of the comment directly preceding the component, without the
+	// leading `//` of each line.
+	Doc string
+	// Range of the component's signature within the file.
+	Range Range
+}
+
+// ComponentParameter is a parameter of a templ component.
+type ComponentParameter struct {
+	Name string
+	Type string
+}
+
+// Components returns the definitions of the templ components declared in the file.
+// Components with signatures that can't be parsed as Go are skipped.
+func (tf TemplateFile) Components() (defs []ComponentDef) {
+	for i, n := range tf.Nodes {
+		t, ok := n.(HTMLTemplate)
+		if !ok {
+			continue
+		}
+		def, ok := parseComponentDef(t)
+		if !ok {
+			continue
+		}
+		if i > 0 {
+			if e, isGo := tf.Nodes[i-1].(TemplateFileGoExpression); isGo {
+				def.Doc = trailingComment(e.Expression.Value)
+			}
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+func parseComponentDef(t HTMLTemplate) (def ComponentDef, ok bool) {
+	src := "package main\nfunc " + t.Expression.Value + " {}"
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil || len(f.Decls) == 0 {
+		return def, false
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		return def, false
+	}
+	nodeSource := func(n ast.Node) string {
+		return src[n.Pos()-1 : n.End()-1]
+	}
+	def.Name = fn.Name.Name
+	if fn.Recv != nil {
+		def.Receiver = nodeSource(fn.Recv)
+	}
+	for _, field := range fn.Type.Params.List {
+		typ := nodeSource(field.Type)
+		if len(field.Names) == 0 {
+			def.Parameters = append(def.Parameters, ComponentParameter{Type: typ})
+		}
+		for _, name := range field.Names {
+			def.Parameters = append(def.Parameters, ComponentParameter{Name: name.Name, Type: typ})
+		}
+	}
+	def.Range = t.Expression.Range
+	return def, true
+}
+
+// trailingComment returns the text of the single line comments at the end of the Go code.
+func trailingComment(src string) string {
+	lines := strings.Split(strings.TrimRight(src, " \t\r\n"), "\n")
+	start := len(lines)
+	for start > 0 && strings.HasPrefix(strings.TrimSpace(lines[start-1]), "//") {
+		start--
+	}
+	comment := make([]string, 0, len(lines)-start)
+	for _, line := range lines[start:] {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "//")
+		comment = append(comment, strings.TrimPrefix(line, " "))
+	}
+	return strings.Join(comment, "\n")
+}
+
+// GroupByParamType groups the file's components by the type of their parameter, e.g. to
+// find the templates that render the same props struct. Components that have no parameters,
+// or more than one, are grouped under the empty string.
+func (tf TemplateFile) GroupByParamType() map[string][]ComponentDef {
+	groups := map[string][]ComponentDef{}
+	for _, def := range tf.Components() {
+		var typ string
+		if len(def.Parameters) == 1 {
+			typ = def.Parameters[0].Type
+		}
+		groups[typ] = append(groups[typ], def)
+	}
+	return groups
+}
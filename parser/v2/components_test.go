@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTemplateFileComponents(t *testing.T) {
+	input := `package main
+
+import "fmt"
+
+// Button renders a button.
+// The label is escaped.
+templ Button(label string, count int) {
+	<button>{ label } { fmt.Sprint(count) }</button>
+}
+
+var x = 1
+
+// Card renders a card with items.
+templ (c Card) Render(items []string, attrs templ.Attributes) {
+	<div { attrs... }></div>
+}
+
+templ Undocumented() {
+	<div></div>
+}
+`
+	tf, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	expected := []ComponentDef{
+		{
+			Name: "Button",
+			Parameters: []ComponentParameter{
+				{Name: "label", Type: "string"},
+				{Name: "count", Type: "int"},
+			},
+			Doc: "Button renders a button.\nThe label is escaped.",
+			Range: Range{
+				From: Position{Index: 87, Line: 6, Col: 6},
+				To:   Position{Index: 118, Line: 6, Col: 37},
+			},
+		},
+		{
+			Name:     "Render",
+			Receiver: "(c Card)",
+			Parameters: []ComponentParameter{
+				{Name: "items", Type: "[]string"},
+				{Name: "attrs", Type: "templ.Attributes"},
+			},
+			Doc: "Card renders a card with items.",
+			Range: Range{
+				From: Position{Index: 226, Line: 13, Col: 6},
+				To:   Position{Index: 281, Line: 13, Col: 61},
+			},
+		},
+		{
+			Name: "Undocumented",
+			Range: Range{
+				From: Position{Index: 319, Line: 17, Col: 6},
+				To:   Position{Index: 333, Line: 17, Col: 20},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, tf.Components()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTemplateFileGroupByParamType(t *testing.T) {
+	input := `package main
+
+templ Summary(p Product) {
+	<p>{ p.Name }</p>
+}
+
+templ Detail(product Product) {
+	<h1>{ product.Name }</h1>
+}
+
+templ Header(title string, subtitle string) {
+	<h1>{ title }</h1>
+}
+
+templ Footer() {
+	<footer></footer>
+}
+`
+	tf, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	names := map[string][]string{}
+	for typ, defs := range tf.GroupByParamType() {
+		for _, def := range defs {
+			names[typ] = append(names[typ], def.Name)
+		}
+	}
+	expected := map[string][]string{
+		"Product": {"Summary", "Detail"},
+		"":        {"Header", "Footer"},
+	}
+	if diff := cmp.Diff(expected, names); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -48,11 +48,8 @@ func (_ conditionalAttributeParser) Parse(pi *parse.Input) (r ConditionalAttribu
 	}
 
 	// Read the optional 'Else' Nodes.
-	if r.Else, ok, err = attributeElseExpression.Parse(pi); err != nil {
-		return
-	}
-	if ok && len(r.Else) == 0 {
-		err = parse.Error("attribute if: invalid content or no attributes were found in the else block", pi.Position())
+	// An empty else block is allowed, and has the same effect as no else block.
+	if r.Else, _, err = attributeElseExpression.Parse(pi); err != nil {
 		return
 	}
 
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
+)
+
+var constBlock parse.Parser[Node] = constBlockParser{}
+
+type constBlockParser struct{}
+
+func (_ constBlockParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+	var r ConstBlock
+	start := pi.Index()
+
+	// Strip leading whitespace and look for `const `.
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return r, false, err
+	}
+	if !peekPrefix(pi, "const ", "const(") {
+		pi.Seek(start)
+		return r, false, nil
+	}
+
+	// Parse the Go const declaration. If it's not valid Go, it's text that happens to start
+	// with "const", so let another parser handle it.
+	from := pi.Index()
+	src, _ := pi.Peek(-1)
+	specs, end, err := goexpression.Const(src)
+	if err != nil {
+		pi.Seek(start)
+		return r, false, nil
+	}
+	for _, spec := range specs {
+		r.Declarations = append(r.Declarations, NewExpression(src[spec.Start:spec.End], pi.PositionAt(from+spec.Start), pi.PositionAt(from+spec.End)))
+	}
+	pi.Take(end)
+
+	// Eat the trailing newline.
+	if _, _, err = parse.NewLine.Parse(pi); err != nil {
+		return r, false, err
+	}
+
+	return r, true, nil
+}
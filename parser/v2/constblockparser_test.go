@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConstBlockParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected HTMLTemplate
+	}{
+		{
+			name: "const: block with two constants referenced in a string expression",
+			input: `templ Name() {
+	const (
+		title = "Home"
+		maxItems = 10
+	)
+	<h1>{ title }</h1>
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "Name()",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 12, Line: 0, Col: 12},
+					},
+				},
+				Children: []Node{
+					ConstBlock{
+						Declarations: []Expression{
+							{
+								Value: `title = "Home"`,
+								Range: Range{
+									From: Position{Index: 26, Line: 2, Col: 2},
+									To:   Position{Index: 40, Line: 2, Col: 16},
+								},
+							},
+							{
+								Value: `maxItems = 10`,
+								Range: Range{
+									From: Position{Index: 43, Line: 3, Col: 2},
+									To:   Position{Index: 56, Line: 3, Col: 15},
+								},
+							},
+						},
+					},
+					Whitespace{Value: "\t"},
+					Element{
+						Name: "h1",
+						Children: []Node{
+							StringExpression{
+								Expression: Expression{
+									Value: `title`,
+									Range: Range{
+										From: Position{Index: 67, Line: 5, Col: 7},
+										To:   Position{Index: 72, Line: 5, Col: 12},
+									},
+								},
+								Range: Range{
+									From: Position{
+										Index: 65,
+										Line:  5,
+										Col:   5,
+									},
+									To: Position{
+										Index: 74,
+										Line:  5,
+										Col:   14,
+									},
+								},
+							},
+						},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 61,
+								Line:  5,
+								Col:   1,
+							},
+							To: Position{
+								Index: 79,
+								Line:  5,
+								Col:   19,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "const: single constant",
+			input: `templ Name() {
+	const title = "Home"
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "Name()",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 12, Line: 0, Col: 12},
+					},
+				},
+				Children: []Node{
+					ConstBlock{
+						Declarations: []Expression{
+							{
+								Value: `title = "Home"`,
+								Range: Range{
+									From: Position{Index: 22, Line: 1, Col: 7},
+									To:   Position{Index: 36, Line: 1, Col: 21},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "const: text that isn't a valid declaration is parsed as text",
+			input: `templ Name() {
+	const values can't be changed
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "Name()",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 12, Line: 0, Col: 12},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t"},
+					Text{
+						Value:         "const values can't be changed",
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 16,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 45,
+								Line:  1,
+								Col:   30,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := template.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package parser
+
+// Constructors for nodes, to reduce the boilerplate of building trees in code and tests.
+// Nodes created by these functions don't have source ranges.
+
+// NewElement creates an element with the given attributes and children.
+func NewElement(name string, attrs []Attribute, children ...Node) Element {
+	return Element{
+		Name:       name,
+		Attributes: attrs,
+		Children:   children,
+		Key:        elementKey(attrs),
+	}
+}
+
+// NewText creates a text node.
+func NewText(s string) Text {
+	return Text{
+		Value: s,
+	}
+}
+
+// NewExpressionValue creates a Go expression without a source range.
+func NewExpressionValue(value string) Expression {
+	return Expression{
+		Value: value,
+	}
+}
+
+// NewStringExpression creates a string expression, e.g. `{ p.Name }`.
+func NewStringExpression(value string) StringExpression {
+	return StringExpression{
+		Expression: NewExpressionValue(value),
+	}
+}
+
+// NewConstantAttribute creates an attribute with a constant value, e.g. `href="/"`.
+func NewConstantAttribute(name, value string) ConstantAttribute {
+	return ConstantAttribute{
+		Name:  name,
+		Value: value,
+	}
+}
+
+// NewBoolConstantAttribute creates a boolean attribute with no value, e.g. `disabled`.
+func NewBoolConstantAttribute(name string) BoolConstantAttribute {
+	return BoolConstantAttribute{
+		Name: name,
+	}
+}
+
+// NewExpressionAttribute creates an attribute with a Go expression value, e.g. `href={ p.URL }`.
+func NewExpressionAttribute(name, value string) ExpressionAttribute {
+	return ExpressionAttribute{
+		Name:       name,
+		Expression: NewExpressionValue(value),
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestConstructors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		actual   Node
+		expected Node
+	}{
+		{
+			name:   "text",
+			actual: NewText("Hello"),
+			expected: Text{
+				Value: "Hello",
+			},
+		},
+		{
+			name:   "string expression",
+			actual: NewStringExpression("p.Name"),
+			expected: StringExpression{
+				Expression: Expression{
+					Value: "p.Name",
+				},
+			},
+		},
+		{
+			name: "nested elements",
+			actual: NewElement("div", []Attribute{NewConstantAttribute("class", "a"), NewBoolConstantAttribute("hidden")},
+				NewElement("a", []Attribute{NewExpressionAttribute("href", "p.URL")},
+					NewText("Link"),
+				),
+			),
+			expected: Element{
+				Name: "div",
+				Attributes: []Attribute{
+					ConstantAttribute{Name: "class", Value: "a"},
+					BoolConstantAttribute{Name: "hidden"},
+				},
+				Children: []Node{
+					Element{
+						Name: "a",
+						Attributes: []Attribute{
+							ExpressionAttribute{
+								Name: "href",
+								Expression: Expression{
+									Value: "p.URL",
+								},
+							},
+						},
+						Children: []Node{
+							Text{Value: "Link"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, tt.actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestConstructorsMatchParsedNodesIgnoringRanges(t *testing.T) {
+	input := parse.NewInput(`<a href={ p.URL }>{ p.Name }</a>`)
+	actual, ok, err := element.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+	expected := NewElement("a", []Attribute{NewExpressionAttribute("href", "p.URL")},
+		NewStringExpression("p.Name"),
+	)
+	if diff := cmp.Diff(expected, actual, cmpopts.IgnoreTypes(Range{})); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -83,7 +83,7 @@ var cssExpressionParser = parse.Func(func(pi *parse.Input) (r cssExpression, ok
 	}
 
 	// Eat " {\n".
-	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+	if _, ok, err = openBraceAndNewLine.Parse(pi); err != nil || !ok {
 		err = parse.Error("css expression: parameters missing open bracket", pi.PositionAt(start))
 		return
 	}
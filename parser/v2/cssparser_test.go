@@ -34,6 +34,18 @@ func TestExpressionCSSPropertyParser(t *testing.T) {
 							},
 						},
 					},
+					Range: Range{
+						From: Position{
+							Index: 18,
+							Line:  0,
+							Col:   18,
+						},
+						To: Position{
+							Index: 47,
+							Line:  0,
+							Col:   47,
+						},
+					},
 				},
 			},
 		},
@@ -58,6 +70,18 @@ func TestExpressionCSSPropertyParser(t *testing.T) {
 							},
 						},
 					},
+					Range: Range{
+						From: Position{
+							Index: 19,
+							Line:  1,
+							Col:   0,
+						},
+						To: Position{
+							Index: 48,
+							Line:  1,
+							Col:   29,
+						},
+					},
 				},
 			},
 		},
@@ -246,6 +270,18 @@ background-color: { constants.BackgroundColor };
 									},
 								},
 							},
+							Range: Range{
+								From: Position{
+									Index: 31,
+									Line:  1,
+									Col:   18,
+								},
+								To: Position{
+									Index: 60,
+									Line:  1,
+									Col:   47,
+								},
+							},
 						},
 					},
 				},
@@ -292,6 +328,18 @@ background-color: { prop };
 									},
 								},
 							},
+							Range: Range{
+								From: Position{
+									Index: 42,
+									Line:  1,
+									Col:   18,
+								},
+								To: Position{
+									Index: 50,
+									Line:  1,
+									Col:   26,
+								},
+							},
 						},
 					},
 				},
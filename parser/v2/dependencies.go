@@ -0,0 +1,288 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ComponentRef is a call to a component from within a template, e.g. `@Button("OK")` or
+// `@components.Button("OK")`.
+type ComponentRef struct {
+	// Package is the package qualifier of the component, e.g. `components`, or empty if the
+	// component is called without one.
+	Package string
+	// Name of the component, e.g. `Button`.
+	Name string
+	// Range of the call expression within the file.
+	Range Range
+}
+
+// Dependencies returns the components called by the template, in the order they're called.
+// Only calls to functions are included, since the component held by a variable or returned
+// by a method can't be known until the template is rendered.
+func (t HTMLTemplate) Dependencies() (refs []ComponentRef) {
+	add := func(e Expression) {
+		if ref, ok := parseComponentRef(e); ok {
+			refs = append(refs, ref)
+		}
+	}
+	Walk(t.Children, func(n Node) bool {
+		switch n := n.(type) {
+		case CallTemplateExpression:
+			add(n.Expression)
+		case TemplElementExpression:
+			add(n.Expression)
+		}
+		return true
+	})
+	return refs
+}
+
+func parseComponentRef(e Expression) (ref ComponentRef, ok bool) {
+	expr, err := goparser.ParseExpr(e.Value)
+	if err != nil {
+		return ref, false
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return ref, false
+	}
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		ref.Name = fn.Name
+	case *ast.SelectorExpr:
+		pkg, ok := fn.X.(*ast.Ident)
+		if !ok {
+			return ref, false
+		}
+		ref.Package, ref.Name = pkg.Name, fn.Sel.Name
+	default:
+		return ref, false
+	}
+	ref.Range = e.Range
+	return ref, true
+}
+
+// ComponentID identifies a component by the file that declares it and its name.
+type ComponentID struct {
+	FileName string
+	Name     string
+}
+
+func (id ComponentID) String() string {
+	return id.FileName + ":" + id.Name
+}
+
+// DependencyGraph is the graph of calls between the components of a set of template files,
+// see BuildDependencyGraph.
+type DependencyGraph struct {
+	// Dependencies lists the components called by each component, sorted by file name and
+	// component name. Every component in the files has an entry.
+	Dependencies map[ComponentID][]ComponentID
+	// Order lists every component after the components that it depends on, e.g. to
+	// determine the order to regenerate them in.
+	Order []ComponentID
+}
+
+// DependencyCycleError is returned by BuildDependencyGraph when components call each other
+// in a cycle.
+type DependencyCycleError struct {
+	// Cycle lists the components in the cycle, starting and ending with the same component.
+	Cycle []ComponentID
+}
+
+func (e DependencyCycleError) Error() string {
+	names := make([]string, len(e.Cycle))
+	for i, id := range e.Cycle {
+		names[i] = id.String()
+	}
+	return "dependency cycle: " + strings.Join(names, " -> ")
+}
+
+// BuildDependencyGraph resolves the component calls in the template files, keyed by file
+// name, to the components that they call.
+//
+// Calls without a package qualifier are resolved to components declared in files within the
+// same directory, since they're in the same package. Qualified calls, e.g.
+// `@components.Button()`, are resolved using the file's imports to files within a directory
+// that matches the end of the import path, e.g. `github.com/a/app/components` matches files in
+// `components`. Calls that can't be resolved, e.g. to components that aren't in the files or to
+// Go functions, are ignored. Components that are methods can't be resolved, so they're not
+// included.
+//
+// If components call each other in a cycle, a DependencyCycleError is returned. A component
+// that calls itself is a cycle.
+func BuildDependencyGraph(files map[string]TemplateFile) (*DependencyGraph, error) {
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	// Index the components by directory and name.
+	type dirComponent struct {
+		dir, name string
+	}
+	declarations := map[dirComponent]ComponentID{}
+	var declarationOrder []dirComponent
+	for _, fileName := range fileNames {
+		dir := path.Dir(filepath.ToSlash(fileName))
+		for _, def := range files[fileName].Components() {
+			if def.Receiver != "" {
+				continue
+			}
+			key := dirComponent{dir: dir, name: def.Name}
+			if existing, isDuplicate := declarations[key]; isDuplicate {
+				return nil, fmt.Errorf("%s: component %q is also declared in %s", fileName, def.Name, existing.FileName)
+			}
+			declarations[key] = ComponentID{FileName: fileName, Name: def.Name}
+			declarationOrder = append(declarationOrder, key)
+		}
+	}
+	resolveDir := func(importPath, name string) (id ComponentID, ok bool) {
+		for _, key := range declarationOrder {
+			if key.name == name && (key.dir == importPath || strings.HasSuffix(importPath, "/"+key.dir) || strings.HasSuffix(key.dir, "/"+importPath)) {
+				return declarations[key], true
+			}
+		}
+		return id, false
+	}
+
+	g := &DependencyGraph{Dependencies: map[ComponentID][]ComponentID{}}
+	for _, fileName := range fileNames {
+		tf := files[fileName]
+		dir := path.Dir(filepath.ToSlash(fileName))
+		imports := tf.imports()
+		for _, n := range tf.Nodes {
+			t, ok := n.(HTMLTemplate)
+			if !ok {
+				continue
+			}
+			def, ok := parseComponentDef(t)
+			if !ok || def.Receiver != "" {
+				continue
+			}
+			from := ComponentID{FileName: fileName, Name: def.Name}
+			deps := []ComponentID{}
+			seen := map[ComponentID]struct{}{}
+			for _, ref := range t.Dependencies() {
+				var to ComponentID
+				if ref.Package == "" {
+					to, ok = declarations[dirComponent{dir: dir, name: ref.Name}]
+				} else if importPath, isImported := imports[ref.Package]; isImported {
+					to, ok = resolveDir(importPath, ref.Name)
+				} else {
+					ok = false
+				}
+				if _, isSeen := seen[to]; !ok || isSeen {
+					continue
+				}
+				seen[to] = struct{}{}
+				deps = append(deps, to)
+			}
+			sort.Slice(deps, func(i, j int) bool { return deps[i].String() < deps[j].String() })
+			g.Dependencies[from] = deps
+		}
+	}
+	if err := g.sort(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// sort populates the Order of the graph, returning an error if there's a cycle.
+func (g *DependencyGraph) sort() error {
+	ids := make([]ComponentID, 0, len(g.Dependencies))
+	for id := range g.Dependencies {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[ComponentID]int, len(ids))
+	var stack []ComponentID
+	var visit func(id ComponentID) error
+	visit = func(id ComponentID) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			// Find where the cycle starts on the stack.
+			for i := range stack {
+				if stack[i] == id {
+					return DependencyCycleError{Cycle: append(append([]ComponentID{}, stack[i:]...), id)}
+				}
+			}
+		}
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range g.Dependencies[id] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+		g.Order = append(g.Order, id)
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Imports returns the import paths of the file, in the order they're declared.
+func (tf TemplateFile) Imports() (paths []string) {
+	for _, spec := range tf.importSpecs() {
+		if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+			paths = append(paths, importPath)
+		}
+	}
+	return paths
+}
+
+// imports returns the import paths of the file, keyed by the name they're referred to by.
+func (tf TemplateFile) imports() map[string]string {
+	imports := map[string]string{}
+	for _, spec := range tf.importSpecs() {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+func (tf TemplateFile) importSpecs() (specs []*ast.ImportSpec) {
+	for _, n := range tf.Nodes {
+		e, ok := n.(TemplateFileGoExpression)
+		if !ok {
+			continue
+		}
+		f, _ := goparser.ParseFile(token.NewFileSet(), "", "package main\n"+e.Expression.Value, goparser.ImportsOnly)
+		if f == nil {
+			continue
+		}
+		specs = append(specs, f.Imports...)
+	}
+	return specs
+}
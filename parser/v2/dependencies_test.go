@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDependencies(t *testing.T) {
+	tf, err := ParseString(`package main
+
+templ Page(items []string) {
+	@layout.Base("Items") {
+		for _, item := range items {
+			@Item(item)
+		}
+		{! footer() }
+		@child
+		@templ.Raw("<hr>")
+	}
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var refs []string
+	for _, ref := range tf.Nodes[0].(HTMLTemplate).Dependencies() {
+		refs = append(refs, ref.Package+"."+ref.Name)
+	}
+	if diff := cmp.Diff([]string{"layout.Base", ".Item", ".footer", "templ.Raw"}, refs); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func parseFiles(t *testing.T, sources map[string]string) map[string]TemplateFile {
+	t.Helper()
+	files := make(map[string]TemplateFile, len(sources))
+	for fileName, src := range sources {
+		tf, err := ParseString(src)
+		if err != nil {
+			t.Fatalf("%s: failed to parse template: %v", fileName, err)
+		}
+		files[fileName] = tf
+	}
+	return files
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"pages/home.templ": `package pages
+
+import "github.com/a-h/app/components"
+
+templ Home() {
+	@Header()
+	@components.Card("Welcome")
+}
+
+templ Header() {
+	<h1>Home</h1>
+}
+`,
+		"components/card.templ": `package components
+
+templ Card(title string) {
+	<div>
+		@Title(title)
+	</div>
+}
+`,
+		"components/title.templ": `package components
+
+templ Title(title string) {
+	<h2>{ title }</h2>
+	@templ.Raw("<hr>")
+}
+`,
+	})
+	g, err := BuildDependencyGraph(files)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	home := ComponentID{FileName: "pages/home.templ", Name: "Home"}
+	header := ComponentID{FileName: "pages/home.templ", Name: "Header"}
+	card := ComponentID{FileName: "components/card.templ", Name: "Card"}
+	title := ComponentID{FileName: "components/title.templ", Name: "Title"}
+	expected := map[ComponentID][]ComponentID{
+		home:   {card, header},
+		header: {},
+		card:   {title},
+		title:  {},
+	}
+	if diff := cmp.Diff(expected, g.Dependencies); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]ComponentID{title, card, header, home}, g.Order); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestBuildDependencyGraphCycle(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"a.templ": `package main
+
+templ A() {
+	@B()
+}
+`,
+		"b.templ": `package main
+
+templ B() {
+	@C()
+}
+`,
+		"c.templ": `package main
+
+templ C() {
+	if true {
+		@A()
+	}
+}
+`,
+	})
+	_, err := BuildDependencyGraph(files)
+	var cycleErr DependencyCycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected a DependencyCycleError, got %v", err)
+	}
+	expected := []ComponentID{
+		{FileName: "a.templ", Name: "A"},
+		{FileName: "b.templ", Name: "B"},
+		{FileName: "c.templ", Name: "C"},
+		{FileName: "a.templ", Name: "A"},
+	}
+	if diff := cmp.Diff(expected, cycleErr.Cycle); diff != "" {
+		t.Error(diff)
+	}
+	if err.Error() != "dependency cycle: a.templ:A -> b.templ:B -> c.templ:C -> a.templ:A" {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+)
+
+// PatchOp is the type of change made by a Patch.
+type PatchOp int
+
+const (
+	// PatchInsert inserts the Node at the Path.
+	PatchInsert PatchOp = iota
+	// PatchRemove removes the node at the Path.
+	PatchRemove
+	// PatchReplace replaces the node at the Path with the Node.
+	PatchReplace
+	// PatchSetText sets the Text of the text node at the Path.
+	PatchSetText
+	// PatchSetAttribute sets the Attribute of the element at the Path, replacing any
+	// attribute with the same name, or adding it after the existing attributes.
+	PatchSetAttribute
+	// PatchRemoveAttribute removes the attribute with the Name from the element at the Path.
+	PatchRemoveAttribute
+	// PatchSetAttributes replaces all of the attributes of the element at the Path with the
+	// Attributes. It's used when attributes without a name, e.g. conditional or spread
+	// attributes, have changed.
+	PatchSetAttributes
+)
+
+func (op PatchOp) String() string {
+	switch op {
+	case PatchInsert:
+		return "insert"
+	case PatchRemove:
+		return "remove"
+	case PatchReplace:
+		return "replace"
+	case PatchSetText:
+		return "set text"
+	case PatchSetAttribute:
+		return "set attribute"
+	case PatchRemoveAttribute:
+		return "remove attribute"
+	case PatchSetAttributes:
+		return "set attributes"
+	}
+	return "unknown"
+}
+
+// Patch is a change to a list of nodes, see Diff.
+type Patch struct {
+	Op PatchOp
+	// Path is the index of the node within each list of nodes, starting from the root, e.g.
+	// []int{2, 0} is the first child of the third node.
+	Path []int
+	// Node is the node to insert, or replace the existing node with.
+	Node Node
+	// Text is the new value of a text node.
+	Text string
+	// Name is the name of the attribute to remove.
+	Name string
+	// Attribute is the attribute to set.
+	Attribute Attribute
+	// Attributes are the new attributes of the element.
+	Attributes []Attribute
+}
+
+// Diff returns the patches that change the old nodes into the new nodes. The patches are
+// applied in order, and each path refers to the nodes as they are after the patches before it
+// have been applied.
+//
+// Siblings are matched by type, and elements also by name and key, or id, using the longest common
+// subsequence, so that reordering siblings, or inserting one, doesn't change all of the nodes
+// after it. Matched elements are compared attribute by attribute, and child by child, while
+// other matched nodes are replaced if they're different. Nodes are compared as they are by
+// Equal, so source ranges and layout metadata such as quote styles are ignored, and parsing
+// the same template twice produces no patches.
+func Diff(old, new []Node) []Patch {
+	var d differ
+	d.nodes(nil, old, new)
+	return d.patches
+}
+
+type differ struct {
+	patches []Patch
+}
+
+func (d *differ) add(p Patch) {
+	d.patches = append(d.patches, p)
+}
+
+func (d *differ) nodes(parent []int, old, new []Node) {
+	matches := matchNodes(old, new)
+	var index, o, n int
+	for _, m := range append(matches, [2]int{len(old), len(new)}) {
+		for ; o < m[0]; o++ {
+			d.add(Patch{Op: PatchRemove, Path: childPath(parent, index)})
+		}
+		for ; n < m[1]; n++ {
+			d.add(Patch{Op: PatchInsert, Path: childPath(parent, index), Node: new[n]})
+			index++
+		}
+		if o < len(old) && n < len(new) {
+			d.node(childPath(parent, index), old[o], new[n])
+			o, n, index = o+1, n+1, index+1
+		}
+	}
+}
+
+func (d *differ) node(path []int, old, new Node) {
+	switch n := new.(type) {
+	case Element:
+		o := old.(Element)
+		oe, ne := o, n
+		oe.Attributes, oe.Children, ne.Attributes, ne.Children = nil, nil, nil, nil
+		if !equalValues(reflect.ValueOf(oe), reflect.ValueOf(ne)) {
+			d.add(Patch{Op: PatchReplace, Path: path, Node: new})
+			return
+		}
+		d.attributes(path, o.Attributes, n.Attributes)
+		d.nodes(path, o.Children, n.Children)
+		return
+	case Text:
+		o := old.(Text)
+		if !equalValues(reflect.ValueOf(o.TrailingSpace), reflect.ValueOf(n.TrailingSpace)) {
+			d.add(Patch{Op: PatchReplace, Path: path, Node: new})
+			return
+		}
+		if o.Value != n.Value {
+			d.add(Patch{Op: PatchSetText, Path: path, Text: n.Value})
+		}
+		return
+	}
+	if !equalValues(reflect.ValueOf(old), reflect.ValueOf(new)) {
+		d.add(Patch{Op: PatchReplace, Path: path, Node: new})
+	}
+}
+
+func (d *differ) attributes(path []int, old, new []Attribute) {
+	if equalValues(reflect.ValueOf(old), reflect.ValueOf(new)) {
+		return
+	}
+	oldByName, oldOK := attributesByName(old)
+	newByName, newOK := attributesByName(new)
+	if !oldOK || !newOK {
+		d.add(Patch{Op: PatchSetAttributes, Path: path, Attributes: new})
+		return
+	}
+	for _, a := range old {
+		name := attributeName(a)
+		if _, ok := newByName[name]; !ok {
+			d.add(Patch{Op: PatchRemoveAttribute, Path: path, Name: name})
+		}
+	}
+	for _, a := range new {
+		existing, ok := oldByName[attributeName(a)]
+		if ok && equalValues(reflect.ValueOf(existing), reflect.ValueOf(a)) {
+			continue
+		}
+		d.add(Patch{Op: PatchSetAttribute, Path: path, Attribute: a})
+	}
+}
+
+// attributesByName returns false if any of the attributes don't have a name, or if there are
+// duplicate names.
+func attributesByName(attrs []Attribute) (m map[string]Attribute, ok bool) {
+	m = make(map[string]Attribute, len(attrs))
+	for _, a := range attrs {
+		name := attributeName(a)
+		if name == "" {
+			return nil, false
+		}
+		if _, isDuplicate := m[name]; isDuplicate {
+			return nil, false
+		}
+		m[name] = a
+	}
+	return m, true
+}
+
+func childPath(parent []int, index int) []int {
+	return append(append(make([]int, 0, len(parent)+1), parent...), index)
+}
+
+// matchNodes returns the indices of pairs of matching old and new nodes, using the longest
+// common subsequence of their keys.
+func matchNodes(old, new []Node) (matches [][2]int) {
+	oldKeys, newKeys := make([]string, len(old)), make([]string, len(new))
+	for i, n := range old {
+		oldKeys[i] = nodeKey(n)
+	}
+	for i, n := range new {
+		newKeys[i] = nodeKey(n)
+	}
+	// lengths[i][j] is the length of the longest common subsequence of oldKeys[i:] and newKeys[j:].
+	lengths := make([][]int, len(old)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			switch {
+			case oldKeys[i] == newKeys[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(old) && j < len(new); {
+		switch {
+		case oldKeys[i] == newKeys[j]:
+			matches = append(matches, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
+
+// nodeKey identifies nodes that can be patched into each other.
+func nodeKey(n Node) string {
+	name := reflect.TypeOf(n).Name()
+	if e, ok := n.(Element); ok {
+		name += ":" + e.Name
+		if e.Key != "" {
+			return name + "=" + e.Key
+		}
+		for _, a := range e.Attributes {
+			if ca, ok := a.(ConstantAttribute); ok && strings.EqualFold(ca.Name, "id") {
+				name += "#" + ca.Value
+			}
+		}
+	}
+	return name
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	var tests = []struct {
+		name     string
+		old      string
+		new      string
+		expected []Patch
+	}{
+		{
+			name:     "identical templates produce no patches",
+			old:      "templ x() {\n\t<div class=\"a\"><p>Text</p></div>\n}",
+			new:      "templ x() {\n\t<div class=\"a\"><p>Text</p></div>\n}",
+			expected: nil,
+		},
+		{
+			name:     "differences in quote style produce no patches",
+			old:      "templ x() {\n\t<div class=\"a\"><p>Text</p></div>\n}",
+			new:      "templ x() {\n\t<div class='a'><p>Text</p></div>\n}",
+			expected: nil,
+		},
+		{
+			name: "changing an attribute produces a single attribute patch",
+			old:  "templ x() {\n\t<div class=\"a\"><p>Text</p></div>\n}",
+			new:  "templ x() {\n\t<div class=\"b\"><p>Text</p></div>\n}",
+			expected: []Patch{
+				{Op: PatchSetAttribute, Path: []int{1}, Attribute: ConstantAttribute{Name: "class", Value: "b"}},
+			},
+		},
+		{
+			name: "removing an attribute",
+			old:  "templ x() {\n\t<div class=\"a\" title=\"t\"></div>\n}",
+			new:  "templ x() {\n\t<div class=\"a\"></div>\n}",
+			expected: []Patch{
+				{Op: PatchRemoveAttribute, Path: []int{1}, Name: "title"},
+			},
+		},
+		{
+			name: "appending a child produces a single insert",
+			old:  "templ x() {\n\t<ul><li>A</li><li>B</li></ul>\n}",
+			new:  "templ x() {\n\t<ul><li>A</li><li>B</li><li>C</li></ul>\n}",
+			expected: []Patch{
+				{Op: PatchInsert, Path: []int{1, 2}, Node: Element{Name: "li", Children: []Node{Text{Value: "C"}}}},
+			},
+		},
+		{
+			name: "inserting a child at the start doesn't change the siblings after it",
+			old:  "templ x() {\n\t<div><p>A</p><span>B</span></div>\n}",
+			new:  "templ x() {\n\t<div><h1>Title</h1><p>A</p><span>B</span></div>\n}",
+			expected: []Patch{
+				{Op: PatchInsert, Path: []int{1, 0}, Node: Element{Name: "h1", Children: []Node{Text{Value: "Title"}}}},
+			},
+		},
+		{
+			name: "removing a child",
+			old:  "templ x() {\n\t<div><p>A</p><span>B</span></div>\n}",
+			new:  "templ x() {\n\t<div><span>B</span></div>\n}",
+			expected: []Patch{
+				{Op: PatchRemove, Path: []int{1, 0}},
+			},
+		},
+		{
+			name: "changing text",
+			old:  "templ x() {\n\t<p>Old</p>\n}",
+			new:  "templ x() {\n\t<p>New</p>\n}",
+			expected: []Patch{
+				{Op: PatchSetText, Path: []int{1, 0}, Text: "New"},
+			},
+		},
+		{
+			name: "swapping siblings moves one of them",
+			old:  "templ x() {\n\t<div><p>A</p><span>B</span></div>\n}",
+			new:  "templ x() {\n\t<div><span>B</span><p>A</p></div>\n}",
+			expected: []Patch{
+				{Op: PatchRemove, Path: []int{1, 0}},
+				{Op: PatchInsert, Path: []int{1, 1}, Node: Element{Name: "p", Children: []Node{Text{Value: "A"}}}},
+			},
+		},
+		{
+			name: "elements with different ids aren't patched into each other",
+			old:  "templ x() {\n\t<div id=\"a\"></div>\n}",
+			new:  "templ x() {\n\t<div id=\"b\"></div>\n}",
+			expected: []Patch{
+				{Op: PatchRemove, Path: []int{1}},
+				{Op: PatchInsert, Path: []int{1}, Node: Element{Name: "div", Attributes: []Attribute{ConstantAttribute{Name: "id", Value: "b"}}, TrailingSpace: SpaceVertical}},
+			},
+		},
+		{
+			name: "inserting an item in the middle of a keyed list produces a single insert",
+			old:  "templ x() {\n\t<ul><li key=\"a\">A</li><li key=\"c\">C</li><li key=\"d\">D</li></ul>\n}",
+			new:  "templ x() {\n\t<ul><li key=\"a\">A</li><li key=\"b\">B</li><li key=\"c\">C</li><li key=\"d\">D</li></ul>\n}",
+			expected: []Patch{
+				{Op: PatchInsert, Path: []int{1, 1}, Node: Element{
+					Name:       "li",
+					Attributes: []Attribute{ConstantAttribute{Name: "key", Value: "b"}},
+					Children:   []Node{Text{Value: "B"}},
+					Key:        "b",
+				}},
+			},
+		},
+		{
+			name: "reordering a keyed list moves the item without changing the others",
+			old:  "templ x() {\n\t<ul><li key=\"a\">A</li><li key=\"b\">B</li><li key=\"c\">C</li></ul>\n}",
+			new:  "templ x() {\n\t<ul><li key=\"c\">C</li><li key=\"a\">A</li><li key=\"b\">B</li></ul>\n}",
+			expected: []Patch{
+				{Op: PatchInsert, Path: []int{1, 0}, Node: Element{
+					Name:       "li",
+					Attributes: []Attribute{ConstantAttribute{Name: "key", Value: "c"}},
+					Children:   []Node{Text{Value: "C"}},
+					Key:        "c",
+				}},
+				{Op: PatchRemove, Path: []int{1, 3}},
+			},
+		},
+		{
+			name: "changing an expression replaces the node",
+			old:  "templ x() {\n\t<p>{ a }</p>\n}",
+			new:  "templ x() {\n\t<p>{ b }</p>\n}",
+			expected: []Patch{
+				{Op: PatchReplace, Path: []int{1, 0}, Node: StringExpression{Expression: Expression{Value: "b"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			old := parseDiffTemplate(t, tt.old)
+			new := parseDiffTemplate(t, tt.new)
+			actual := Diff(old, new)
+			if diff := cmp.Diff(tt.expected, actual, cmp.Comparer(func(a, b Range) bool { return true })); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func parseDiffTemplate(t *testing.T, input string) []Node {
+	t.Helper()
+	tem, ok, err := template.Parse(parse.NewInput(input))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template %q: %v", input, err)
+	}
+	return tem.Children
+}
@@ -18,6 +18,18 @@ func TestDocTypeParser(t *testing.T) {
 			input: `<!DOCTYPE html>`,
 			expected: DocType{
 				Value: "html",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 15,
+						Line:  0,
+						Col:   15,
+					},
+				},
 			},
 		},
 		{
@@ -25,6 +37,18 @@ func TestDocTypeParser(t *testing.T) {
 			input: `<!doctype html>`,
 			expected: DocType{
 				Value: "html",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 15,
+						Line:  0,
+						Col:   15,
+					},
+				},
 			},
 		},
 		{
@@ -32,6 +56,18 @@ func TestDocTypeParser(t *testing.T) {
 			input: `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN" "http://www.w3.org/TR/html4/loose.dtd">`,
 			expected: DocType{
 				Value: `HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN" "http://www.w3.org/TR/html4/loose.dtd"`,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 102,
+						Line:  0,
+						Col:   102,
+					},
+				},
 			},
 		},
 		{
@@ -39,6 +75,18 @@ func TestDocTypeParser(t *testing.T) {
 			input: `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.1//EN" "http://www.w3.org/TR/xhtml11/DTD/xhtml11.dtd">`,
 			expected: DocType{
 				Value: `html PUBLIC "-//W3C//DTD XHTML 1.1//EN" "http://www.w3.org/TR/xhtml11/DTD/xhtml11.dtd"`,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 97,
+						Line:  0,
+						Col:   97,
+					},
+				},
 			},
 		},
 	}
@@ -3,6 +3,8 @@ package parser
 import (
 	"fmt"
 	"html"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/a-h/parse"
@@ -66,16 +68,22 @@ type elementCloseTag struct {
 	Name string
 }
 
+// elementCloseTagParser is tried before each child node of an element is parsed, so it avoids
+// allocating when there isn't a close tag.
 var elementCloseTagParser = parse.Func(func(in *parse.Input) (ct elementCloseTag, ok bool, err error) {
-	var parts []string
-	parts, ok, err = parse.All(
-		parse.String("</"),
-		elementNameParser,
-		parse.Rune('>')).Parse(in)
-	if err != nil || !ok {
+	start := in.Index()
+	if !peekPrefix(in, "</") {
+		return ct, false, nil
+	}
+	in.Take(len("</"))
+	if ct.Name, ok, err = elementNameParser.Parse(in); err != nil || !ok {
+		in.Seek(start)
+		return
+	}
+	if _, ok, err = gt.Parse(in); err != nil || !ok {
+		in.Seek(start)
 		return
 	}
-	ct.Name = parts[1]
 	return ct, true, nil
 })
 
@@ -83,13 +91,15 @@ var elementCloseTagParser = parse.Func(func(in *parse.Input) (ct elementCloseTag
 var (
 	attributeNameFirst      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ:_@"
 	attributeNameSubsequent = attributeNameFirst + "-.0123456789*"
+	attributeNameFirstRune  = parse.RuneIn(attributeNameFirst)
+	attributeNameSuffix     = parse.StringUntil(parse.RuneNotIn(attributeNameSubsequent))
 	attributeNameParser     = parse.Func(func(in *parse.Input) (name string, ok bool, err error) {
 		start := in.Index()
-		var prefix, suffix string
-		if prefix, ok, err = parse.RuneIn(attributeNameFirst).Parse(in); err != nil || !ok {
+		var suffix string
+		if _, ok, err = attributeNameFirstRune.Parse(in); err != nil || !ok {
 			return
 		}
-		if suffix, ok, err = parse.StringUntil(parse.RuneNotIn(attributeNameSubsequent)).Parse(in); err != nil {
+		if suffix, ok, err = attributeNameSuffix.Parse(in); err != nil {
 			in.Seek(start)
 			return
 		}
@@ -98,7 +108,9 @@ var (
 			err = parse.Error("attribute names must be < 128 characters long", in.Position())
 			return
 		}
-		return prefix + suffix, true, nil
+		// The name is sliced from the input, rather than joining the prefix and suffix, so that
+		// it isn't allocated.
+		return sourceSince(in, start), true, nil
 	})
 )
 
@@ -106,6 +118,7 @@ var (
 var (
 	attributeConstantValueParser            = parse.StringUntil(parse.Rune('"'))
 	attributeConstantValueSingleQuoteParser = parse.StringUntil(parse.Rune('\''))
+	attributeConstantValueStart             = parse.Or(parse.String(`="`), parse.String(`='`))
 	constantAttributeParser                 = parse.Func(func(pi *parse.Input) (attr ConstantAttribute, ok bool, err error) {
 		start := pi.Index()
 
@@ -115,17 +128,28 @@ var (
 		}
 
 		// Attribute name.
+		from := pi.Position()
 		if attr.Name, ok, err = attributeNameParser.Parse(pi); err != nil || !ok {
 			pi.Seek(start)
 			return
 		}
 
 		// ="
-		result, ok, err := parse.Or(parse.String(`="`), parse.String(`='`)).Parse(pi)
-		if err != nil || !ok {
+		result, ok, err := attributeConstantValueStart.Parse(pi)
+		if err != nil {
 			pi.Seek(start)
 			return
 		}
+		if !ok {
+			// Unquoted value, e.g. type=text.
+			if attr.Value, ok = unquotedAttributeValue(pi); !ok {
+				pi.Seek(start)
+				return
+			}
+			attr.Value = html.UnescapeString(attr.Value)
+			attr.Range = NewRange(from, pi.Position())
+			return attr, true, nil
+		}
 
 		valueParser := attributeConstantValueParser
 		closeParser := parse.String(`"`)
@@ -133,6 +157,7 @@ var (
 			valueParser = attributeConstantValueSingleQuoteParser
 			closeParser = parse.String(`'`)
 			attr.SingleQuote = true
+			attr.SourceSingleQuote = true
 		}
 
 		// Attribute value.
@@ -152,11 +177,44 @@ var (
 			err = parse.Error(fmt.Sprintf("missing closing quote on attribute %q", attr.Name), pi.Position())
 			return
 		}
+		attr.Range = NewRange(from, pi.Position())
 
 		return attr, true, nil
 	})
 )
 
+// unquotedAttributeValue parses an unquoted attribute value, including the leading equals sign,
+// e.g. `=text`. The value ends at whitespace, `>` or `/>`.
+func unquotedAttributeValue(pi *parse.Input) (value string, ok bool) {
+	start := pi.Index()
+	if next, _ := pi.Peek(1); next != "=" {
+		return "", false
+	}
+	pi.Take(1)
+	valueStart := pi.Index()
+	for {
+		next, ok := pi.Peek(1)
+		if !ok || next == ">" || strings.TrimSpace(next) == "" {
+			break
+		}
+		if next == "/" {
+			if closing, _ := pi.Peek(2); closing == "/>" {
+				break
+			}
+		}
+		if next == `"` || next == "'" || next == "=" || next == "<" || next == "`" || (next == "{" && pi.Index() == valueStart) {
+			pi.Seek(start)
+			return "", false
+		}
+		pi.Take(1)
+	}
+	if value = sourceSince(pi, valueStart); value == "" {
+		pi.Seek(start)
+		return "", false
+	}
+	return value, true
+}
+
 // BoolConstantAttribute.
 var boolConstantAttributeParser = parse.Func(func(pi *parse.Input) (attr BoolConstantAttribute, ok bool, err error) {
 	start := pi.Index()
@@ -167,10 +225,12 @@ var boolConstantAttributeParser = parse.Func(func(pi *parse.Input) (attr BoolCon
 	}
 
 	// Attribute name.
+	from := pi.Position()
 	if attr.Name, ok, err = attributeNameParser.Parse(pi); err != nil || !ok {
 		pi.Seek(start)
 		return
 	}
+	attr.Range = NewRange(from, pi.Position())
 
 	// We have a name, but if we have an equals sign, it's not a constant boolean attribute.
 	next, ok := pi.Peek(1)
@@ -230,6 +290,8 @@ var boolExpressionAttributeParser = parse.Func(func(pi *parse.Input) (r BoolExpr
 	return r, true, nil
 })
 
+var expressionAttributeValueStart = parse.Or(parse.String("={ "), parse.String("={"))
+
 var expressionAttributeParser = parse.Func(func(pi *parse.Input) (attr ExpressionAttribute, ok bool, err error) {
 	start := pi.Index()
 
@@ -245,7 +307,7 @@ var expressionAttributeParser = parse.Func(func(pi *parse.Input) (attr Expressio
 	}
 
 	// ={
-	if _, ok, err = parse.Or(parse.String("={ "), parse.String("={")).Parse(pi); err != nil || !ok {
+	if _, ok, err = expressionAttributeValueStart.Parse(pi); err != nil || !ok {
 		pi.Seek(start)
 		return
 	}
@@ -264,6 +326,71 @@ var expressionAttributeParser = parse.Func(func(pi *parse.Input) (attr Expressio
 		return
 	}
 
+	if isEventAttribute(attr.Name) {
+		attr.Handler = parseEventHandler(pi, attr.Expression)
+	}
+
+	return attr, true, nil
+})
+
+func isEventAttribute(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "on") || strings.HasPrefix(name, "hx-on:")
+}
+
+var eventHandlerName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// parseEventHandler returns the function call in an event handler expression, or nil if the
+// expression isn't a call to a named function.
+func parseEventHandler(pi *parse.Input, e Expression) *EventHandler {
+	src, from := e.Value, int(e.Range.From.Index)
+	args, end, err := goexpression.Call(src)
+	if err != nil || strings.TrimSpace(src[end:]) != "" {
+		return nil
+	}
+	name, _, _ := strings.Cut(src, "(")
+	name = strings.TrimSpace(name)
+	if !eventHandlerName.MatchString(name) {
+		return nil
+	}
+	h := &EventHandler{Name: name}
+	for _, arg := range args {
+		h.Args = append(h.Args, NewExpression(src[arg.Start:arg.End], pi.PositionAt(from+arg.Start), pi.PositionAt(from+arg.End)))
+	}
+	return h
+}
+
+// classAttributeParser parses class attributes with an expression, e.g.
+// class={ "btn", templ.KV("large", isLarge), "active": isActive }, into a class list.
+var classAttributeParser = parse.Func(func(pi *parse.Input) (attr ClassAttribute, ok bool, err error) {
+	start := pi.Index()
+	ea, ok, err := expressionAttributeParser.Parse(pi)
+	if err != nil || !ok || !strings.EqualFold(ea.Name, "class") {
+		pi.Seek(start)
+		return attr, false, nil
+	}
+	elements, err := goexpression.SliceElements(ea.Expression.Value)
+	if err != nil {
+		pi.Seek(start)
+		return attr, false, nil
+	}
+	src, from := ea.Expression.Value, int(ea.Expression.Range.From.Index)
+	attr.Name = ea.Name
+	attr.Expression = ea.Expression
+	for _, el := range elements {
+		e := NewExpression(src[el.Start:el.End], pi.PositionAt(from+el.Start), pi.PositionAt(from+el.End))
+		if el.KeyEnd == 0 {
+			attr.Classes = append(attr.Classes, ClassPart{Expression: e})
+			continue
+		}
+		// Leave class names that aren't string literals to the Go compiler to report.
+		var name string
+		if name, err = strconv.Unquote(src[el.KeyStart:el.KeyEnd]); err != nil || name == "" {
+			pi.Seek(start)
+			return attr, false, nil
+		}
+		attr.Classes = append(attr.Classes, ClassPart{Class: name, Cond: e})
+	}
 	return attr, true, nil
 })
 
@@ -276,6 +403,7 @@ var spreadAttributesParser = parse.Func(func(pi *parse.Input) (attr SpreadAttrib
 	}
 
 	// Eat the first brace.
+	from := pi.Position()
 	if _, ok, err = openBraceWithOptionalPadding.Parse(pi); err != nil ||
 		!ok {
 		pi.Seek(start)
@@ -304,6 +432,7 @@ var spreadAttributesParser = parse.Func(func(pi *parse.Input) (attr SpreadAttrib
 		err = parse.Error("attribute spread expression: missing closing brace", pi.Position())
 		return
 	}
+	attr.Range = NewRange(from, pi.Position())
 
 	return attr, true, nil
 })
@@ -315,6 +444,9 @@ func (attributeParser) Parse(in *parse.Input) (out Attribute, ok bool, err error
 	if out, ok, err = boolExpressionAttributeParser.Parse(in); err != nil || ok {
 		return
 	}
+	if out, ok, err = classAttributeParser.Parse(in); err != nil || ok {
+		return
+	}
 	if out, ok, err = expressionAttributeParser.Parse(in); err != nil || ok {
 		return
 	}
@@ -354,15 +486,17 @@ func (attributesParser) Parse(in *parse.Input) (attributes []Attribute, ok bool,
 
 // Element name.
 var (
-	elementNameFirst      = "abcdefghijklmnopqrstuvwxyz"
-	elementNameSubsequent = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-"
+	elementNameFirst      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	elementNameSubsequent = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-:"
+	elementNameFirstRune  = parse.RuneIn(elementNameFirst)
+	elementNameSuffix     = parse.StringUntil(parse.RuneNotIn(elementNameSubsequent))
 	elementNameParser     = parse.Func(func(in *parse.Input) (name string, ok bool, err error) {
 		start := in.Index()
-		var prefix, suffix string
-		if prefix, ok, err = parse.RuneIn(elementNameFirst).Parse(in); err != nil || !ok {
+		var suffix string
+		if _, ok, err = elementNameFirstRune.Parse(in); err != nil || !ok {
 			return
 		}
-		if suffix, ok, err = parse.StringUntil(parse.RuneNotIn(elementNameSubsequent)).Parse(in); err != nil || !ok {
+		if suffix, ok, err = elementNameSuffix.Parse(in); err != nil || !ok {
 			in.Seek(start)
 			return
 		}
@@ -371,16 +505,19 @@ var (
 			err = parse.Error("element names must be < 128 characters long", in.Position())
 			return
 		}
-		return prefix + suffix, true, nil
+		return sourceSince(in, start), true, nil
 	})
 )
 
 // Element.
 var elementOpenClose elementOpenCloseParser
 
-type elementOpenCloseParser struct{}
+type elementOpenCloseParser struct {
+	// preserveWhitespace is set within whitespace sensitive elements, e.g. <pre>.
+	preserveWhitespace bool
+}
 
-func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err error) {
+func (p elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err error) {
 	// Check the open tag.
 	var ot elementOpenTag
 	if ot, ok, err = elementOpenTagParser.Parse(pi); err != nil || !ok {
@@ -389,17 +526,24 @@ func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err er
 	r.Name = ot.Name
 	r.Attributes = ot.Attributes
 	r.IndentAttrs = ot.IndentAttrs
+	if r.IsVoidElement() {
+		return voidElementEnd(pi, r)
+	}
+	r.PreserveWhitespace = p.preserveWhitespace || isWhitespaceSensitive(r.Name)
 
 	// Once we've got an open tag, the rest must be present.
 	l := pi.Position().Line
 	var nodes Nodes
-	if nodes, ok, err = newTemplateNodeParser[any](nil, "").Parse(pi); err != nil || !ok {
+	tnp := newTemplateNodeParser[any](nil, "")
+	tnp.preserveWhitespace = r.PreserveWhitespace
+	if nodes, ok, err = tnp.Parse(pi); err != nil || !ok {
 		return
 	}
 	r.Children = nodes.Nodes
 	r.Diagnostics = nodes.Diagnostics
-	// If the children are not all on the same line, indent them
-	if l != pi.Position().Line {
+	// If the children are not all on the same line, indent them, unless the whitespace is
+	// significant.
+	if l != pi.Position().Line && !r.PreserveWhitespace {
 		r.IndentChildren = true
 	}
 
@@ -411,12 +555,15 @@ func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err er
 		return
 	}
 	if !ok {
-		err = parse.Error(fmt.Sprintf("<%s>: expected end tag not present or invalid tag contents", r.Name), pi.Position())
+		err = parse.Error(fmt.Sprintf("<%s>: expected end tag '</%s>' not present or invalid tag contents", r.Name, r.Name), pi.Position())
 		return
 	}
 	if ct.Name != r.Name {
+		if (Element{Name: ct.Name}).IsVoidElement() {
+			return r, false, voidElementEndTagError(ct.Name, pos)
+		}
 		err = parse.Error(fmt.Sprintf("<%s>: mismatched end tag, expected '</%s>', got '</%s>'", r.Name, r.Name, ct.Name), pos)
-		return
+		return r, false, err
 	}
 
 	// Parse trailing whitespace.
@@ -432,6 +579,36 @@ func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err er
 	return r, true, nil
 }
 
+// voidElementEndTagError is returned for the end tag of a void element that follows other
+// content, e.g. `<img>content</img>`, since the content would be the element's children.
+func voidElementEndTagError(name string, pos parse.Position) error {
+	return parse.Error(fmt.Sprintf("<%s>: void elements can't have children", name), pos)
+}
+
+// voidElementEnd parses the rest of a void element after its open tag, e.g. `<br>`. Void
+// elements can't have children, so the end tag is optional, and is only consumed if nothing but
+// whitespace comes before it. Anything else follows the element, and an end tag after other
+// content is reported by the parent element, see voidElementEndTagError.
+func voidElementEnd(pi *parse.Input, r Element) (Element, bool, error) {
+	afterOpenTag := pi.Index()
+	_, _, _ = parse.OptionalWhitespace.Parse(pi)
+	if ct, ok, err := elementCloseTagParser.Parse(pi); err != nil || !ok || ct.Name != r.Name {
+		// Other content, e.g. a sibling or the parent's end tag, follows the element.
+		pi.Seek(afterOpenTag)
+	}
+
+	// Parse trailing whitespace.
+	ws, _, err := parse.Whitespace.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	r.TrailingSpace, err = NewTrailingSpace(ws)
+	if err != nil {
+		return r, false, err
+	}
+	return r, true, nil
+}
+
 // Element self-closing tag.
 var selfClosingElement = parse.Func(func(pi *parse.Input) (e Element, ok bool, err error) {
 	start := pi.Index()
@@ -485,15 +662,22 @@ var selfClosingElement = parse.Func(func(pi *parse.Input) (e Element, ok bool, e
 // Element
 var element elementParser
 
-type elementParser struct{}
+type elementParser struct {
+	// preserveWhitespace is set within whitespace sensitive elements, e.g. <pre>.
+	preserveWhitespace bool
+}
 
-func (elementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+func (p elementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	start := pi.Position()
+	startIndex := pi.Index()
 
 	var r Element
-	if r, ok, err = parse.Any[Element](selfClosingElement, elementOpenClose).Parse(pi); err != nil || !ok {
+	openClose := elementOpenCloseParser{preserveWhitespace: p.preserveWhitespace}
+	if r, ok, err = parse.Any[Element](selfClosingElement, openClose).Parse(pi); err != nil || !ok {
 		return
 	}
+	r.Key = elementKey(r.Attributes)
+	r.Range = sourceRange(pi, startIndex)
 	var msgs []string
 	if msgs, ok = r.Validate(); !ok {
 		err = parse.Error(fmt.Sprintf("<%s>: %s", r.Name, strings.Join(msgs, ", ")), start)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/a-h/parse"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 type attributeTest[T any] struct {
@@ -43,6 +44,18 @@ func TestAttributeParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "_",
 						Value: "show = true",
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 20,
+								Line:  0,
+								Col:   20,
+							},
+						},
 					},
 				},
 			},
@@ -57,10 +70,34 @@ func TestAttributeParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "@click",
 						Value: "show = true",
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 25,
+								Line:  0,
+								Col:   25,
+							},
+						},
 					},
 					ConstantAttribute{
 						Name:  ":class",
 						Value: "{'foo': true}",
+						Range: Range{
+							From: Position{
+								Index: 26,
+								Line:  0,
+								Col:   26,
+							},
+							To: Position{
+								Index: 48,
+								Line:  0,
+								Col:   48,
+							},
+						},
 					},
 				},
 			},
@@ -75,10 +112,34 @@ func TestAttributeParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "id",
 						Value: "123",
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 13,
+								Line:  0,
+								Col:   13,
+							},
+						},
 					},
 					ConstantAttribute{
 						Name:  "style",
 						Value: "padding: 10px",
+						Range: Range{
+							From: Position{
+								Index: 14,
+								Line:  0,
+								Col:   14,
+							},
+							To: Position{
+								Index: 35,
+								Line:  0,
+								Col:   35,
+							},
+						},
 					},
 				},
 			},
@@ -111,6 +172,18 @@ func TestAttributeParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "class",
 						Value: "important",
+						Range: Range{
+							From: Position{
+								Index: 23,
+								Line:  2,
+								Col:   3,
+							},
+							To: Position{
+								Index: 40,
+								Line:  2,
+								Col:   20,
+							},
+						},
 					},
 				},
 			},
@@ -145,9 +218,33 @@ if test {
 					ConstantAttribute{
 						Name:  "class",
 						Value: "itIsTrue",
+						Range: Range{
+							From: Position{
+								Index: 13,
+								Line:  2,
+								Col:   1,
+							},
+							To: Position{
+								Index: 29,
+								Line:  2,
+								Col:   17,
+							},
+						},
 					},
 					BoolConstantAttribute{
 						Name: "noshade",
+						Range: Range{
+							From: Position{
+								Index: 31,
+								Line:  3,
+								Col:   1,
+							},
+							To: Position{
+								Index: 38,
+								Line:  3,
+								Col:   8,
+							},
+						},
 					},
 					ExpressionAttribute{
 						Name: "name",
@@ -170,6 +267,97 @@ if test {
 				},
 			},
 		},
+		{
+			name:   "conditional expression attribute - nested",
+			input:  `if a { if b { x } else { y } }"`,
+			parser: StripType(conditionalAttribute),
+			expected: ConditionalAttribute{
+				Expression: Expression{
+					Value: "a",
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 4, Line: 0, Col: 4},
+					},
+				},
+				Then: []Attribute{
+					ConditionalAttribute{
+						Expression: Expression{
+							Value: "b",
+							Range: Range{
+								From: Position{Index: 10, Line: 0, Col: 10},
+								To:   Position{Index: 11, Line: 0, Col: 11},
+							},
+						},
+						Then: []Attribute{
+							BoolConstantAttribute{
+								Name: "x",
+								Range: Range{
+									From: Position{
+										Index: 14,
+										Line:  0,
+										Col:   14,
+									},
+									To: Position{
+										Index: 15,
+										Line:  0,
+										Col:   15,
+									},
+								},
+							},
+						},
+						Else: []Attribute{
+							BoolConstantAttribute{
+								Name: "y",
+								Range: Range{
+									From: Position{
+										Index: 25,
+										Line:  0,
+										Col:   25,
+									},
+									To: Position{
+										Index: 26,
+										Line:  0,
+										Col:   26,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "conditional expression attribute - empty else",
+			input:  `if p.important { class="important" } else { }"`,
+			parser: StripType(conditionalAttribute),
+			expected: ConditionalAttribute{
+				Expression: Expression{
+					Value: "p.important",
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 14, Line: 0, Col: 14},
+					},
+				},
+				Then: []Attribute{
+					ConstantAttribute{
+						Name:  "class",
+						Value: "important",
+						Range: Range{
+							From: Position{
+								Index: 17,
+								Line:  0,
+								Col:   17,
+							},
+							To: Position{
+								Index: 34,
+								Line:  0,
+								Col:   34,
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:   "boolean expression attribute",
 			input:  ` noshade?={ true }"`,
@@ -244,7 +432,7 @@ if test {
 			input:  ` { spread... }"`,
 			parser: StripType(spreadAttributesParser),
 			expected: SpreadAttributes{
-				Expression{
+				Expression: Expression{
 					Value: "spread",
 					Range: Range{
 						From: Position{
@@ -259,6 +447,10 @@ if test {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{Index: 1, Line: 0, Col: 1},
+					To:   Position{Index: 14, Line: 0, Col: 14},
+				},
 			},
 		},
 		{
@@ -268,6 +460,18 @@ if test {
 			expected: ConstantAttribute{
 				Name:  "href",
 				Value: "test",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 12,
+						Line:  0,
+						Col:   12,
+					},
+				},
 			},
 		},
 		{
@@ -275,9 +479,22 @@ if test {
 			input:  ` href='no double quote in value'`,
 			parser: StripType(constantAttributeParser),
 			expected: ConstantAttribute{
-				Name:        "href",
-				Value:       `no double quote in value`,
-				SingleQuote: false,
+				Name:              "href",
+				Value:             `no double quote in value`,
+				SingleQuote:       false,
+				SourceSingleQuote: true,
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 32,
+						Line:  0,
+						Col:   32,
+					},
+				},
 			},
 		},
 		{
@@ -285,9 +502,85 @@ if test {
 			input:  ` href='"test"'`,
 			parser: StripType(constantAttributeParser),
 			expected: ConstantAttribute{
-				Name:        "href",
-				Value:       `"test"`,
-				SingleQuote: true,
+				Name:              "href",
+				Value:             `"test"`,
+				SingleQuote:       true,
+				SourceSingleQuote: true,
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 14,
+						Line:  0,
+						Col:   14,
+					},
+				},
+			},
+		},
+		{
+			name:   "unquoted constant attribute",
+			input:  ` type=text`,
+			parser: StripType(constantAttributeParser),
+			expected: ConstantAttribute{
+				Name:  "type",
+				Value: "text",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 10,
+						Line:  0,
+						Col:   10,
+					},
+				},
+			},
+		},
+		{
+			name:   "unquoted constant attribute containing a slash",
+			input:  ` href=/docs/index.html>`,
+			parser: StripType(constantAttributeParser),
+			expected: ConstantAttribute{
+				Name:  "href",
+				Value: "/docs/index.html",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 22,
+						Line:  0,
+						Col:   22,
+					},
+				},
+			},
+		},
+		{
+			name:   "unquoted constant attribute with entities",
+			input:  ` title=a&amp;b`,
+			parser: StripType(constantAttributeParser),
+			expected: ConstantAttribute{
+				Name:  "title",
+				Value: "a&b",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 14,
+						Line:  0,
+						Col:   14,
+					},
+				},
 			},
 		},
 		{
@@ -297,6 +590,18 @@ if test {
 			expected: ConstantAttribute{
 				Name:  "data-turbo-permanent",
 				Value: "value",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 29,
+						Line:  0,
+						Col:   29,
+					},
+				},
 			},
 		},
 		{
@@ -306,6 +611,18 @@ if test {
 			expected: ConstantAttribute{
 				Name:  "data",
 				Value: "",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 8,
+						Line:  0,
+						Col:   8,
+					},
+				},
 			},
 		},
 		{
@@ -318,6 +635,18 @@ if test {
 			expected: ConstantAttribute{
 				Name:  "data-script",
 				Value: "on click\n                do something\n             end",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 69,
+						Line:  2,
+						Col:   17,
+					},
+				},
 			},
 		},
 		{
@@ -329,6 +658,18 @@ if test {
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "data",
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 9,
+								Line:  0,
+								Col:   9,
+							},
+						},
 					},
 				},
 			},
@@ -343,6 +684,30 @@ if test {
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "required",
+						Range: Range{
+							From: Position{
+								Index: 9,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 17,
+								Line:  1,
+								Col:   10,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 21,
+						Line:  2,
+						Col:   3,
 					},
 				},
 			},
@@ -357,6 +722,30 @@ if test {
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "required",
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 18,
+								Line:  1,
+								Col:   10,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 23,
+						Line:  2,
+						Col:   3,
 					},
 				},
 			},
@@ -368,6 +757,136 @@ if test {
 			expected: ConstantAttribute{
 				Name:  "href",
 				Value: `<">`,
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 22,
+						Line:  0,
+						Col:   22,
+					},
+				},
+			},
+		},
+		{
+			name:   "class attributes can contain conditional classes",
+			input:  ` class={ "btn", "active": isActive }`,
+			parser: StripType(classAttributeParser),
+			expected: ClassAttribute{
+				Name: "class",
+				Classes: []ClassPart{
+					{
+						Expression: Expression{
+							Value: `"btn"`,
+							Range: Range{
+								From: Position{Index: 9, Line: 0, Col: 9},
+								To:   Position{Index: 14, Line: 0, Col: 14},
+							},
+						},
+					},
+					{
+						Class: "active",
+						Cond: Expression{
+							Value: `isActive`,
+							Range: Range{
+								From: Position{Index: 26, Line: 0, Col: 26},
+								To:   Position{Index: 34, Line: 0, Col: 34},
+							},
+						},
+					},
+				},
+				Expression: Expression{
+					Value: `"btn", "active": isActive`,
+					Range: Range{
+						From: Position{Index: 9, Line: 0, Col: 9},
+						To:   Position{Index: 34, Line: 0, Col: 34},
+					},
+				},
+			},
+		},
+		{
+			name:   "class attributes with expressions are class lists",
+			input:  ` class={ "btn", templ.KV("active", isActive) }`,
+			parser: StripType[Attribute](attribute),
+			expected: ClassAttribute{
+				Name: "class",
+				Classes: []ClassPart{
+					{
+						Expression: Expression{
+							Value: `"btn"`,
+							Range: Range{
+								From: Position{Index: 9, Line: 0, Col: 9},
+								To:   Position{Index: 14, Line: 0, Col: 14},
+							},
+						},
+					},
+					{
+						Expression: Expression{
+							Value: `templ.KV("active", isActive)`,
+							Range: Range{
+								From: Position{Index: 16, Line: 0, Col: 16},
+								To:   Position{Index: 44, Line: 0, Col: 44},
+							},
+						},
+					},
+				},
+				Expression: Expression{
+					Value: `"btn", templ.KV("active", isActive)`,
+					Range: Range{
+						From: Position{Index: 9, Line: 0, Col: 9},
+						To:   Position{Index: 44, Line: 0, Col: 44},
+					},
+				},
+			},
+		},
+		{
+			name:   "class attributes with a single expression are class lists",
+			input:  ` class={ classes }`,
+			parser: StripType[Attribute](attribute),
+			expected: ClassAttribute{
+				Name: "class",
+				Classes: []ClassPart{
+					{
+						Expression: Expression{
+							Value: `classes`,
+							Range: Range{
+								From: Position{Index: 9, Line: 0, Col: 9},
+								To:   Position{Index: 16, Line: 0, Col: 16},
+							},
+						},
+					},
+				},
+				Expression: Expression{
+					Value: `classes`,
+					Range: Range{
+						From: Position{Index: 9, Line: 0, Col: 9},
+						To:   Position{Index: 16, Line: 0, Col: 16},
+					},
+				},
+			},
+		},
+		{
+			name:   "constant class attributes are constant attributes",
+			input:  ` class="static"`,
+			parser: StripType[Attribute](attribute),
+			expected: ConstantAttribute{
+				Name:  "class",
+				Value: "static",
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 15,
+						Line:  0,
+						Col:   15,
+					},
+				},
 			},
 		},
 		{
@@ -377,6 +896,93 @@ if test {
 			expected: ConstantAttribute{
 				Name:  "hx-target-*",
 				Value: `#errors`,
+				Range: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 22,
+						Line:  0,
+						Col:   22,
+					},
+				},
+			},
+		},
+		{
+			name:   "expression attribute: identifier field",
+			input:  ` value={ user.Name }`,
+			parser: StripType(expressionAttributeParser),
+			expected: ExpressionAttribute{
+				Name: "value",
+				Expression: Expression{
+					Value: `user.Name`,
+					Range: Range{
+						From: Position{Index: 9, Line: 0, Col: 9},
+						To:   Position{Index: 18, Line: 0, Col: 18},
+					},
+				},
+			},
+		},
+		{
+			name:   "expression attribute: string concatenation",
+			input:  ` href={ "/users/" + id }`,
+			parser: StripType(expressionAttributeParser),
+			expected: ExpressionAttribute{
+				Name: "href",
+				Expression: Expression{
+					Value: `"/users/" + id`,
+					Range: Range{
+						From: Position{Index: 8, Line: 0, Col: 8},
+						To:   Position{Index: 22, Line: 0, Col: 22},
+					},
+				},
+			},
+		},
+		{
+			name:   "expression attribute: method call",
+			input:  ` value={ user.FullName() }`,
+			parser: StripType(expressionAttributeParser),
+			expected: ExpressionAttribute{
+				Name: "value",
+				Expression: Expression{
+					Value: `user.FullName()`,
+					Range: Range{
+						From: Position{Index: 9, Line: 0, Col: 9},
+						To:   Position{Index: 24, Line: 0, Col: 24},
+					},
+				},
+			},
+		},
+		{
+			name:   "expression attribute: braces within the expression",
+			input:  ` value={ Point{X: 1, Y: 2}.String() }`,
+			parser: StripType(expressionAttributeParser),
+			expected: ExpressionAttribute{
+				Name: "value",
+				Expression: Expression{
+					Value: `Point{X: 1, Y: 2}.String()`,
+					Range: Range{
+						From: Position{Index: 9, Line: 0, Col: 9},
+						To:   Position{Index: 35, Line: 0, Col: 35},
+					},
+				},
+			},
+		},
+		{
+			name:   "expression attribute: map literal within the expression",
+			input:  ` title={ map[string]string{"a": "b"}[key] }`,
+			parser: StripType(expressionAttributeParser),
+			expected: ExpressionAttribute{
+				Name: "title",
+				Expression: Expression{
+					Value: `map[string]string{"a": "b"}[key]`,
+					Range: Range{
+						From: Position{Index: 9, Line: 0, Col: 9},
+						To:   Position{Index: 41, Line: 0, Col: 41},
+					},
+				},
 			},
 		},
 	}
@@ -413,6 +1019,30 @@ func TestElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "href",
 						Value: "test",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 14,
+								Line:  0,
+								Col:   14,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 16,
+						Line:  0,
+						Col:   16,
 					},
 				},
 			},
@@ -442,6 +1072,18 @@ func TestElementParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 23,
+						Line:  0,
+						Col:   23,
+					},
+				},
 			},
 		},
 		{
@@ -469,6 +1111,18 @@ func TestElementParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 20,
+						Line:  0,
+						Col:   20,
+					},
+				},
 			},
 		},
 		{
@@ -480,10 +1134,46 @@ func TestElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "href",
 						Value: "test",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 14,
+								Line:  0,
+								Col:   14,
+							},
+						},
 					},
 					ConstantAttribute{
 						Name:  "style",
 						Value: "text-underline: auto",
+						Range: Range{
+							From: Position{
+								Index: 15,
+								Line:  0,
+								Col:   15,
+							},
+							To: Position{
+								Index: 43,
+								Line:  0,
+								Col:   43,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 45,
+						Line:  0,
+						Col:   45,
 					},
 				},
 			},
@@ -510,6 +1200,10 @@ func TestElementParser(t *testing.T) {
 								},
 							},
 						},
+						Range: Range{
+							From: Position{Index: 3, Line: 0, Col: 3},
+							To:   Position{Index: 21, Line: 0, Col: 21},
+						},
 					},
 					SpreadAttributes{
 						Expression: Expression{
@@ -527,18 +1221,112 @@ func TestElementParser(t *testing.T) {
 								},
 							},
 						},
+						Range: Range{
+							From: Position{Index: 22, Line: 0, Col: 22},
+							To:   Position{Index: 37, Line: 0, Col: 37},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 39,
+						Line:  0,
+						Col:   39,
 					},
 				},
 			},
 		},
 		{
-			name:  "element: self-closing with multiple boolean attributes",
-			input: `<hr optionA optionB?={ true } optionC="other"/>`,
+			name:  "element: spread attributes keep their position between other attributes",
+			input: `<a href="/" { attrs... } title="Home"/>`,
 			expected: Element{
-				Name: "hr",
+				Name: "a",
 				Attributes: []Attribute{
-					BoolConstantAttribute{
-						Name: "optionA",
+					ConstantAttribute{
+						Name:  "href",
+						Value: "/",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 11,
+								Line:  0,
+								Col:   11,
+							},
+						},
+					},
+					SpreadAttributes{
+						Expression: Expression{
+							Value: "attrs",
+							Range: Range{
+								From: Position{Index: 14, Line: 0, Col: 14},
+								To:   Position{Index: 19, Line: 0, Col: 19},
+							},
+						},
+						Range: Range{
+							From: Position{Index: 12, Line: 0, Col: 12},
+							To:   Position{Index: 24, Line: 0, Col: 24},
+						},
+					},
+					ConstantAttribute{
+						Name:  "title",
+						Value: "Home",
+						Range: Range{
+							From: Position{
+								Index: 25,
+								Line:  0,
+								Col:   25,
+							},
+							To: Position{
+								Index: 37,
+								Line:  0,
+								Col:   37,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 39,
+						Line:  0,
+						Col:   39,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: self-closing with multiple boolean attributes",
+			input: `<hr optionA optionB?={ true } optionC="other"/>`,
+			expected: Element{
+				Name: "hr",
+				Attributes: []Attribute{
+					BoolConstantAttribute{
+						Name: "optionA",
+						Range: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 11,
+								Line:  0,
+								Col:   11,
+							},
+						},
 					},
 					BoolExpressionAttribute{
 						Name: "optionB",
@@ -561,6 +1349,30 @@ func TestElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "optionC",
 						Value: "other",
+						Range: Range{
+							From: Position{
+								Index: 30,
+								Line:  0,
+								Col:   30,
+							},
+							To: Position{
+								Index: 45,
+								Line:  0,
+								Col:   45,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 47,
+						Line:  0,
+						Col:   47,
 					},
 				},
 			},
@@ -574,6 +1386,18 @@ func TestElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "href",
 						Value: "test",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 14,
+								Line:  0,
+								Col:   14,
+							},
+						},
 					},
 					ExpressionAttribute{
 						Name: "title",
@@ -596,6 +1420,30 @@ func TestElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "style",
 						Value: "text-underline: auto",
+						Range: Range{
+							From: Position{
+								Index: 53,
+								Line:  0,
+								Col:   53,
+							},
+							To: Position{
+								Index: 81,
+								Line:  0,
+								Col:   81,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 83,
+						Line:  0,
+						Col:   83,
 					},
 				},
 			},
@@ -616,6 +1464,18 @@ func TestElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "style",
 						Value: "width: 100;",
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 24,
+								Line:  0,
+								Col:   24,
+							},
+						},
 					},
 					ConditionalAttribute{
 						Expression: Expression{
@@ -637,6 +1497,18 @@ func TestElementParser(t *testing.T) {
 							ConstantAttribute{
 								Name:  "class",
 								Value: "important",
+								Range: Range{
+									From: Position{
+										Index: 47,
+										Line:  2,
+										Col:   3,
+									},
+									To: Position{
+										Index: 64,
+										Line:  2,
+										Col:   20,
+									},
+								},
 							},
 						},
 					},
@@ -645,9 +1517,33 @@ func TestElementParser(t *testing.T) {
 				Children: []Node{
 					Text{
 						Value: "Test",
+						Range: Range{
+							From: Position{
+								Index: 70,
+								Line:  4,
+								Col:   1,
+							},
+							To: Position{
+								Index: 74,
+								Line:  4,
+								Col:   5,
+							},
+						},
 					},
 				},
 				TrailingSpace: SpaceVertical,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 80,
+						Line:  4,
+						Col:   11,
+					},
+				},
 			},
 		},
 		{
@@ -655,6 +1551,18 @@ func TestElementParser(t *testing.T) {
 			input: `<hr/>`,
 			expected: Element{
 				Name: "hr",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 5,
+						Line:  0,
+						Col:   5,
+					},
+				},
 			},
 		},
 		{
@@ -666,305 +1574,1337 @@ func TestElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "style",
 						Value: "padding: 10px",
+						Range: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 25,
+								Line:  0,
+								Col:   25,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 28,
+						Line:  0,
+						Col:   28,
 					},
 				},
 			},
 		},
 		{
-			name: "element: self-closing with conditional attribute",
-			input: `<hr style="padding: 10px" 
-			if true {
-				class="itIsTrue"
-			}
-/>`,
+			name:  "element: void element without a closing slash",
+			input: `<br>`,
 			expected: Element{
-				Name: "hr",
-				Attributes: []Attribute{
-					ConstantAttribute{
-						Name:  "style",
-						Value: "padding: 10px",
+				Name: "br",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
 					},
-					ConditionalAttribute{
-						Expression: Expression{
-							Value: "true",
-							Range: Range{
-								From: Position{
-									Index: 33,
-									Line:  1,
-									Col:   6,
-								},
-								To: Position{
-									Index: 37,
-									Line:  1,
-									Col:   10,
-								},
-							},
-						},
-						Then: []Attribute{
-							ConstantAttribute{
-								Name:  "class",
-								Value: "itIsTrue",
-							},
-						},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
 					},
 				},
-				IndentAttrs: true,
 			},
 		},
 		{
-			name: "element: self-closing with conditional attribute with else block",
-			input: `<hr style="padding: 10px" 
-			if true {
-				class="itIsTrue"
-			} else {
-				class="itIsNotTrue"
-			}
-/>`,
+			name:  "element: void element with attributes and without a closing slash",
+			input: `<input type="text">`,
 			expected: Element{
-				Name: "hr",
+				Name: "input",
 				Attributes: []Attribute{
 					ConstantAttribute{
-						Name:  "style",
-						Value: "padding: 10px",
-					},
-					ConditionalAttribute{
-						Expression: Expression{
-							Value: "true",
-							Range: Range{
-								From: Position{
-									Index: 33,
-									Line:  1,
-									Col:   6,
-								},
-								To: Position{
-									Index: 37,
-									Line:  1,
-									Col:   10,
-								},
-							},
-						},
-						Then: []Attribute{
-							ConstantAttribute{
-								Name:  "class",
-								Value: "itIsTrue",
+						Name:  "type",
+						Value: "text",
+						Range: Range{
+							From: Position{
+								Index: 7,
+								Line:  0,
+								Col:   7,
 							},
-						},
-						Else: []Attribute{
-							ConstantAttribute{
-								Name:  "class",
-								Value: "itIsNotTrue",
+							To: Position{
+								Index: 18,
+								Line:  0,
+								Col:   18,
 							},
 						},
 					},
 				},
-				IndentAttrs: true,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 19,
+						Line:  0,
+						Col:   19,
+					},
+				},
 			},
 		},
 		{
-			name: "element: open and close with conditional attribute",
-			input: `<p style="padding: 10px" 
-			if true {
-				class="itIsTrue"
-			}
->Test</p>`,
+			name:  "element: void element with an end tag",
+			input: `<br></br>`,
 			expected: Element{
-				Name: "p",
-				Attributes: []Attribute{
-					ConstantAttribute{
-						Name:  "style",
-						Value: "padding: 10px",
+				Name: "br",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
 					},
-					ConditionalAttribute{
-						Expression: Expression{
-							Value: "true",
-							Range: Range{
-								From: Position{
-									Index: 32,
-									Line:  1,
-									Col:   6,
-								},
-								To: Position{
-									Index: 36,
-									Line:  1,
-									Col:   10,
-								},
-							},
-						},
-						Then: []Attribute{
-							ConstantAttribute{
-								Name:  "class",
-								Value: "itIsTrue",
-							},
-						},
+					To: Position{
+						Index: 9,
+						Line:  0,
+						Col:   9,
 					},
 				},
-				IndentAttrs: true,
-				Children: []Node{
-					Text{Value: "Test"},
-				},
 			},
 		},
 		{
-			name:  "element: open and close",
-			input: `<a></a>`,
+			name:  "element: void element with whitespace before its end tag",
+			input: "<br>\n</br>",
 			expected: Element{
-				Name: "a",
+				Name: "br",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 10,
+						Line:  1,
+						Col:   5,
+					},
+				},
 			},
 		},
 		{
-			name:  "element: open and close with text",
-			input: `<a>The text</a>`,
+			name:  "element: void element followed by siblings within an element",
+			input: `<p>a<br>b</p>`,
 			expected: Element{
-				Name: "a",
+				Name: "p",
 				Children: []Node{
 					Text{
-						Value: "The text",
+						Value: "a",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+						},
+					},
+					Element{
+						Name: "br",
+						Range: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 8,
+								Line:  0,
+								Col:   8,
+							},
+						},
+					},
+					Text{
+						Value: "b",
+						Range: Range{
+							From: Position{
+								Index: 8,
+								Line:  0,
+								Col:   8,
+							},
+							To: Position{
+								Index: 9,
+								Line:  0,
+								Col:   9,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 13,
+						Line:  0,
+						Col:   13,
 					},
 				},
 			},
 		},
 		{
-			name:  "element: with self-closing child element",
-			input: `<a><b/></a>`,
+			name:  "element: svg with mixed case names and namespaced attributes",
+			input: `<svg viewBox="0 0 24 24"><linearGradient id="g"></linearGradient><use xlink:href="#g"/></svg>`,
 			expected: Element{
-				Name: "a",
+				Name: "svg",
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "viewBox",
+						Value: "0 0 24 24",
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 24,
+								Line:  0,
+								Col:   24,
+							},
+						},
+					},
+				},
 				Children: []Node{
 					Element{
-						Name: "b",
+						Name: "linearGradient",
+						Attributes: []Attribute{
+							ConstantAttribute{
+								Name:  "id",
+								Value: "g",
+								Range: Range{
+									From: Position{
+										Index: 41,
+										Line:  0,
+										Col:   41,
+									},
+									To: Position{
+										Index: 47,
+										Line:  0,
+										Col:   47,
+									},
+								},
+							},
+						},
+						Range: Range{
+							From: Position{
+								Index: 25,
+								Line:  0,
+								Col:   25,
+							},
+							To: Position{
+								Index: 65,
+								Line:  0,
+								Col:   65,
+							},
+						},
+					},
+					Element{
+						Name: "use",
+						Attributes: []Attribute{
+							ConstantAttribute{
+								Name:  "xlink:href",
+								Value: "#g",
+								Range: Range{
+									From: Position{
+										Index: 70,
+										Line:  0,
+										Col:   70,
+									},
+									To: Position{
+										Index: 85,
+										Line:  0,
+										Col:   85,
+									},
+								},
+							},
+						},
+						Range: Range{
+							From: Position{
+								Index: 65,
+								Line:  0,
+								Col:   65,
+							},
+							To: Position{
+								Index: 87,
+								Line:  0,
+								Col:   87,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 93,
+						Line:  0,
+						Col:   93,
 					},
 				},
 			},
 		},
 		{
-			name:  "element: with non-self-closing child element",
-			input: `<a><b></b></a>`,
+			name:  "element: namespaced element name",
+			input: `<svg:rect width="1"></svg:rect>`,
 			expected: Element{
-				Name: "a",
-				Children: []Node{
-					Element{
-						Name: "b",
+				Name: "svg:rect",
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "width",
+						Value: "1",
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  0,
+								Col:   10,
+							},
+							To: Position{
+								Index: 19,
+								Line:  0,
+								Col:   19,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 31,
+						Line:  0,
+						Col:   31,
 					},
 				},
 			},
 		},
 		{
-			name:  "element: containing space",
-			input: `<a> <b> </b> </a>`,
+			name:  "element: whitespace within pre is preserved",
+			input: "<pre>\n  a   b\n\n\t<b>c</b>  d\n</pre>",
 			expected: Element{
-				Name: "a",
+				Name: "pre",
 				Children: []Node{
-					Whitespace{Value: " "},
+					Text{
+						Value: "\n  ",
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 8,
+								Line:  1,
+								Col:   2,
+							},
+						},
+					},
+					Text{
+						Value: "a   b",
+						Range: Range{
+							From: Position{
+								Index: 8,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 13,
+								Line:  1,
+								Col:   7,
+							},
+						},
+					},
+					Text{
+						Value: "\n\n\t",
+						Range: Range{
+							From: Position{
+								Index: 13,
+								Line:  1,
+								Col:   7,
+							},
+							To: Position{
+								Index: 16,
+								Line:  3,
+								Col:   1,
+							},
+						},
+					},
 					Element{
 						Name: "b",
 						Children: []Node{
-							Whitespace{Value: " "},
+							Text{
+								Value: "c",
+								Range: Range{
+									From: Position{
+										Index: 19,
+										Line:  3,
+										Col:   4,
+									},
+									To: Position{
+										Index: 20,
+										Line:  3,
+										Col:   5,
+									},
+								},
+							},
 						},
-						TrailingSpace: SpaceHorizontal,
+						PreserveWhitespace: true,
+						Range: Range{
+							From: Position{
+								Index: 16,
+								Line:  3,
+								Col:   1,
+							},
+							To: Position{
+								Index: 24,
+								Line:  3,
+								Col:   9,
+							},
+						},
+					},
+					Text{
+						Value: "  ",
+						Range: Range{
+							From: Position{
+								Index: 24,
+								Line:  3,
+								Col:   9,
+							},
+							To: Position{
+								Index: 26,
+								Line:  3,
+								Col:   11,
+							},
+						},
+					},
+					Text{
+						Value: "d",
+						Range: Range{
+							From: Position{
+								Index: 26,
+								Line:  3,
+								Col:   11,
+							},
+							To: Position{
+								Index: 27,
+								Line:  3,
+								Col:   12,
+							},
+						},
+					},
+					Text{
+						Value: "\n",
+						Range: Range{
+							From: Position{
+								Index: 27,
+								Line:  3,
+								Col:   12,
+							},
+							To: Position{
+								Index: 28,
+								Line:  4,
+								Col:   0,
+							},
+						},
+					},
+				},
+				PreserveWhitespace: true,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 34,
+						Line:  4,
+						Col:   6,
 					},
 				},
 			},
 		},
 		{
-			name:  "element: with multiple child elements",
-			input: `<a><b></b><c><d/></c></a>`,
+			name:  "element: whitespace within textarea is preserved",
+			input: "<textarea>\n one  two\n</textarea>",
 			expected: Element{
-				Name: "a",
+				Name: "textarea",
 				Children: []Node{
-					Element{
-						Name: "b",
+					Text{
+						Value: "\n ",
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  0,
+								Col:   10,
+							},
+							To: Position{
+								Index: 12,
+								Line:  1,
+								Col:   1,
+							},
+						},
 					},
-					Element{
-						Name: "c",
-						Children: []Node{
-							Element{
-								Name: "d",
+					Text{
+						Value: "one  two",
+						Range: Range{
+							From: Position{
+								Index: 12,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 20,
+								Line:  1,
+								Col:   9,
+							},
+						},
+					},
+					Text{
+						Value: "\n",
+						Range: Range{
+							From: Position{
+								Index: 20,
+								Line:  1,
+								Col:   9,
+							},
+							To: Position{
+								Index: 21,
+								Line:  2,
+								Col:   0,
+							},
+						},
+					},
+				},
+				PreserveWhitespace: true,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 32,
+						Line:  2,
+						Col:   11,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: data-key attribute sets the key",
+			input: `<li data-key="a"></li>`,
+			expected: Element{
+				Name: "li",
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "data-key",
+						Value: "a",
+						Range: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 16,
+								Line:  0,
+								Col:   16,
 							},
 						},
 					},
 				},
+				Key: "a",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 22,
+						Line:  0,
+						Col:   22,
+					},
+				},
 			},
 		},
 		{
-			name:  "element: empty",
-			input: `<div></div>`,
+			name:  "element: key expression attribute sets the key",
+			input: `<li key={ item.ID }></li>`,
 			expected: Element{
-				Name: "div",
+				Name: "li",
+				Attributes: []Attribute{
+					ExpressionAttribute{
+						Name: "key",
+						Expression: Expression{
+							Value: "item.ID",
+							Range: Range{
+								From: Position{Index: 10, Line: 0, Col: 10},
+								To:   Position{Index: 17, Line: 0, Col: 17},
+							},
+						},
+					},
+				},
+				Key: "item.ID",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 25,
+						Line:  0,
+						Col:   25,
+					},
+				},
 			},
 		},
 		{
-			name:  "element: containing string expression",
-			input: `<div>{ "test" }</div>`,
+			name: "element: self-closing with conditional attribute",
+			input: `<hr style="padding: 10px" 
+			if true {
+				class="itIsTrue"
+			}
+/>`,
 			expected: Element{
-				Name: "div",
-				Children: []Node{
-					StringExpression{
+				Name: "hr",
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "style",
+						Value: "padding: 10px",
+						Range: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 25,
+								Line:  0,
+								Col:   25,
+							},
+						},
+					},
+					ConditionalAttribute{
 						Expression: Expression{
-							Value: `"test"`,
+							Value: "true",
 							Range: Range{
 								From: Position{
-									Index: 7,
-									Line:  0,
-									Col:   7,
+									Index: 33,
+									Line:  1,
+									Col:   6,
 								},
 								To: Position{
-									Index: 13,
-									Line:  0,
-									Col:   13,
+									Index: 37,
+									Line:  1,
+									Col:   10,
 								},
 							},
 						},
+						Then: []Attribute{
+							ConstantAttribute{
+								Name:  "class",
+								Value: "itIsTrue",
+								Range: Range{
+									From: Position{
+										Index: 44,
+										Line:  2,
+										Col:   4,
+									},
+									To: Position{
+										Index: 60,
+										Line:  2,
+										Col:   20,
+									},
+								},
+							},
+						},
+					},
+				},
+				IndentAttrs: true,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 68,
+						Line:  4,
+						Col:   2,
 					},
 				},
 			},
 		},
 		{
-			name:  "element: inputs can contain class attributes",
-			input: `<input  type="email" id="email" name="email" class={ "a", "b", "c",  templ.KV("c", false)}	placeholder="your@email.com" autocomplete="off"/>`,
+			name: "element: self-closing with conditional attribute with else block",
+			input: `<hr style="padding: 10px" 
+			if true {
+				class="itIsTrue"
+			} else {
+				class="itIsNotTrue"
+			}
+/>`,
 			expected: Element{
-				Name: "input",
+				Name: "hr",
 				Attributes: []Attribute{
 					ConstantAttribute{
-						Name:  "type",
-						Value: "email",
+						Name:  "style",
+						Value: "padding: 10px",
+						Range: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 25,
+								Line:  0,
+								Col:   25,
+							},
+						},
 					},
-					ConstantAttribute{
-						Name:  "id",
-						Value: "email",
+					ConditionalAttribute{
+						Expression: Expression{
+							Value: "true",
+							Range: Range{
+								From: Position{
+									Index: 33,
+									Line:  1,
+									Col:   6,
+								},
+								To: Position{
+									Index: 37,
+									Line:  1,
+									Col:   10,
+								},
+							},
+						},
+						Then: []Attribute{
+							ConstantAttribute{
+								Name:  "class",
+								Value: "itIsTrue",
+								Range: Range{
+									From: Position{
+										Index: 44,
+										Line:  2,
+										Col:   4,
+									},
+									To: Position{
+										Index: 60,
+										Line:  2,
+										Col:   20,
+									},
+								},
+							},
+						},
+						Else: []Attribute{
+							ConstantAttribute{
+								Name:  "class",
+								Value: "itIsNotTrue",
+								Range: Range{
+									From: Position{
+										Index: 77,
+										Line:  4,
+										Col:   4,
+									},
+									To: Position{
+										Index: 96,
+										Line:  4,
+										Col:   23,
+									},
+								},
+							},
+						},
+					},
+				},
+				IndentAttrs: true,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
 					},
+					To: Position{
+						Index: 104,
+						Line:  6,
+						Col:   2,
+					},
+				},
+			},
+		},
+		{
+			name: "element: open and close with conditional attribute",
+			input: `<p style="padding: 10px" 
+			if true {
+				class="itIsTrue"
+			}
+>Test</p>`,
+			expected: Element{
+				Name: "p",
+				Attributes: []Attribute{
 					ConstantAttribute{
-						Name:  "name",
-						Value: "email",
+						Name:  "style",
+						Value: "padding: 10px",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 24,
+								Line:  0,
+								Col:   24,
+							},
+						},
 					},
-					ExpressionAttribute{
-						Name: "class",
+					ConditionalAttribute{
 						Expression: Expression{
-							Value: `"a", "b", "c",  templ.KV("c", false)`,
+							Value: "true",
 							Range: Range{
 								From: Position{
-									Index: 53,
-									Line:  0,
-									Col:   53,
+									Index: 32,
+									Line:  1,
+									Col:   6,
 								},
 								To: Position{
-									Index: 89,
-									Line:  0,
-									Col:   89,
+									Index: 36,
+									Line:  1,
+									Col:   10,
+								},
+							},
+						},
+						Then: []Attribute{
+							ConstantAttribute{
+								Name:  "class",
+								Value: "itIsTrue",
+								Range: Range{
+									From: Position{
+										Index: 43,
+										Line:  2,
+										Col:   4,
+									},
+									To: Position{
+										Index: 59,
+										Line:  2,
+										Col:   20,
+									},
 								},
 							},
 						},
 					},
-					ConstantAttribute{
-						Name:  "placeholder",
-						Value: "your@email.com",
+				},
+				IndentAttrs: true,
+				Children: []Node{
+					Text{
+						Value: "Test",
+						Range: Range{
+							From: Position{
+								Index: 66,
+								Line:  4,
+								Col:   1,
+							},
+							To: Position{
+								Index: 70,
+								Line:  4,
+								Col:   5,
+							},
+						},
 					},
-					ConstantAttribute{
-						Name:  "autocomplete",
-						Value: "off",
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 74,
+						Line:  4,
+						Col:   9,
 					},
 				},
 			},
 		},
-	}
+		{
+			name:  "element: open and close",
+			input: `<a></a>`,
+			expected: Element{
+				Name: "a",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 7,
+						Line:  0,
+						Col:   7,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: open and close with text",
+			input: `<a>The text</a>`,
+			expected: Element{
+				Name: "a",
+				Children: []Node{
+					Text{
+						Value: "The text",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 11,
+								Line:  0,
+								Col:   11,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 15,
+						Line:  0,
+						Col:   15,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: with self-closing child element",
+			input: `<a><b/></a>`,
+			expected: Element{
+				Name: "a",
+				Children: []Node{
+					Element{
+						Name: "b",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 7,
+								Line:  0,
+								Col:   7,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 11,
+						Line:  0,
+						Col:   11,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: with non-self-closing child element",
+			input: `<a><b></b></a>`,
+			expected: Element{
+				Name: "a",
+				Children: []Node{
+					Element{
+						Name: "b",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 10,
+								Line:  0,
+								Col:   10,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 14,
+						Line:  0,
+						Col:   14,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: containing space",
+			input: `<a> <b> </b> </a>`,
+			expected: Element{
+				Name: "a",
+				Children: []Node{
+					Whitespace{Value: " "},
+					Element{
+						Name: "b",
+						Children: []Node{
+							Whitespace{Value: " "},
+						},
+						TrailingSpace: SpaceHorizontal,
+						Range: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 12,
+								Line:  0,
+								Col:   12,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  0,
+						Col:   17,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: with multiple child elements",
+			input: `<a><b></b><c><d/></c></a>`,
+			expected: Element{
+				Name: "a",
+				Children: []Node{
+					Element{
+						Name: "b",
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 10,
+								Line:  0,
+								Col:   10,
+							},
+						},
+					},
+					Element{
+						Name: "c",
+						Children: []Node{
+							Element{
+								Name: "d",
+								Range: Range{
+									From: Position{
+										Index: 13,
+										Line:  0,
+										Col:   13,
+									},
+									To: Position{
+										Index: 17,
+										Line:  0,
+										Col:   17,
+									},
+								},
+							},
+						},
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  0,
+								Col:   10,
+							},
+							To: Position{
+								Index: 21,
+								Line:  0,
+								Col:   21,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 25,
+						Line:  0,
+						Col:   25,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: empty",
+			input: `<div></div>`,
+			expected: Element{
+				Name: "div",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 11,
+						Line:  0,
+						Col:   11,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: containing string expression",
+			input: `<div>{ "test" }</div>`,
+			expected: Element{
+				Name: "div",
+				Children: []Node{
+					StringExpression{
+						Expression: Expression{
+							Value: `"test"`,
+							Range: Range{
+								From: Position{
+									Index: 7,
+									Line:  0,
+									Col:   7,
+								},
+								To: Position{
+									Index: 13,
+									Line:  0,
+									Col:   13,
+								},
+							},
+						},
+						Range: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 15,
+								Line:  0,
+								Col:   15,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 21,
+						Line:  0,
+						Col:   21,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: inputs can contain class attributes",
+			input: `<input  type="email" id="email" name="email" class={ "a", "b", "c",  templ.KV("c", false)}	placeholder="your@email.com" autocomplete="off"/>`,
+			expected: Element{
+				Name: "input",
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "type",
+						Value: "email",
+						Range: Range{
+							From: Position{
+								Index: 8,
+								Line:  0,
+								Col:   8,
+							},
+							To: Position{
+								Index: 20,
+								Line:  0,
+								Col:   20,
+							},
+						},
+					},
+					ConstantAttribute{
+						Name:  "id",
+						Value: "email",
+						Range: Range{
+							From: Position{
+								Index: 21,
+								Line:  0,
+								Col:   21,
+							},
+							To: Position{
+								Index: 31,
+								Line:  0,
+								Col:   31,
+							},
+						},
+					},
+					ConstantAttribute{
+						Name:  "name",
+						Value: "email",
+						Range: Range{
+							From: Position{
+								Index: 32,
+								Line:  0,
+								Col:   32,
+							},
+							To: Position{
+								Index: 44,
+								Line:  0,
+								Col:   44,
+							},
+						},
+					},
+					ClassAttribute{
+						Name: "class",
+						Classes: []ClassPart{
+							{
+								Expression: Expression{
+									Value: `"a"`,
+									Range: Range{From: Position{Index: 53, Line: 0, Col: 53}, To: Position{Index: 56, Line: 0, Col: 56}},
+								},
+							},
+							{
+								Expression: Expression{
+									Value: `"b"`,
+									Range: Range{From: Position{Index: 58, Line: 0, Col: 58}, To: Position{Index: 61, Line: 0, Col: 61}},
+								},
+							},
+							{
+								Expression: Expression{
+									Value: `"c"`,
+									Range: Range{From: Position{Index: 63, Line: 0, Col: 63}, To: Position{Index: 66, Line: 0, Col: 66}},
+								},
+							},
+							{
+								Expression: Expression{
+									Value: `templ.KV("c", false)`,
+									Range: Range{From: Position{Index: 69, Line: 0, Col: 69}, To: Position{Index: 89, Line: 0, Col: 89}},
+								},
+							},
+						},
+						Expression: Expression{
+							Value: `"a", "b", "c",  templ.KV("c", false)`,
+							Range: Range{
+								From: Position{
+									Index: 53,
+									Line:  0,
+									Col:   53,
+								},
+								To: Position{
+									Index: 89,
+									Line:  0,
+									Col:   89,
+								},
+							},
+						},
+					},
+					ConstantAttribute{
+						Name:  "placeholder",
+						Value: "your@email.com",
+						Range: Range{
+							From: Position{
+								Index: 91,
+								Line:  0,
+								Col:   91,
+							},
+							To: Position{
+								Index: 119,
+								Line:  0,
+								Col:   119,
+							},
+						},
+					},
+					ConstantAttribute{
+						Name:  "autocomplete",
+						Value: "off",
+						Range: Range{
+							From: Position{
+								Index: 120,
+								Line:  0,
+								Col:   120,
+							},
+							To: Position{
+								Index: 138,
+								Line:  0,
+								Col:   138,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 140,
+						Line:  0,
+						Col:   140,
+					},
+				},
+			},
+		},
+	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -999,6 +2939,46 @@ func TestElementParserErrors(t *testing.T) {
 					Col:   3,
 				}),
 		},
+		{
+			name:  "element: swapped end tags report the innermost element",
+			input: "<div>\n\t<span>text</div></span>",
+			expected: parse.Error("<span>: mismatched end tag, expected '</span>', got '</div>'",
+				parse.Position{
+					Index: 17,
+					Line:  1,
+					Col:   11,
+				}),
+		},
+		{
+			name:  "element: missing end tags report the innermost unclosed element",
+			input: "<div><p><b>text</p></div>",
+			expected: parse.Error("<b>: mismatched end tag, expected '</b>', got '</p>'",
+				parse.Position{
+					Index: 15,
+					Line:  0,
+					Col:   15,
+				}),
+		},
+		{
+			name:  "element: missing end tag at the end of the input",
+			input: "<div><span>text",
+			expected: parse.Error("<span>: expected end tag '</span>' not present or invalid tag contents",
+				parse.Position{
+					Index: 11,
+					Line:  0,
+					Col:   11,
+				}),
+		},
+		{
+			name:  "element: void elements can't have children",
+			input: `<p><img src="x">content</img></p>`,
+			expected: parse.Error("<img>: void elements can't have children",
+				parse.Position{
+					Index: 23,
+					Line:  0,
+					Col:   23,
+				}),
+		},
 		{
 			name:  "element: style must only contain text",
 			input: `<style><button /></style>`,
@@ -1097,3 +3077,660 @@ func TestBigElement(t *testing.T) {
 		t.Errorf("unexpected failure to parse")
 	}
 }
+
+func TestElementAttributeMutation(t *testing.T) {
+	parseElement := func(t *testing.T) Element {
+		result, ok, err := element.Parse(parse.NewInput(`<input type="text" disabled?={ isDisabled } name={ name } required/>`))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse element: %v", err)
+		}
+		return result.(Element)
+	}
+
+	t.Run("existing attributes are overwritten in place", func(t *testing.T) {
+		e := parseElement(t)
+		original := e
+		e.SetAttr("NAME", "q")
+		e.SetAttr("type", "search")
+		expected := []Attribute{
+			ConstantAttribute{Name: "type", Value: "search"},
+			e.Attributes[1],
+			ConstantAttribute{Name: "NAME", Value: "q"},
+			original.Attributes[3],
+		}
+		if diff := cmp.Diff(expected, e.Attributes); diff != "" {
+			t.Error(diff)
+		}
+		if _, ok := e.Attributes[1].(BoolExpressionAttribute); !ok {
+			t.Errorf("expected the boolean expression attribute to be kept, got %#v", e.Attributes[1])
+		}
+		if _, ok := original.Attributes[2].(ExpressionAttribute); !ok {
+			t.Errorf("expected the original element to be unchanged, got %#v", original.Attributes[2])
+		}
+	})
+	t.Run("missing attributes are added", func(t *testing.T) {
+		e := parseElement(t)
+		e.SetAttr("id", "search")
+		if attr, ok := e.Attr("id"); !ok || attr.(ConstantAttribute).Value != "search" {
+			t.Errorf("expected the id attribute to be added, got %#v", e.Attributes)
+		}
+		if len(e.Attributes) != 5 {
+			t.Errorf("expected 5 attributes, got %d", len(e.Attributes))
+		}
+	})
+	t.Run("attributes of each kind are removed", func(t *testing.T) {
+		e := parseElement(t)
+		for _, name := range []string{"type", "Disabled", "name", "required"} {
+			if !e.RemoveAttr(name) {
+				t.Errorf("expected %s to be removed", name)
+			}
+			if e.HasAttr(name) {
+				t.Errorf("expected %s to be missing after it's removed", name)
+			}
+		}
+		if len(e.Attributes) != 0 {
+			t.Errorf("expected no attributes, got %#v", e.Attributes)
+		}
+		if e.RemoveAttr("type") {
+			t.Error("expected missing attributes not to be removed")
+		}
+	})
+	t.Run("the key is updated", func(t *testing.T) {
+		e := parseElement(t)
+		e.SetAttr("key", "a")
+		if e.Key != "a" {
+			t.Errorf("expected key a, got %q", e.Key)
+		}
+		e.RemoveAttr("key")
+		if e.Key != "" {
+			t.Errorf("expected no key, got %q", e.Key)
+		}
+	})
+}
+
+func TestElementDuplicateAttributes(t *testing.T) {
+	parseElement := func(t *testing.T) Element {
+		result, ok, err := element.Parse(parse.NewInput(`<div class="a" id="x" CLASS="b" { attrs... } class={ c }></div>`))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse element: %v", err)
+		}
+		return result.(Element)
+	}
+
+	t.Run("duplicates are kept when parsed", func(t *testing.T) {
+		e := parseElement(t)
+		if len(e.Attributes) != 5 {
+			t.Fatalf("expected 5 attributes, got %d", len(e.Attributes))
+		}
+		w := new(strings.Builder)
+		if err := e.Write(w, 0); err != nil {
+			t.Fatalf("failed to write element: %v", err)
+		}
+		expected := `<div class="a" id="x" CLASS="b" { attrs... } class={ c }></div>`
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+		if attr, _ := e.Attr("class"); attr.(ConstantAttribute).Value != "a" {
+			t.Errorf("expected the first class attribute, got %#v", attr)
+		}
+	})
+	t.Run("DeduplicateAttributes keeps the first of each name", func(t *testing.T) {
+		e := parseElement(t)
+		original := e
+		if err := e.DeduplicateAttributes(false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []Attribute{
+			original.Attributes[0],
+			original.Attributes[1],
+			original.Attributes[3],
+		}
+		if diff := cmp.Diff(expected, e.Attributes); diff != "" {
+			t.Error(diff)
+		}
+		if len(original.Attributes) != 5 {
+			t.Errorf("expected the original element to be unchanged, got %d attributes", len(original.Attributes))
+		}
+	})
+	t.Run("DeduplicateAttributes returns an error in strict mode", func(t *testing.T) {
+		e := parseElement(t)
+		err := e.DeduplicateAttributes(true)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if diff := cmp.Diff(`<div>: duplicate attribute "class"`, err.Error()); diff != "" {
+			t.Error(diff)
+		}
+		if len(e.Attributes) != 5 {
+			t.Errorf("expected the attributes to be unchanged, got %d", len(e.Attributes))
+		}
+	})
+}
+
+func TestElementAttributeLookup(t *testing.T) {
+	input := parse.NewInput(`<input TYPE="text" disabled?={ isDisabled } class="  a   b
+	c " required/>`)
+	result, ok, err := element.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("unexpected failure to parse")
+	}
+	e := result.(Element)
+
+	t.Run("attributes are found case-insensitively", func(t *testing.T) {
+		attr, ok := e.Attr("type")
+		if !ok {
+			t.Fatal("expected type attribute to be found")
+		}
+		if diff := cmp.Diff("text", attr.(ConstantAttribute).Value); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("expression attributes are found", func(t *testing.T) {
+		if !e.HasAttr("disabled") {
+			t.Error("expected disabled attribute to be found")
+		}
+	})
+	t.Run("boolean attributes are found", func(t *testing.T) {
+		if !e.HasAttr("Required") {
+			t.Error("expected required attribute to be found")
+		}
+	})
+	t.Run("missing attributes are not found", func(t *testing.T) {
+		if attr, ok := e.Attr("name"); ok {
+			t.Errorf("expected name attribute to be missing, got %#v", attr)
+		}
+		if e.HasAttr("name") {
+			t.Error("expected HasAttr to return false")
+		}
+	})
+	t.Run("class lists ignore extra whitespace", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"a", "b", "c"}, e.ClassList()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("elements without a class attribute have an empty class list", func(t *testing.T) {
+		if classes := (Element{Name: "div"}).ClassList(); classes != nil {
+			t.Errorf("expected nil, got %v", classes)
+		}
+	})
+}
+
+func TestElementParserConditionalAttributesBeforeSelfClose(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "space before self-close",
+			input: `<input type="text" if required { required } /><span>after</span>`,
+		},
+		{
+			name:  "no space before self-close",
+			input: `<input type="text" if required { required }/><span>after</span>`,
+		},
+		{
+			name: "multiline group with else",
+			input: `<input type="text" if required {
+	required
+} else {
+	placeholder="optional"
+} /><span>after</span>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := element.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			e := result.(Element)
+			if len(e.Attributes) != 2 {
+				t.Fatalf("expected 2 attributes, got %d", len(e.Attributes))
+			}
+			ca, ok := e.Attributes[1].(ConditionalAttribute)
+			if !ok {
+				t.Fatalf("expected a conditional attribute, got %T", e.Attributes[1])
+			}
+			if diff := cmp.Diff([]Attribute{BoolConstantAttribute{Name: "required"}}, ca.Then, cmpopts.IgnoreTypes(Range{})); diff != "" {
+				t.Error(diff)
+			}
+			if len(e.Children) != 0 {
+				t.Errorf("expected no children, got %d", len(e.Children))
+			}
+			// The self-close must be consumed with the element, leaving the sibling.
+			rest, _ := input.Peek(-1)
+			if rest != "<span>after</span>" {
+				t.Errorf("expected the element to end after the self-close, remaining input: %q", rest)
+			}
+		})
+	}
+}
+
+func TestEventHandlerAttributes(t *testing.T) {
+	input := parse.NewInput(`templ List(items []Item) {
+	for _, item := range items {
+		<button onclick={ onClickHandler(item.ID, "select") } onmouseover={ handler } title={ title(item) }>{ item.Name }</button>
+	}
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+	buttons := tem.InteractiveElements()
+	if len(buttons) != 1 {
+		t.Fatalf("expected 1 button, got %d", len(buttons))
+	}
+	attrs := buttons[0].Attributes
+
+	expected := &EventHandler{
+		Name: "onClickHandler",
+		Args: []Expression{
+			{
+				Value: "item.ID",
+				Range: Range{
+					From: Position{Index: 92, Line: 2, Col: 35},
+					To:   Position{Index: 99, Line: 2, Col: 42},
+				},
+			},
+			{
+				Value: `"select"`,
+				Range: Range{
+					From: Position{Index: 101, Line: 2, Col: 44},
+					To:   Position{Index: 109, Line: 2, Col: 52},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, attrs[0].(ExpressionAttribute).Handler); diff != "" {
+		t.Error(diff)
+	}
+	// Handlers that aren't calls, and attributes that aren't events, are left as expressions.
+	if h := attrs[1].(ExpressionAttribute).Handler; h != nil {
+		t.Errorf("expected no handler for a plain expression, got %#v", h)
+	}
+	if h := attrs[2].(ExpressionAttribute).Handler; h != nil {
+		t.Errorf("expected no handler for a non-event attribute, got %#v", h)
+	}
+}
+
+func TestElementParserBoolConstantAttributes(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []Attribute
+	}{
+		{
+			name:  "several bare attributes",
+			input: `<video autoplay controls muted></video>`,
+			expected: []Attribute{
+				BoolConstantAttribute{
+					Name: "autoplay",
+					Range: Range{
+						From: Position{
+							Index: 7,
+							Line:  0,
+							Col:   7,
+						},
+						To: Position{
+							Index: 15,
+							Line:  0,
+							Col:   15,
+						},
+					},
+				},
+				BoolConstantAttribute{
+					Name: "controls",
+					Range: Range{
+						From: Position{
+							Index: 16,
+							Line:  0,
+							Col:   16,
+						},
+						To: Position{
+							Index: 24,
+							Line:  0,
+							Col:   24,
+						},
+					},
+				},
+				BoolConstantAttribute{
+					Name: "muted",
+					Range: Range{
+						From: Position{
+							Index: 25,
+							Line:  0,
+							Col:   25,
+						},
+						To: Position{
+							Index: 30,
+							Line:  0,
+							Col:   30,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "bare and valued attributes",
+			input: `<input disabled type="text" required value="x"/>`,
+			expected: []Attribute{
+				BoolConstantAttribute{
+					Name: "disabled",
+					Range: Range{
+						From: Position{
+							Index: 7,
+							Line:  0,
+							Col:   7,
+						},
+						To: Position{
+							Index: 15,
+							Line:  0,
+							Col:   15,
+						},
+					},
+				},
+				ConstantAttribute{
+					Name:  "type",
+					Value: "text",
+					Range: Range{
+						From: Position{
+							Index: 16,
+							Line:  0,
+							Col:   16,
+						},
+						To: Position{
+							Index: 27,
+							Line:  0,
+							Col:   27,
+						},
+					},
+				},
+				BoolConstantAttribute{
+					Name: "required",
+					Range: Range{
+						From: Position{
+							Index: 28,
+							Line:  0,
+							Col:   28,
+						},
+						To: Position{
+							Index: 36,
+							Line:  0,
+							Col:   36,
+						},
+					},
+				},
+				ConstantAttribute{
+					Name:  "value",
+					Value: "x",
+					Range: Range{
+						From: Position{
+							Index: 37,
+							Line:  0,
+							Col:   37,
+						},
+						To: Position{
+							Index: 46,
+							Line:  0,
+							Col:   46,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "bare attribute before the end of the tag",
+			input: `<option value="a" selected>A</option>`,
+			expected: []Attribute{
+				ConstantAttribute{
+					Name:  "value",
+					Value: "a",
+					Range: Range{
+						From: Position{
+							Index: 8,
+							Line:  0,
+							Col:   8,
+						},
+						To: Position{
+							Index: 17,
+							Line:  0,
+							Col:   17,
+						},
+					},
+				},
+				BoolConstantAttribute{
+					Name: "selected",
+					Range: Range{
+						From: Position{
+							Index: 18,
+							Line:  0,
+							Col:   18,
+						},
+						To: Position{
+							Index: 26,
+							Line:  0,
+							Col:   26,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "unquoted values before the end of a self-closing tag",
+			input: `<input type=text name=q/>`,
+			expected: []Attribute{
+				ConstantAttribute{
+					Name:  "type",
+					Value: "text",
+					Range: Range{
+						From: Position{
+							Index: 7,
+							Line:  0,
+							Col:   7,
+						},
+						To: Position{
+							Index: 16,
+							Line:  0,
+							Col:   16,
+						},
+					},
+				},
+				ConstantAttribute{
+					Name:  "name",
+					Value: "q",
+					Range: Range{
+						From: Position{
+							Index: 17,
+							Line:  0,
+							Col:   17,
+						},
+						To: Position{
+							Index: 23,
+							Line:  0,
+							Col:   23,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "unquoted, single and double quoted values",
+			input: `<a href=/home title='say "hi"' class="nav" hidden>Home</a>`,
+			expected: []Attribute{
+				ConstantAttribute{
+					Name:  "href",
+					Value: "/home",
+					Range: Range{
+						From: Position{
+							Index: 3,
+							Line:  0,
+							Col:   3,
+						},
+						To: Position{
+							Index: 13,
+							Line:  0,
+							Col:   13,
+						},
+					},
+				},
+				ConstantAttribute{
+					Name:              "title",
+					Value:             `say "hi"`,
+					SingleQuote:       true,
+					SourceSingleQuote: true,
+					Range: Range{
+						From: Position{
+							Index: 14,
+							Line:  0,
+							Col:   14,
+						},
+						To: Position{
+							Index: 30,
+							Line:  0,
+							Col:   30,
+						},
+					},
+				},
+				ConstantAttribute{
+					Name:  "class",
+					Value: "nav",
+					Range: Range{
+						From: Position{
+							Index: 31,
+							Line:  0,
+							Col:   31,
+						},
+						To: Position{
+							Index: 42,
+							Line:  0,
+							Col:   42,
+						},
+					},
+				},
+				BoolConstantAttribute{
+					Name: "hidden",
+					Range: Range{
+						From: Position{
+							Index: 43,
+							Line:  0,
+							Col:   43,
+						},
+						To: Position{
+							Index: 49,
+							Line:  0,
+							Col:   49,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "bare attributes on separate lines",
+			input: `<input
+	disabled
+	name="q"
+	readonly
+/>`,
+			expected: []Attribute{
+				BoolConstantAttribute{
+					Name: "disabled",
+					Range: Range{
+						From: Position{
+							Index: 8,
+							Line:  1,
+							Col:   1,
+						},
+						To: Position{
+							Index: 16,
+							Line:  1,
+							Col:   9,
+						},
+					},
+				},
+				ConstantAttribute{
+					Name:  "name",
+					Value: "q",
+					Range: Range{
+						From: Position{
+							Index: 18,
+							Line:  2,
+							Col:   1,
+						},
+						To: Position{
+							Index: 26,
+							Line:  2,
+							Col:   9,
+						},
+					},
+				},
+				BoolConstantAttribute{
+					Name: "readonly",
+					Range: Range{
+						From: Position{
+							Index: 28,
+							Line:  3,
+							Col:   1,
+						},
+						To: Position{
+							Index: 36,
+							Line:  3,
+							Col:   9,
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := element.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			if diff := cmp.Diff(tt.expected, result.(Element).Attributes); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestElementParserManyVoidSiblings(t *testing.T) {
+	// Each void element used to parse all of its following siblings to look for its end tag,
+	// which took exponential time.
+	const n = 1000
+	input := "<p>" + strings.Repeat("a<br>", n) + "</p>"
+	actual, ok, err := element.Parse(parse.NewInput(input))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if children := len(actual.(Element).Children); children != n*2 {
+		t.Errorf("expected %d children, got %d", n*2, children)
+	}
+}
+
+func BenchmarkElementParserVoidSiblings(b *testing.B) {
+	input := "<p>" + strings.Repeat("a<br><input type=\"text\">", 100) + "</p>"
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		if _, ok, err := element.Parse(parse.NewInput(input)); err != nil || !ok {
+			b.Fatalf("failed to parse: %v", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// characterReference matches a named, decimal or hexadecimal character reference that's
+// terminated by a semicolon, e.g. `&amp;`, `&#39;` or `&#x27;`.
+var characterReference = regexp.MustCompile(`&(?:[a-zA-Z][a-zA-Z0-9]*|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+
+// unescapeEntities decodes the character references in s. References without a terminating
+// semicolon, and names that aren't HTML entities, e.g. `&notanentity;`, are left as they are.
+func unescapeEntities(s string) string {
+	return characterReference.ReplaceAllStringFunc(s, func(ref string) string {
+		r := html.UnescapeString(ref)
+		// UnescapeString decodes the longest entity at the start of the name, e.g. `&not` in
+		// `&notanentity;`, so if any of the name is left over, it's not an entity.
+		if r != ";" && strings.HasSuffix(r, ";") {
+			return ref
+		}
+		return r
+	})
+}
+
+// unescapeTextEntities decodes the character references in text nodes, and marks them as
+// Unescaped, so that they're encoded again when they're written.
+func unescapeTextEntities(nodes []Node) []Node {
+	return mapNodeLists(nodes, func(nodes []Node) []Node {
+		for i, n := range nodes {
+			if t, ok := n.(Text); ok && !t.Unescaped {
+				t.Value = unescapeEntities(t.Value)
+				t.Unescaped = true
+				nodes[i] = t
+			}
+		}
+		return nodes
+	})
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+)
+
+func TestUnescapeEntities(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "named",
+			input:    "Tom &amp; Jerry &lt;3 &copy;",
+			expected: "Tom & Jerry <3 ©",
+		},
+		{
+			name:     "decimal",
+			input:    "Tom&#39;s &#8364;5",
+			expected: "Tom's €5",
+		},
+		{
+			name:     "hexadecimal",
+			input:    "Tom&#x27;s &#X20AC;5",
+			expected: "Tom's €5",
+		},
+		{
+			name:     "semicolon",
+			input:    "a&semi;b",
+			expected: "a;b",
+		},
+		{
+			name:     "unknown names are left as they are",
+			input:    "&notanentity; &unknown;",
+			expected: "&notanentity; &unknown;",
+		},
+		{
+			name:     "references without a semicolon are left as they are",
+			input:    "&amp &#39 & a",
+			expected: "&amp &#39 & a",
+		},
+		{
+			name:     "malformed numeric references are left as they are",
+			input:    "&#; &#x; &#xZZ;",
+			expected: "&#; &#x; &#xZZ;",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := unescapeEntities(tt.input)
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTemplateFileParserUnescapeEntities(t *testing.T) {
+	input := `package main
+
+templ Name() {
+	<p>Tom &amp; Jerry&#39;s &#x3C;3 &notanentity;</p>
+}
+`
+	var tests = []struct {
+		name             string
+		unescapeEntities bool
+		expectedText     string
+		expectedHTML     string
+	}{
+		{
+			name:             "text is HTML encoded by default",
+			unescapeEntities: false,
+			expectedText:     "Tom &amp; Jerry&#39;s &#x3C;3 &notanentity;",
+			expectedHTML:     "<p>Tom &amp; Jerry&#39;s &#x3C;3 &notanentity;</p> ",
+		},
+		{
+			name:             "entities are decoded when the option is set, and encoded when rendered",
+			unescapeEntities: true,
+			expectedText:     "Tom & Jerry's <3 &notanentity;",
+			expectedHTML:     "<p>Tom &amp; Jerry&#39;s &lt;3 &amp;notanentity;</p> ",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTemplateFileParser("main")
+			p.UnescapeEntities = tt.unescapeEntities
+			tf, ok, err := p.Parse(parse.NewInput(input))
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatal("failed to parse template file")
+			}
+			para := firstElement(t, tf.Nodes[0].(HTMLTemplate).Children)
+			text := para.Children[0].(Text)
+			if text.Value != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, text.Value)
+			}
+			w := new(strings.Builder)
+			if err := Render(w, []Node{para}); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if w.String() != tt.expectedHTML {
+				t.Errorf("expected HTML %q, got %q", tt.expectedHTML, w.String())
+			}
+		})
+	}
+}
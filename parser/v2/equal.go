@@ -0,0 +1,86 @@
+package parser
+
+import "reflect"
+
+// Equal returns true if the nodes have the same structure and content, e.g. the same element
+// names, attributes, Go expressions and children, even if they were parsed from templates that
+// are laid out differently.
+//
+// Metadata about the source is ignored: source ranges, the Source retained with
+// TemplateFileParser.RetainSource, diagnostics, quote styles, and whether attributes and children
+// are indented. Whitespace is only compared by whether there is any, since any amount of it is
+// rendered as a single space.
+func Equal(a, b Node) bool {
+	return equalValues(reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+}
+
+// EqualNodes returns true if the lists have the same length, and each pair of nodes is Equal.
+func EqualNodes(a, b []Node) bool {
+	return equalValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+var (
+	whitespaceType    = reflect.TypeOf(Whitespace{})
+	trailingSpaceType = reflect.TypeOf(SpaceNone)
+)
+
+// layoutFields are the fields that describe how a node is written in the template, rather than
+// what it contains.
+var layoutFields = map[string]bool{
+	"Source":            true,
+	"Diagnostics":       true,
+	"IndentAttrs":       true,
+	"IndentChildren":    true,
+	"SingleQuote":       true,
+	"SourceSingleQuote": true,
+	"Multiline":         true,
+}
+
+func equalValues(a, b reflect.Value) bool {
+	if a.IsValid() != b.IsValid() {
+		return false
+	}
+	if !a.IsValid() {
+		return true
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Type() {
+	case rangeType:
+		return true
+	case whitespaceType:
+		return (a.Interface().(Whitespace).Value == "") == (b.Interface().(Whitespace).Value == "")
+	case trailingSpaceType:
+		return (a.Interface().(TrailingSpace) == SpaceNone) == (b.Interface().(TrailingSpace) == SpaceNone)
+	}
+	switch a.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if !f.IsExported() || layoutFields[f.Name] {
+				continue
+			}
+			if !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return a.Interface() == b.Interface()
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEqual(t *testing.T) {
+	var tests = []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{
+			name: "differently indented templates are equal",
+			a: `templ x(items []string) {
+	<ul
+		class="list"
+		id="items"
+	>
+		for _, item := range items {
+			<li>{ item }</li>
+		}
+	</ul>
+	<p>Hello,   { name }</p>
+}`,
+			b: `templ x(items []string) {
+  <ul class='list' id="items">
+      for _, item := range items {
+          <li>{ item }</li>
+      }
+  </ul>
+  <p>Hello,   { name }</p>
+}`,
+			expected: true,
+		},
+		{
+			name: "different attribute values are not equal",
+			a: `templ x() {
+	<a href="/a">A</a>
+}`,
+			b: `templ x() {
+	<a href="/b">A</a>
+}`,
+		},
+		{
+			name: "different text is not equal",
+			a: `templ x() {
+	<p>Hello</p>
+}`,
+			b: `templ x() {
+	<p>Hi</p>
+}`,
+		},
+		{
+			name: "different expressions are not equal",
+			a: `templ x() {
+	<p>{ a }</p>
+}`,
+			b: `templ x() {
+	<p>{ b }</p>
+}`,
+		},
+		{
+			name: "removing whitespace between nodes is not equal",
+			a: `templ x() {
+	<b>a</b> <i>b</i>
+}`,
+			b: `templ x() {
+	<b>a</b><i>b</i>
+}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			a, ok, err := template.Parse(parse.NewInput(tt.a))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse a: %v", err)
+			}
+			b, ok, err := template.Parse(parse.NewInput(tt.b))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse b: %v", err)
+			}
+			if actual := EqualNodes(a.Children, b.Children); actual != tt.expected {
+				t.Errorf("expected EqualNodes to be %v, got %v", tt.expected, actual)
+			}
+			if tt.expected && cmp.Equal(a.Children, b.Children) {
+				t.Error("expected the parsed nodes to differ in their positions")
+			}
+			for i := 0; i < len(a.Children) && i < len(b.Children); i++ {
+				if tt.expected && !Equal(a.Children[i], b.Children[i]) {
+					t.Errorf("expected node %d to be equal", i)
+				}
+			}
+		})
+	}
+}
+
+func TestEqualNodeTypes(t *testing.T) {
+	if Equal(Text{Value: "a"}, RawHTML{Value: "a"}) {
+		t.Error("expected nodes of different types not to be equal")
+	}
+	if !Equal(nil, nil) {
+		t.Error("expected nil nodes to be equal")
+	}
+	if Equal(Text{Value: "a"}, nil) {
+		t.Error("expected a node not to equal nil")
+	}
+	if !EqualNodes(nil, []Node{}) {
+		t.Error("expected nil and empty lists to be equal")
+	}
+}
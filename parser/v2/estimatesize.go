@@ -0,0 +1,123 @@
+package parser
+
+// Heuristics used when estimating the size of dynamic content, which can't be known until
+// the template is rendered.
+const (
+	estimatedExpressionSize = 16
+	estimatedTemplateSize   = 64
+	estimatedLoopIterations = 4
+)
+
+// EstimateSize returns an approximate byte length of the rendered output of the template.
+// Static text and attributes are counted exactly, while expressions, template calls and
+// loops use a fixed heuristic. The result is intended for pre-sizing buffers, not as an
+// exact measurement.
+func (t HTMLTemplate) EstimateSize() int {
+	return estimateNodesSize(t.Children)
+}
+
+func estimateNodesSize(nodes []Node) (size int) {
+	for _, n := range nodes {
+		size += estimateNodeSize(n)
+	}
+	return size
+}
+
+func estimateNodeSize(node Node) int {
+	switch n := node.(type) {
+	case Text:
+		return len(n.Value) + len(n.TrailingSpace)
+	case Whitespace:
+		if n.Value == "" {
+			return 0
+		}
+		return 1
+	case DocType:
+		return len("<!DOCTYPE >") + len(n.Value)
+	case HTMLComment:
+		return len("<!---->") + len(n.Contents)
+	case CData:
+		return len("<![CDATA[]]>") + len(n.Value)
+	case Element:
+		size := estimateAttributesSize(n.Attributes)
+		if n.IsVoidElement() {
+			return size + len("<>") + len(n.Name) + len(n.TrailingSpace)
+		}
+		size += len("<></>") + len(n.Name)*2
+		return size + estimateNodesSize(n.Children) + len(n.TrailingSpace)
+	case RawElement:
+		size := estimateAttributesSize(n.Attributes)
+		return size + len("<></>") + len(n.Name)*2 + len(n.Contents)
+	case StringExpression:
+		return estimatedExpressionSize + len(n.TrailingSpace)
+	case InlineIfExpression:
+		return estimatedExpressionSize + len(n.TrailingSpace)
+	case TranslationExpression:
+		return estimatedExpressionSize + len(n.TrailingSpace)
+	case CallTemplateExpression:
+		return estimatedTemplateSize
+	case AssetExpression:
+		return len(n.Path) + estimatedExpressionSize
+	case RawHTML:
+		return len(n.Value)
+	case JSONScriptExpression:
+		return len(`<script type="application/json" id=""></script>`) + estimatedExpressionSize*2
+	case TemplElementExpression:
+		return estimatedTemplateSize + estimateNodesSize(n.Children)
+	case OnceExpression:
+		return estimateNodesSize(n.Children)
+	case FragmentBlock:
+		return estimateNodesSize(n.Children)
+	case IfExpression:
+		// Only one branch is rendered, so use the largest.
+		size := estimateNodesSize(n.Then)
+		for _, elseIf := range n.ElseIfs {
+			size = maxInt(size, estimateNodesSize(elseIf.Then))
+		}
+		return maxInt(size, estimateNodesSize(n.Else))
+	case SwitchExpression:
+		var size int
+		for _, c := range n.Cases {
+			size = maxInt(size, estimateNodesSize(c.Children))
+		}
+		return size
+	case ForExpression:
+		return estimateNodesSize(n.Children) * estimatedLoopIterations
+	}
+	return 0
+}
+
+func estimateAttributesSize(attrs []Attribute) (size int) {
+	for _, a := range attrs {
+		// Each attribute is preceded by a space.
+		size++
+		switch a := a.(type) {
+		case BoolConstantAttribute:
+			size += len(a.Name)
+		case ConstantAttribute:
+			size += len(a.Name) + len(`=""`) + len(a.Value)
+		case StyleAttribute:
+			size += len(`style=""`) + len(a.Value())
+		case SrcsetAttribute:
+			size += len(`srcset=""`) + len(a.Value())
+		case BoolExpressionAttribute:
+			size += len(a.Name)
+		case ExpressionAttribute:
+			size += len(a.Name) + len(`=""`) + estimatedExpressionSize
+		case ClassAttribute:
+			size += len(a.Name) + len(`=""`) + estimatedExpressionSize
+		case SpreadAttributes:
+			size += estimatedExpressionSize
+		case ConditionalAttribute:
+			size += maxInt(estimateAttributesSize(a.Then), estimateAttributesSize(a.Else))
+		}
+	}
+	return size
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+)
+
+func TestEstimateSize(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+		min   int
+		max   int
+	}{
+		{
+			name: "static content is counted exactly",
+			input: `templ Name() {
+	<div class="a"><p>Hello</p></div>
+}`,
+			min: len(`<div class="a"><p>Hello</p></div>`),
+			max: len(`<div class="a"><p>Hello</p></div>`) + 2,
+		},
+		{
+			name: "void elements do not have a closing tag",
+			input: `templ Name() {
+	<input type="text"/>
+}`,
+			min: len(`<input type="text">`),
+			max: len(`<input type="text">`) + 2,
+		},
+		{
+			name: "expressions use a heuristic",
+			input: `templ Name(name string) {
+	<div><span>Name: </span>{ name }</div>
+}`,
+			min: len(`<div><span>Name: </span></div>`) + 1,
+			max: len(`<div><span>Name: </span></div>`) + 64,
+		},
+		{
+			name: "only the largest branch of an if expression is counted",
+			input: `templ Name(ok bool) {
+	if ok {
+		<p>Success, the operation completed</p>
+	} else {
+		<p>Failure</p>
+	}
+}`,
+			min: len(`<p>Success, the operation completed</p>`),
+			max: len(`<p>Success, the operation completed</p>`) + 4,
+		},
+		{
+			name: "loop contents are multiplied",
+			input: `templ Name(items []string) {
+	<ul>
+		for _, item := range items {
+			<li>{ item }</li>
+		}
+	</ul>
+}`,
+			min: len(`<ul><li></li><li></li></ul>`),
+			max: len(`<ul></ul>`) + (len(`<li></li>`)+64)*8,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			tem, ok, err := template.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			actual := tem.EstimateSize()
+			if actual < tt.min || actual > tt.max {
+				t.Errorf("expected estimate between %d and %d, got %d", tt.min, tt.max, actual)
+			}
+		})
+	}
+}
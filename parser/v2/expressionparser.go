@@ -31,10 +31,43 @@ var gt = parse.Rune('>')
 var openBrace = parse.String("{")
 var optionalSpaces = parse.StringFrom(parse.Optional(
 	parse.AtLeast(1, parse.Rune(' '))))
-var openBraceWithPadding = parse.StringFrom(optionalSpaces,
-	openBrace,
-	optionalSpaces)
-var openBraceWithOptionalPadding = parse.Any(openBraceWithPadding, openBrace)
+var openBraceWithOptionalSpace = parse.Or(parse.String("{ "), openBrace)
+
+// openBraceWithOptionalPadding matches `{`, with optional spaces either side. It's used at the
+// start of each block, so it avoids allocating.
+var openBraceWithOptionalPadding = parse.Func(func(pi *parse.Input) (s string, ok bool, err error) {
+	start := pi.Index()
+	takeSpaces(pi)
+	if !peekPrefix(pi, "{") {
+		pi.Seek(start)
+		return s, false, nil
+	}
+	pi.Take(len("{"))
+	takeSpaces(pi)
+	return sourceSince(pi, start), true, nil
+})
+
+// openBraceAndNewLine is the start of a block, e.g. ` {\n` after `if ok`.
+var openBraceAndNewLine = parse.Func(func(pi *parse.Input) (s string, ok bool, err error) {
+	start := pi.Index()
+	if _, ok, err = openBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		return
+	}
+	if _, ok, err = parse.NewLine.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+	return sourceSince(pi, start), true, nil
+})
+
+func takeSpaces(pi *parse.Input) {
+	for {
+		if s, ok := pi.Peek(1); !ok || s != " " {
+			return
+		}
+		pi.Take(1)
+	}
+}
 
 var closeBrace = parse.String("}")
 var closeBraceWithPadding = parse.String(" }")
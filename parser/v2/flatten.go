@@ -0,0 +1,512 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Flatten returns the nodes the template renders for data, with if, switch and for
+// expressions replaced by the nodes they render, e.g. for snapshot testing. The contents of
+// each for loop are repeated once per item. Other expressions, such as string expressions and
+// attribute values, are left as-is, so the result is a halfway point between the template and
+// its output.
+//
+// data provides the values of the template's parameters, and is either a map[string]any keyed
+// by parameter name, or a struct, where each parameter is a field with the same name, or the
+// same name starting with an upper case letter. Only a subset of Go is evaluated: literals,
+// identifiers, fields, indexing, len, and unary, comparison and logical operators. Loops must
+// range over a slice, array, map or string.
+func Flatten(t HTMLTemplate, data any) ([]Node, error) {
+	f := flattener{scope: map[string]reflect.Value{}}
+	f.data = reflect.ValueOf(data)
+	return f.nodes(t.Children)
+}
+
+type flattener struct {
+	data  reflect.Value
+	scope map[string]reflect.Value
+}
+
+func (f flattener) nodes(nodes []Node) (op []Node, err error) {
+	for _, n := range nodes {
+		var flattened []Node
+		if flattened, err = f.node(n); err != nil {
+			return nil, err
+		}
+		op = append(op, flattened...)
+	}
+	return op, nil
+}
+
+func (f flattener) node(node Node) (op []Node, err error) {
+	switch n := node.(type) {
+	case IfExpression:
+		return f.ifExpression(n)
+	case SwitchExpression:
+		return f.switchExpression(n)
+	case ForExpression:
+		return f.forExpression(n)
+	case Element:
+		if n.Children, err = f.nodes(n.Children); err != nil {
+			return nil, err
+		}
+		return []Node{n}, nil
+	case TemplElementExpression:
+		if n.Children, err = f.nodes(n.Children); err != nil {
+			return nil, err
+		}
+		return []Node{n}, nil
+	case OnceExpression:
+		if n.Children, err = f.nodes(n.Children); err != nil {
+			return nil, err
+		}
+		return []Node{n}, nil
+	case FragmentBlock:
+		if n.Children, err = f.nodes(n.Children); err != nil {
+			return nil, err
+		}
+		return []Node{n}, nil
+	}
+	return []Node{node}, nil
+}
+
+func (f flattener) ifExpression(n IfExpression) ([]Node, error) {
+	ok, err := f.evalBool(n.Expression.Value)
+	if err != nil || ok {
+		return f.flattenOrError(n.Then, err)
+	}
+	for _, elseIf := range n.ElseIfs {
+		ok, err = f.evalBool(elseIf.Expression.Value)
+		if err != nil || ok {
+			return f.flattenOrError(elseIf.Then, err)
+		}
+	}
+	return f.nodes(n.Else)
+}
+
+func (f flattener) flattenOrError(nodes []Node, err error) ([]Node, error) {
+	if err != nil {
+		return nil, err
+	}
+	return f.nodes(nodes)
+}
+
+func (f flattener) switchExpression(n SwitchExpression) ([]Node, error) {
+	// Parse the whole statement, so that the case expressions can be evaluated.
+	var src strings.Builder
+	src.WriteString("switch " + n.Expression.Value + " {\n")
+	for _, c := range n.Cases {
+		src.WriteString(c.Expression.Value + "\n")
+	}
+	src.WriteString("}")
+	stmt, err := parseStmt(src.String())
+	if err != nil {
+		return nil, err
+	}
+	sw, ok := stmt.(*ast.SwitchStmt)
+	if !ok || sw.Init != nil {
+		return nil, fmt.Errorf("flatten: unsupported switch %q", n.Expression.Value)
+	}
+	tag := reflect.ValueOf(true)
+	if sw.Tag != nil {
+		if tag, err = f.eval(sw.Tag); err != nil {
+			return nil, err
+		}
+	}
+	defaultCase := -1
+	for i, c := range sw.Body.List {
+		clause := c.(*ast.CaseClause)
+		if clause.List == nil {
+			defaultCase = i
+			continue
+		}
+		for _, e := range clause.List {
+			v, err := f.eval(e)
+			if err != nil {
+				return nil, err
+			}
+			if equal, err := compare(token.EQL, tag, v); err != nil || equal {
+				return f.flattenOrError(n.Cases[i].Children, err)
+			}
+		}
+	}
+	if defaultCase >= 0 {
+		return f.nodes(n.Cases[defaultCase].Children)
+	}
+	return nil, nil
+}
+
+func (f flattener) forExpression(n ForExpression) (op []Node, err error) {
+	stmt, err := parseStmt("for " + n.Expression.Value + " {}")
+	if err != nil {
+		return nil, err
+	}
+	rs, ok := stmt.(*ast.RangeStmt)
+	if !ok {
+		return nil, fmt.Errorf("flatten: unsupported for loop %q, only range loops can be flattened", n.Expression.Value)
+	}
+	x, err := f.eval(rs.X)
+	if err != nil {
+		return nil, err
+	}
+	iterate := func(k, v reflect.Value) error {
+		inner := f.withVar(rs.Key, k).withVar(rs.Value, v)
+		children, err := inner.nodes(n.Children)
+		op = append(op, children...)
+		return err
+	}
+	switch x.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < x.Len(); i++ {
+			if err = iterate(reflect.ValueOf(i), x.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Map:
+		keys := x.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			if err = iterate(k, x.MapIndex(k)); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.String:
+		for i, r := range x.String() {
+			if err = iterate(reflect.ValueOf(i), reflect.ValueOf(r)); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("flatten: can't range over %s in %q", x.Kind(), n.Expression.Value)
+	}
+	return op, nil
+}
+
+func (f flattener) withVar(e ast.Expr, v reflect.Value) flattener {
+	ident, ok := e.(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return f
+	}
+	scope := make(map[string]reflect.Value, len(f.scope)+1)
+	for k, v := range f.scope {
+		scope[k] = v
+	}
+	scope[ident.Name] = v
+	f.scope = scope
+	return f
+}
+
+func parseStmt(src string) (ast.Stmt, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package main\nfunc templ_container() {\n"+src+"\n}", 0)
+	if err != nil {
+		return nil, fmt.Errorf("flatten: %w", err)
+	}
+	body := file.Decls[0].(*ast.FuncDecl).Body.List
+	if len(body) != 1 {
+		return nil, fmt.Errorf("flatten: expected a single statement in %q", src)
+	}
+	return body[0], nil
+}
+
+func (f flattener) evalBool(src string) (bool, error) {
+	e, err := parser.ParseExpr(strings.TrimSpace(src))
+	if err != nil {
+		return false, fmt.Errorf("flatten: unsupported condition %q: %w", src, err)
+	}
+	v, err := f.eval(e)
+	if err != nil {
+		return false, err
+	}
+	if v.Kind() != reflect.Bool {
+		return false, fmt.Errorf("flatten: condition %q is not a bool", src)
+	}
+	return v.Bool(), nil
+}
+
+func (f flattener) eval(e ast.Expr) (reflect.Value, error) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return f.eval(e.X)
+	case *ast.BasicLit:
+		return literal(e)
+	case *ast.Ident:
+		return f.lookup(e.Name)
+	case *ast.SelectorExpr:
+		x, err := f.eval(e.X)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return field(x, e.Sel.Name)
+	case *ast.IndexExpr:
+		x, err := f.eval(e.X)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		index, err := f.eval(e.Index)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return indexValue(x, index)
+	case *ast.CallExpr:
+		if fn, ok := e.Fun.(*ast.Ident); ok && fn.Name == "len" && len(e.Args) == 1 {
+			x, err := f.eval(e.Args[0])
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			switch indirect(x).Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map, reflect.String, reflect.Chan:
+				return reflect.ValueOf(indirect(x).Len()), nil
+			}
+		}
+	case *ast.UnaryExpr:
+		x, err := f.eval(e.X)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		switch {
+		case e.Op == token.NOT && x.Kind() == reflect.Bool:
+			return reflect.ValueOf(!x.Bool()), nil
+		case e.Op == token.SUB && isInt(x):
+			return reflect.ValueOf(-toInt(x)), nil
+		case e.Op == token.SUB && isFloat(x):
+			return reflect.ValueOf(-toFloat(x)), nil
+		}
+	case *ast.BinaryExpr:
+		return f.binary(e)
+	}
+	return reflect.Value{}, fmt.Errorf("flatten: unsupported expression %q", exprString(e))
+}
+
+func (f flattener) binary(e *ast.BinaryExpr) (reflect.Value, error) {
+	x, err := f.eval(e.X)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if e.Op == token.LAND || e.Op == token.LOR {
+		if x.Kind() != reflect.Bool {
+			return reflect.Value{}, fmt.Errorf("flatten: %q is not a bool", exprString(e.X))
+		}
+		// Short circuit, so that conditions like `p != nil && p.OK` can be evaluated.
+		if (e.Op == token.LAND && !x.Bool()) || (e.Op == token.LOR && x.Bool()) {
+			return x, nil
+		}
+		y, err := f.eval(e.Y)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if y.Kind() != reflect.Bool {
+			return reflect.Value{}, fmt.Errorf("flatten: %q is not a bool", exprString(e.Y))
+		}
+		return y, nil
+	}
+	y, err := f.eval(e.Y)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	ok, err := compare(e.Op, x, y)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("flatten: %q: %w", exprString(e), err)
+	}
+	return reflect.ValueOf(ok), nil
+}
+
+func (f flattener) lookup(name string) (reflect.Value, error) {
+	switch name {
+	case "true":
+		return reflect.ValueOf(true), nil
+	case "false":
+		return reflect.ValueOf(false), nil
+	case "nil":
+		return reflect.Value{}, nil
+	}
+	if v, ok := f.scope[name]; ok {
+		return v, nil
+	}
+	if v, err := field(f.data, name); err == nil {
+		return v, nil
+	}
+	if r, size := utf8.DecodeRuneInString(name); size > 0 && unicode.IsLower(r) {
+		if v, err := field(f.data, string(unicode.ToUpper(r))+name[size:]); err == nil {
+			return v, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("flatten: %q not found in data", name)
+}
+
+func literal(e *ast.BasicLit) (reflect.Value, error) {
+	v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	switch v.Kind() {
+	case constant.String:
+		return reflect.ValueOf(constant.StringVal(v)), nil
+	case constant.Int:
+		if e.Kind == token.CHAR {
+			i, _ := constant.Int64Val(v)
+			return reflect.ValueOf(rune(i)), nil
+		}
+		i, _ := constant.Int64Val(v)
+		return reflect.ValueOf(int(i)), nil
+	case constant.Float:
+		fv, _ := constant.Float64Val(v)
+		return reflect.ValueOf(fv), nil
+	}
+	return reflect.Value{}, fmt.Errorf("flatten: unsupported literal %s", e.Value)
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func field(v reflect.Value, name string) (reflect.Value, error) {
+	v = indirect(v)
+	switch v.Kind() {
+	case reflect.Struct:
+		if f := v.FieldByName(name); f.IsValid() {
+			return f, nil
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			if f := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); f.IsValid() {
+				return f, nil
+			}
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("flatten: field %q not found", name)
+}
+
+func indexValue(x, index reflect.Value) (reflect.Value, error) {
+	x = indirect(x)
+	switch x.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		if !isInt(index) {
+			return reflect.Value{}, fmt.Errorf("flatten: index must be an integer")
+		}
+		i := int(toInt(index))
+		if i < 0 || i >= x.Len() {
+			return reflect.Value{}, fmt.Errorf("flatten: index %d out of range", i)
+		}
+		return x.Index(i), nil
+	case reflect.Map:
+		index = indirect(index)
+		if !index.Type().ConvertibleTo(x.Type().Key()) {
+			return reflect.Value{}, fmt.Errorf("flatten: invalid map key")
+		}
+		v := x.MapIndex(index.Convert(x.Type().Key()))
+		if !v.IsValid() {
+			return reflect.Zero(x.Type().Elem()), nil
+		}
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("flatten: can't index %s", x.Kind())
+}
+
+func isInt(v reflect.Value) bool {
+	switch indirect(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func toInt(v reflect.Value) int64 {
+	v = indirect(v)
+	if v.CanInt() {
+		return v.Int()
+	}
+	return int64(v.Uint())
+}
+
+func isFloat(v reflect.Value) bool {
+	k := indirect(v).Kind()
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func toFloat(v reflect.Value) float64 {
+	if isInt(v) {
+		return float64(toInt(v))
+	}
+	return indirect(v).Float()
+}
+
+func isNil(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+func compare(op token.Token, x, y reflect.Value) (bool, error) {
+	// Comparisons against nil.
+	if !x.IsValid() || !y.IsValid() {
+		equal := isNil(x) && isNil(y)
+		switch op {
+		case token.EQL:
+			return equal, nil
+		case token.NEQ:
+			return !equal, nil
+		}
+		return false, fmt.Errorf("invalid comparison with nil")
+	}
+	x, y = indirect(x), indirect(y)
+	switch {
+	case (isInt(x) || isFloat(x)) && (isInt(y) || isFloat(y)):
+		a, b := toFloat(x), toFloat(y)
+		if isInt(x) && isInt(y) {
+			// Compare as integers to avoid losing precision.
+			return compareOrdered(op, toInt(x), toInt(y))
+		}
+		return compareOrdered(op, a, b)
+	case x.Kind() == reflect.String && y.Kind() == reflect.String:
+		return compareOrdered(op, x.String(), y.String())
+	case x.Kind() == reflect.Bool && y.Kind() == reflect.Bool:
+		switch op {
+		case token.EQL:
+			return x.Bool() == y.Bool(), nil
+		case token.NEQ:
+			return x.Bool() != y.Bool(), nil
+		}
+	}
+	return false, fmt.Errorf("unsupported comparison of %s and %s", x.Kind(), y.Kind())
+}
+
+func compareOrdered[T int64 | float64 | string](op token.Token, a, b T) (bool, error) {
+	switch op {
+	case token.EQL:
+		return a == b, nil
+	case token.NEQ:
+		return a != b, nil
+	case token.LSS:
+		return a < b, nil
+	case token.LEQ:
+		return a <= b, nil
+	case token.GTR:
+		return a > b, nil
+	case token.GEQ:
+		return a >= b, nil
+	}
+	return false, fmt.Errorf("unsupported operator %s", op)
+}
+
+func exprString(e ast.Expr) string {
+	var sb strings.Builder
+	_ = printer.Fprint(&sb, token.NewFileSet(), e)
+	return sb.String()
+}
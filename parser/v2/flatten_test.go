@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFlatten(t *testing.T) {
+	input := parse.NewInput(`templ Name(p Person) {
+	if p.Admin && len(p.Roles) > 0 {
+		<h1>Admin</h1>
+	} else if p.Name == "" {
+		<h1>Anonymous</h1>
+	} else {
+		<h1>{ p.Name }</h1>
+	}
+	<ul>
+		for _, role := range p.Roles {
+			switch role {
+				case "owner":
+					<li class="owner">{ role }</li>
+				default:
+					<li>{ role }</li>
+			}
+		}
+	</ul>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+
+	type person struct {
+		Name  string
+		Admin bool
+		Roles []string
+	}
+	var tests = []struct {
+		name     string
+		data     any
+		expected string
+	}{
+		{
+			name:     "struct data",
+			data:     struct{ P person }{P: person{Name: "Alice", Roles: []string{"owner", "editor"}}},
+			expected: `<h1>{ p.Name }</h1><ul><li class="owner">{ role }</li><li>{ role }</li></ul>`,
+		},
+		{
+			name:     "map data",
+			data:     map[string]any{"p": person{Admin: true, Roles: []string{"editor"}}},
+			expected: `<h1>Admin</h1><ul><li>{ role }</li></ul>`,
+		},
+		{
+			name:     "else if",
+			data:     map[string]any{"p": &person{}},
+			expected: `<h1>Anonymous</h1><ul></ul>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := Flatten(tem, tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var sb strings.Builder
+			for _, n := range nodes {
+				if err := n.Write(&sb, 0); err != nil {
+					t.Fatalf("failed to write node: %v", err)
+				}
+			}
+			actual := strings.Join(strings.Fields(sb.String()), "")
+			expected := strings.Join(strings.Fields(tt.expected), "")
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFlattenErrors(t *testing.T) {
+	input := parse.NewInput(`templ Name(items []string) {
+	if isValid(items) {
+		<p>Valid</p>
+	}
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if _, err = Flatten(tem, map[string]any{"items": []string{}}); err == nil {
+		t.Fatal("expected an error for an unsupported expression")
+	}
+	if _, err = Flatten(tem, map[string]any{}); err == nil {
+		t.Fatal("expected an error for missing data")
+	}
+}
@@ -28,7 +28,7 @@ func (_ forExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error)
 	}
 
 	// Eat " {\n".
-	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+	if _, ok, err = openBraceAndNewLine.Parse(pi); err != nil || !ok {
 		err = parse.Error("for: "+unterminatedMissingCurly, pi.PositionAt(start))
 		return
 	}
@@ -48,6 +48,7 @@ func (_ forExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error)
 		err = parse.Error("for: "+unterminatedMissingEnd, pi.Position())
 		return
 	}
+	r.Range = sourceRange(pi, start)
 
 	return r, true, nil
 }
@@ -55,9 +55,45 @@ func TestForExpressionParser(t *testing.T) {
 										},
 									},
 								},
+								Range: Range{
+									From: Position{
+										Index: 41,
+										Line:  1,
+										Col:   10,
+									},
+									To: Position{
+										Index: 49,
+										Line:  1,
+										Col:   18,
+									},
+								},
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 36,
+								Line:  1,
+								Col:   5,
+							},
+							To: Position{
+								Index: 55,
+								Line:  1,
+								Col:   24,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 61,
+						Line:  2,
+						Col:   5,
 					},
 				},
 			},
@@ -104,9 +140,385 @@ func TestForExpressionParser(t *testing.T) {
 										},
 									},
 								},
+								Range: Range{
+									From: Position{
+										Index: 40,
+										Line:  1,
+										Col:   10,
+									},
+									To: Position{
+										Index: 48,
+										Line:  1,
+										Col:   18,
+									},
+								},
+							},
+						},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 35,
+								Line:  1,
+								Col:   5,
+							},
+							To: Position{
+								Index: 54,
+								Line:  1,
+								Col:   24,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 60,
+						Line:  2,
+						Col:   5,
+					},
+				},
+			},
+		},
+		{
+			name: "for: range over a slice",
+			input: `for i, item := range items {
+	<li>{ item }</li>
+}`,
+			expected: ForExpression{
+				Expression: Expression{
+					Value: `i, item := range items`,
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 26,
+							Line:  0,
+							Col:   26,
+						},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t"},
+					Element{
+						Name: "li",
+						Children: []Node{
+							StringExpression{
+								Expression: Expression{
+									Value: `item`,
+									Range: Range{
+										From: Position{
+											Index: 36,
+											Line:  1,
+											Col:   7,
+										},
+										To: Position{
+											Index: 40,
+											Line:  1,
+											Col:   11,
+										},
+									},
+								},
+								Range: Range{
+									From: Position{
+										Index: 34,
+										Line:  1,
+										Col:   5,
+									},
+									To: Position{
+										Index: 42,
+										Line:  1,
+										Col:   13,
+									},
+								},
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 30,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 47,
+								Line:  1,
+								Col:   18,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 49,
+						Line:  2,
+						Col:   1,
+					},
+				},
+			},
+		},
+		{
+			name: "for: range over a map with key and value",
+			input: `for k, v := range m {
+	<li>{ k }</li>
+}`,
+			expected: ForExpression{
+				Expression: Expression{
+					Value: `k, v := range m`,
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 19,
+							Line:  0,
+							Col:   19,
+						},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t"},
+					Element{
+						Name: "li",
+						Children: []Node{
+							StringExpression{
+								Expression: Expression{
+									Value: `k`,
+									Range: Range{
+										From: Position{
+											Index: 29,
+											Line:  1,
+											Col:   7,
+										},
+										To: Position{
+											Index: 30,
+											Line:  1,
+											Col:   8,
+										},
+									},
+								},
+								Range: Range{
+									From: Position{
+										Index: 27,
+										Line:  1,
+										Col:   5,
+									},
+									To: Position{
+										Index: 32,
+										Line:  1,
+										Col:   10,
+									},
+								},
+							},
+						},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 23,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 37,
+								Line:  1,
+								Col:   15,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 39,
+						Line:  2,
+						Col:   1,
+					},
+				},
+			},
+		},
+		{
+			name: "for: range with index only",
+			input: `for i := range n {
+	<li>{ i }</li>
+}`,
+			expected: ForExpression{
+				Expression: Expression{
+					Value: `i := range n`,
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 16,
+							Line:  0,
+							Col:   16,
+						},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t"},
+					Element{
+						Name: "li",
+						Children: []Node{
+							StringExpression{
+								Expression: Expression{
+									Value: `i`,
+									Range: Range{
+										From: Position{
+											Index: 26,
+											Line:  1,
+											Col:   7,
+										},
+										To: Position{
+											Index: 27,
+											Line:  1,
+											Col:   8,
+										},
+									},
+								},
+								Range: Range{
+									From: Position{
+										Index: 24,
+										Line:  1,
+										Col:   5,
+									},
+									To: Position{
+										Index: 29,
+										Line:  1,
+										Col:   10,
+									},
+								},
+							},
+						},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 20,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 34,
+								Line:  1,
+								Col:   15,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 36,
+						Line:  2,
+						Col:   1,
+					},
+				},
+			},
+		},
+		{
+			name: "for: three clause loop",
+			input: `for i := 0; i < 10; i++ {
+	<li>{ i }</li>
+}`,
+			expected: ForExpression{
+				Expression: Expression{
+					Value: `i := 0; i < 10; i++`,
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 23,
+							Line:  0,
+							Col:   23,
+						},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t"},
+					Element{
+						Name: "li",
+						Children: []Node{
+							StringExpression{
+								Expression: Expression{
+									Value: `i`,
+									Range: Range{
+										From: Position{
+											Index: 33,
+											Line:  1,
+											Col:   7,
+										},
+										To: Position{
+											Index: 34,
+											Line:  1,
+											Col:   8,
+										},
+									},
+								},
+								Range: Range{
+									From: Position{
+										Index: 31,
+										Line:  1,
+										Col:   5,
+									},
+									To: Position{
+										Index: 36,
+										Line:  1,
+										Col:   10,
+									},
+								},
+							},
+						},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 27,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 41,
+								Line:  1,
+								Col:   15,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 43,
+						Line:  2,
+						Col:   1,
 					},
 				},
 			},
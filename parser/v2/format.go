@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// QuoteStyle sets the quotes used around constant attribute values when a template file is
+// formatted.
+type QuoteStyle int
+
+const (
+	// QuoteStyleDefault uses double quotes, unless the value contains a double quote.
+	QuoteStyleDefault QuoteStyle = iota
+	// QuoteStyleDouble uses double quotes, escaping any double quotes in the value.
+	QuoteStyleDouble
+	// QuoteStyleSingle uses single quotes, escaping any single quotes in the value.
+	QuoteStyleSingle
+	// QuoteStylePreserve uses the quotes that were used in the template.
+	QuoteStylePreserve
+)
+
+// ParseQuoteStyle parses a quote style name, one of "default", "double", "single" or
+// "preserve".
+func ParseQuoteStyle(s string) (QuoteStyle, error) {
+	switch s {
+	case "", "default":
+		return QuoteStyleDefault, nil
+	case "double":
+		return QuoteStyleDouble, nil
+	case "single":
+		return QuoteStyleSingle, nil
+	case "preserve":
+		return QuoteStylePreserve, nil
+	}
+	return QuoteStyleDefault, fmt.Errorf("unknown quote style %q, expected one of default, double, single or preserve", s)
+}
+
+// FormatOptions customise how a template file is formatted, see TemplateFile.Format.
+type FormatOptions struct {
+	QuoteStyle QuoteStyle
+}
+
+// Format parses the template file source, and returns it formatted, like `templ fmt`.
+// Elements are indented by their depth, attributes and the braces of expressions are
+// separated by a single space, and consecutive blank lines are collapsed to one.
+func Format(input string) (string, error) {
+	tf, err := ParseString(input)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err = tf.Write(&sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Format writes the formatted template file to w, like Write, applying the options.
+func (tf TemplateFile) Format(w io.Writer, opts FormatOptions) error {
+	if opts.QuoteStyle != QuoteStyleDefault {
+		nodes := make([]TemplateFileNode, len(tf.Nodes))
+		for i, n := range tf.Nodes {
+			if t, ok := n.(HTMLTemplate); ok {
+				t.Children = requoteAttributes(t.Children, opts.QuoteStyle)
+				n = t
+			}
+			nodes[i] = n
+		}
+		tf.Nodes = nodes
+	}
+	return tf.Write(w)
+}
+
+func requoteAttributes(nodes []Node, style QuoteStyle) []Node {
+	requote := func(a Attribute) Attribute {
+		ca, ok := a.(ConstantAttribute)
+		if !ok {
+			return a
+		}
+		switch style {
+		case QuoteStyleDouble:
+			ca.SingleQuote = false
+		case QuoteStyleSingle:
+			ca.SingleQuote = true
+		case QuoteStylePreserve:
+			ca.SingleQuote = ca.SourceSingleQuote
+		}
+		return ca
+	}
+	return mapNodeLists(nodes, func(nodes []Node) []Node {
+		for i, n := range nodes {
+			switch n := n.(type) {
+			case Element:
+				n.Attributes = mapAttributes(n.Attributes, requote)
+				nodes[i] = n
+			case RawElement:
+				n.Attributes = mapAttributes(n.Attributes, requote)
+				nodes[i] = n
+			}
+		}
+		return nodes
+	})
+}
@@ -34,6 +34,14 @@ func TestFormat(t *testing.T) {
 			if diff := cmp.Diff(string(a.Files[1].Data), actual.String()); diff != "" {
 				t.Fatalf("%s:\n%s", file, diff)
 			}
+			// Formatting the output again doesn't change it.
+			reformatted, err := Format(actual.String())
+			if err != nil {
+				t.Fatalf("failed to format output: %v", err)
+			}
+			if diff := cmp.Diff(actual.String(), reformatted); diff != "" {
+				t.Errorf("%s: formatting isn't idempotent:\n%s", file, diff)
+			}
 		})
 	}
 }
@@ -43,3 +51,116 @@ func clean(b []byte) string {
 	b = bytes.TrimSuffix(b, []byte("\n"))
 	return string(b)
 }
+
+func TestFormatQuoteStyle(t *testing.T) {
+	input := `package main
+
+templ Name() {
+	<a href='/home' title="Home" data-quote='Say "hi"' data-name="O'Brien">Home</a>
+	if true {
+		<img alt='logo' src="/logo.png"/>
+	}
+}
+`
+	var tests = []struct {
+		name     string
+		style    QuoteStyle
+		expected string
+	}{
+		{
+			name:  "default",
+			style: QuoteStyleDefault,
+			expected: `package main
+
+templ Name() {
+	<a href="/home" title="Home" data-quote='Say "hi"' data-name="O'Brien">Home</a>
+	if true {
+		<img alt="logo" src="/logo.png"/>
+	}
+}
+`,
+		},
+		{
+			name:  "double",
+			style: QuoteStyleDouble,
+			expected: `package main
+
+templ Name() {
+	<a href="/home" title="Home" data-quote="Say &quot;hi&quot;" data-name="O'Brien">Home</a>
+	if true {
+		<img alt="logo" src="/logo.png"/>
+	}
+}
+`,
+		},
+		{
+			name:  "single",
+			style: QuoteStyleSingle,
+			expected: `package main
+
+templ Name() {
+	<a href='/home' title='Home' data-quote='Say "hi"' data-name='O&#39;Brien'>Home</a>
+	if true {
+		<img alt='logo' src='/logo.png'/>
+	}
+}
+`,
+		},
+		{
+			name:  "preserve",
+			style: QuoteStylePreserve,
+			expected: `package main
+
+templ Name() {
+	<a href='/home' title="Home" data-quote='Say "hi"' data-name="O'Brien">Home</a>
+	if true {
+		<img alt='logo' src="/logo.png"/>
+	}
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tem, err := ParseString(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual bytes.Buffer
+			if err := tem.Format(&actual, FormatOptions{QuoteStyle: tt.style}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual.String()); diff != "" {
+				t.Fatal(diff)
+			}
+			// The formatted output must parse to the same attribute values.
+			formatted, err := ParseString(actual.String())
+			if err != nil {
+				t.Fatalf("failed to parse formatted output: %v", err)
+			}
+			expectedValues := attributeValues(tem)
+			if diff := cmp.Diff(expectedValues, attributeValues(formatted)); diff != "" {
+				t.Errorf("attribute values changed:\n%s", diff)
+			}
+		})
+	}
+}
+
+func attributeValues(tf TemplateFile) (values []string) {
+	for _, n := range tf.Nodes {
+		t, ok := n.(HTMLTemplate)
+		if !ok {
+			continue
+		}
+		mapElements(t.Children, func(e Element) Element {
+			for _, a := range e.Attributes {
+				if ca, ok := a.(ConstantAttribute); ok {
+					values = append(values, ca.Value)
+				}
+			}
+			return e
+		})
+	}
+	return values
+}
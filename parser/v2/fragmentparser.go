@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/a-h/parse"
+)
+
+var fragmentBlock = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+
+	// Check the prefix first.
+	if !peekPrefix(pi, "@fragment \"", "@fragment `") {
+		return n, false, nil
+	}
+	pi.Take(len("@fragment "))
+
+	// Parse the name, which is a Go string literal.
+	var r FragmentBlock
+	src, _ := pi.Peek(-1)
+	quoted, err := strconv.QuotedPrefix(src)
+	if err != nil {
+		return r, false, parse.Error("@fragment: invalid name, expected a string literal", pi.Position())
+	}
+	if r.Name, err = strconv.Unquote(quoted); err != nil {
+		return r, false, parse.Error("@fragment: invalid name, expected a string literal", pi.Position())
+	}
+	pi.Take(len(quoted))
+
+	// {
+	if _, ok, err = openBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		err = parse.Error("@fragment: "+unterminatedMissingCurly, pi.PositionAt(start))
+		return
+	}
+
+	// Node contents.
+	np := newTemplateNodeParser(closeBraceWithOptionalPadding, "@fragment closing brace")
+	var nodes Nodes
+	if nodes, ok, err = np.Parse(pi); err != nil || !ok {
+		err = parse.Error("@fragment: expected nodes, but none were found", pi.Position())
+		return
+	}
+	r.Children = nodes.Nodes
+	r.Diagnostics = nodes.Diagnostics
+
+	// Read the required closing brace.
+	if _, ok, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		err = parse.Error("@fragment: "+unterminatedMissingEnd, pi.Position())
+		return
+	}
+
+	return r, true, nil
+})
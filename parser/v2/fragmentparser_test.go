@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFragmentBlockParser(t *testing.T) {
+	input := parse.NewInput(`@fragment "item-list" {
+	<ul></ul>
+}`)
+	actual, ok, err := fragmentBlock.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("unexpected failure to parse")
+	}
+	expected := FragmentBlock{
+		Name: "item-list",
+		Children: []Node{
+			Whitespace{Value: "\n\t"},
+			Element{
+				Name:          "ul",
+				TrailingSpace: SpaceVertical,
+				Range: Range{
+					From: Position{Index: 25, Line: 1, Col: 1},
+					To:   Position{Index: 34, Line: 1, Col: 10},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFragmentBlockParserErrors(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "unterminated name",
+			input: `@fragment "item-list {`,
+		},
+		{
+			name: "missing closing brace",
+			input: `@fragment "item-list" {
+	<ul></ul>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := fragmentBlock.Parse(parse.NewInput(tt.input))
+			if err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
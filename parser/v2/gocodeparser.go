@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+var goCode parse.Parser[Node] = goCodeParser{}
+
+type goCodeParser struct{}
+
+func (goCodeParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+	if !peekPrefix(pi, "{{") {
+		return nil, false, nil
+	}
+	pi.Take(len("{{"))
+
+	// Skip the whitespace before the code.
+	var r GoCode
+	ws, _, _ := parse.OptionalWhitespace.Parse(pi)
+	r.Multiline = strings.Contains(ws, "\n")
+
+	// Read the code until the closing braces.
+	from := pi.Index()
+	src, _ := pi.Peek(-1)
+	end, ok := goCodeEnd(src)
+	if !ok {
+		err = parse.Error("go code: missing end (expected '}}')", pi.PositionAt(start))
+		return r, false, err
+	}
+	code := strings.TrimRight(src[:end], " \t\r\n")
+	r.Expression = NewExpression(code, pi.PositionAt(from), pi.PositionAt(from+len(code)))
+	pi.Take(end + len("}}"))
+
+	// Parse trailing whitespace.
+	ws, _, err = parse.Whitespace.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	if r.TrailingSpace, err = NewTrailingSpace(ws); err != nil {
+		return r, false, err
+	}
+
+	return r, true, nil
+}
+
+// goCodeEnd returns the index of the `}}` that closes the Go code. Braces within the code, and
+// within strings and comments, are skipped.
+func goCodeEnd(src string) (end int, ok bool) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+	var depth int
+	unmatchedClose := -1
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			return 0, false
+		}
+		offset := file.Offset(pos)
+		switch tok {
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if unmatchedClose >= 0 && unmatchedClose == offset-1 {
+				return unmatchedClose, true
+			}
+			unmatchedClose = offset
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGoCodeParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected GoCode
+	}{
+		{
+			name:  "single line assignment",
+			input: `{{ x := compute() }}`,
+			expected: GoCode{
+				Expression: Expression{
+					Value: "x := compute()",
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 17, Line: 0, Col: 17},
+					},
+				},
+			},
+		},
+		{
+			name: "multiple statements",
+			input: `{{
+	total := 0
+	for _, item := range items {
+		total += item.Price
+	}
+}}
+`,
+			expected: GoCode{
+				Expression: Expression{
+					Value: "total := 0\n\tfor _, item := range items {\n\t\ttotal += item.Price\n\t}",
+					Range: Range{
+						From: Position{Index: 4, Line: 1, Col: 1},
+						To:   Position{Index: 69, Line: 4, Col: 2},
+					},
+				},
+				TrailingSpace: SpaceVertical,
+				Multiline:     true,
+			},
+		},
+		{
+			name:  "braces within composite literals, strings and comments",
+			input: `{{ m := map[string]struct{}{"}}": {}} /* }} */ }}<p>`,
+			expected: GoCode{
+				Expression: Expression{
+					Value: `m := map[string]struct{}{"}}": {}} /* }} */`,
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 46, Line: 0, Col: 46},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := goCode.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGoCodeParserErrors(t *testing.T) {
+	input := parse.NewInput(`{{ x := 1 }`)
+	_, _, err := goCode.Parse(input)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "missing end (expected '}}')") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGoCodeWrite(t *testing.T) {
+	input := `templ x() {
+	{{ x := 1 }}
+	{{
+			y := 2
+			if y > x {
+				y++
+			}
+	}}
+}`
+	expected := `templ x() {
+	{{ x := 1 }}
+	{{
+		y := 2
+		if y > x {
+			y++
+		}
+	}}
+}`
+	tem, ok, err := template.Parse(parse.NewInput(input))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var sb strings.Builder
+	if err = tem.Write(&sb, 0); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	if diff := cmp.Diff(expected, sb.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -14,6 +14,7 @@ var goSingleLineComment = goSingleLineCommentParser{}
 
 func (p goSingleLineCommentParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	// Comment start.
+	start := pi.Position()
 	var c GoComment
 	if _, ok, err = goSingleLineCommentStart.Parse(pi); err != nil || !ok {
 		return
@@ -24,6 +25,7 @@ func (p goSingleLineCommentParser) Parse(pi *parse.Input) (n Node, ok bool, err
 		err = parse.Error("expected end comment literal '\n' not found", pi.Position())
 		return
 	}
+	c.Range = NewRange(start, pi.Position())
 	// Move past the end element.
 	_, _, _ = goSingleLineCommentEnd.Parse(pi)
 	// Return the comment.
@@ -55,6 +57,7 @@ func (p goMultiLineCommentParser) Parse(pi *parse.Input) (n Node, ok bool, err e
 	}
 	// Move past the end element.
 	_, _, _ = goMultiLineCommentEnd.Parse(pi)
+	c.Range = NewRange(start, pi.Position())
 	// Return the comment.
 	c.Multiline = true
 	return c, true, nil
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/a-h/parse"
@@ -20,6 +21,10 @@ func TestGoCommentParser(t *testing.T) {
 			expected: GoComment{
 				Contents:  " single line comment",
 				Multiline: false,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 22, Line: 0, Col: 22},
+				},
 			},
 		},
 		{
@@ -28,6 +33,10 @@ func TestGoCommentParser(t *testing.T) {
 			expected: GoComment{
 				Contents:  " single line comment",
 				Multiline: false,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 22, Line: 0, Col: 22},
+				},
 			},
 		},
 		{
@@ -36,6 +45,10 @@ func TestGoCommentParser(t *testing.T) {
 			expected: GoComment{
 				Contents:  " multiline comment, on one line ",
 				Multiline: true,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 36, Line: 0, Col: 36},
+				},
 			},
 		},
 		{
@@ -45,6 +58,10 @@ on multiple lines */`,
 			expected: GoComment{
 				Contents:  " multiline comment,\non multiple lines ",
 				Multiline: true,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 42, Line: 1, Col: 20},
+				},
 			},
 		},
 	}
@@ -99,3 +116,59 @@ func TestCommentParserErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestGoCommentsWithinTemplates(t *testing.T) {
+	tem, ok, err := template.Parse(parse.NewInput(`templ x(url string) {
+	<a href="https://example.com/a">A</a>
+	// Line comment.
+	<a href={ url + "//b" }>B</a>
+	/* Block
+	comment. */
+	<p>{ "//" }</p>
+}`))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var comments []GoComment
+	var elements int
+	Walk(tem.Children, func(n Node) bool {
+		switch n := n.(type) {
+		case GoComment:
+			comments = append(comments, n)
+		case Element:
+			elements++
+		}
+		return true
+	})
+	expected := []GoComment{
+		{
+			Contents: " Line comment.",
+			Range: Range{
+				From: Position{Index: 62, Line: 2, Col: 1},
+				To:   Position{Index: 78, Line: 2, Col: 17},
+			},
+		},
+		{
+			Contents:  " Block\n\tcomment. ",
+			Multiline: true,
+			Range: Range{
+				From: Position{Index: 111, Line: 4, Col: 1},
+				To:   Position{Index: 132, Line: 5, Col: 12},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, comments); diff != "" {
+		t.Error(diff)
+	}
+	if elements != 3 {
+		t.Errorf("expected 3 elements, got %d", elements)
+	}
+
+	w := new(strings.Builder)
+	if err := Render(w, []Node{comments[0], Element{Name: "br"}, comments[1]}); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if diff := cmp.Diff("<br>", w.String()); diff != "" {
+		t.Errorf("expected the comments not to be rendered:\n%s", diff)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"strings"
 	"unicode"
@@ -101,6 +102,126 @@ func Switch(content string) (start, end int, err error) {
 	})
 }
 
+// InlineIf is the location of the parts of an if statement that chooses between two
+// expressions, e.g. `if user != nil { user.Name } else { "Guest" }`. If there's no else
+// branch, ElseStart and ElseEnd are zero.
+type InlineIf struct {
+	CondStart, CondEnd int
+	ThenStart, ThenEnd int
+	ElseStart, ElseEnd int
+	// End is the position after the closing brace of the last branch.
+	End int
+}
+
+// IfExpr extracts an if statement where each branch contains a single expression.
+func IfExpr(content string) (r InlineIf, err error) {
+	if !strings.HasPrefix(content, "if") {
+		return r, ErrExpectedNodeNotFound
+	}
+	prefix := "package main\nfunc templ_container() {\n"
+	_, _, err = extract(content, func(src string, body []ast.Stmt) (start, end int, err error) {
+		stmt, ok := body[0].(*ast.IfStmt)
+		if !ok || stmt.Init != nil {
+			return 0, 0, ErrExpectedNodeNotFound
+		}
+		then, ok := singleExpression(stmt.Body)
+		if !ok {
+			return 0, 0, ErrExpectedNodeNotFound
+		}
+		r.CondStart, r.CondEnd = int(stmt.Cond.Pos())-1, int(stmt.Cond.End())-1
+		r.ThenStart, r.ThenEnd = int(then.Pos())-1, int(then.End())-1
+		r.End = int(stmt.Body.End()) - 1
+		if stmt.Else != nil {
+			elseBlock, ok := stmt.Else.(*ast.BlockStmt)
+			if !ok {
+				return 0, 0, ErrExpectedNodeNotFound
+			}
+			elseExpr, ok := singleExpression(elseBlock)
+			if !ok {
+				return 0, 0, ErrExpectedNodeNotFound
+			}
+			r.ElseStart, r.ElseEnd = int(elseExpr.Pos())-1, int(elseExpr.End())-1
+			r.End = int(elseBlock.End()) - 1
+		}
+		return 0, 0, nil
+	})
+	if err != nil {
+		return InlineIf{}, err
+	}
+	r.CondStart -= len(prefix)
+	r.CondEnd -= len(prefix)
+	r.ThenStart -= len(prefix)
+	r.ThenEnd -= len(prefix)
+	if r.ElseEnd > 0 {
+		r.ElseStart -= len(prefix)
+		r.ElseEnd -= len(prefix)
+	}
+	r.End -= len(prefix)
+	return r, nil
+}
+
+func singleExpression(block *ast.BlockStmt) (expr ast.Expr, ok bool) {
+	if block == nil || len(block.List) != 1 || !block.Rbrace.IsValid() {
+		return nil, false
+	}
+	stmt, ok := block.List[0].(*ast.ExprStmt)
+	if !ok {
+		return nil, false
+	}
+	return stmt.X, true
+}
+
+// ConstSpec is the location of a single constant declaration within a const block,
+// e.g. `a = 1` in `const ( a = 1 )`.
+type ConstSpec struct {
+	Start, End int
+}
+
+// Const returns the location of each constant declared in a `const x = 1` or `const ( ... )`
+// statement at the start of content, and the end of the statement.
+func Const(content string) (specs []ConstSpec, end int, err error) {
+	if !strings.HasPrefix(content, "const") {
+		return nil, 0, ErrExpectedNodeNotFound
+	}
+	_, end, err = extract(content, func(src string, body []ast.Stmt) (start, end int, err error) {
+		stmt, ok := body[0].(*ast.DeclStmt)
+		if !ok {
+			return 0, 0, ErrExpectedNodeNotFound
+		}
+		decl, ok := stmt.Decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST || len(decl.Specs) == 0 {
+			return 0, 0, ErrExpectedNodeNotFound
+		}
+		if decl.Lparen.IsValid() && !decl.Rparen.IsValid() {
+			return 0, 0, ErrExpectedNodeNotFound
+		}
+		for _, spec := range decl.Specs {
+			specs = append(specs, ConstSpec{
+				Start: int(spec.Pos()) - 1,
+				End:   int(spec.End()) - 1,
+			})
+		}
+		return int(decl.Pos()) - 1, int(decl.End()) - 1, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	// The declaration must be valid Go on its own, and be the only statement on its last line.
+	if _, err = parser.ParseFile(token.NewFileSet(), "", "package main\n"+content[:end], 0); err != nil {
+		return nil, 0, err
+	}
+	if rest := strings.TrimLeft(content[end:], " \t"); rest != "" && rest[0] != '\n' && rest[0] != '\r' {
+		return nil, 0, ErrExpectedNodeNotFound
+	}
+	// Remove the prefix added by extract.
+	offset := len("package main\nfunc templ_container() {\n")
+	for i := range specs {
+		specs[i].Start -= offset
+		specs[i].End -= offset
+	}
+	return specs, end, nil
+}
+
 func Expression(content string) (start, end int, err error) {
 	start, end, err = extract(content, func(src string, body []ast.Stmt) (start, end int, err error) {
 		stmt, ok := body[0].(*ast.ExprStmt)
@@ -121,6 +242,43 @@ func Expression(content string) (start, end int, err error) {
 	return start, end, nil
 }
 
+// CallArg is the location of an argument within a function call.
+type CallArg struct {
+	Start, End int
+}
+
+// Call extracts a function call at the start of content, e.g. `templ.JSONScript("id", data)`,
+// returning the location of each argument, and the end of the call.
+func Call(content string) (args []CallArg, end int, err error) {
+	_, end, err = extract(content, func(src string, body []ast.Stmt) (start, end int, err error) {
+		stmt, ok := body[0].(*ast.ExprStmt)
+		if !ok {
+			return 0, 0, ErrExpectedNodeNotFound
+		}
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok || !call.Rparen.IsValid() || int(call.Rparen) > len(src) || src[call.Rparen-1] != ')' {
+			return 0, 0, ErrExpectedNodeNotFound
+		}
+		for _, arg := range call.Args {
+			args = append(args, CallArg{
+				Start: int(arg.Pos()) - 1,
+				End:   int(arg.End()) - 1,
+			})
+		}
+		return int(stmt.Pos()) - 1, int(stmt.End()) - 1, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	// Remove the prefix added by extract.
+	offset := len("package main\nfunc templ_container() {\n")
+	for i := range args {
+		args[i].Start -= offset
+		args[i].End -= offset
+	}
+	return args, end, nil
+}
+
 func SliceArgs(content string) (expr string, err error) {
 	prefix := "package main\nvar templ_args = []any{"
 	src := prefix + content + "}"
@@ -161,6 +319,163 @@ func SliceArgs(content string) (expr string, err error) {
 	return src[from:to], err
 }
 
+// SliceElement is the location of an element within the contents of a slice literal, e.g.
+// `"btn"` or `"active": isActive`. If the element doesn't have a key, KeyStart and KeyEnd are
+// zero.
+type SliceElement struct {
+	KeyStart, KeyEnd int
+	Start, End       int
+}
+
+// SliceElements returns the location of each element within content, the contents of a slice
+// literal, e.g. `"btn", "active": isActive`.
+func SliceElements(content string) (elements []SliceElement, err error) {
+	prefix := "package main\nvar templ_args = []any{"
+	src := prefix + content + "}"
+	node, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	offset := len(prefix) + 1
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		for _, e := range lit.Elts {
+			var el SliceElement
+			if kv, isKeyValue := e.(*ast.KeyValueExpr); isKeyValue {
+				el.KeyStart, el.KeyEnd = int(kv.Key.Pos())-offset, int(kv.Key.End())-offset
+				e = kv.Value
+			}
+			el.Start, el.End = int(e.Pos())-offset, int(e.End())-offset
+			elements = append(elements, el)
+		}
+		return false
+	})
+	return elements, nil
+}
+
+// NamedArg is the location of a named argument within a call, e.g. `title: "Hi"`.
+type NamedArg struct {
+	NameStart, NameEnd   int
+	ValueStart, ValueEnd int
+}
+
+// NamedArgs extracts a call that uses named arguments, e.g. `Card(title: "Hi", body: content)`.
+// fnEnd is the end of the function expression, and end is the position after the closing paren.
+func NamedArgs(content string) (fnEnd int, args []NamedArg, end int, err error) {
+	fnEnd = strings.IndexRune(content, '(')
+	if fnEnd < 1 || !hasNamedArgPrefix(content, fnEnd) {
+		return 0, nil, 0, ErrExpectedNodeNotFound
+	}
+	args, end, err = namedArgs(content, fnEnd)
+	return fnEnd, args, end, err
+}
+
+func namedArgs(content string, fnEnd int) (args []NamedArg, end int, err error) {
+	fn, fnErr := parser.ParseExpr(content[:fnEnd])
+	if fnErr != nil || int(fn.End())-1 != fnEnd {
+		return nil, 0, ErrExpectedNodeNotFound
+	}
+	switch fn.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+	default:
+		return nil, 0, ErrExpectedNodeNotFound
+	}
+	closeParen, ok := findClosingParen(content, fnEnd)
+	if !ok {
+		return nil, 0, ErrExpectedNodeNotFound
+	}
+
+	// Named arguments are valid Go when written as the elements of a composite literal.
+	prefix := "package main\nvar templ_args = templ_named{"
+	src := prefix + content[fnEnd+1:closeParen] + "}"
+	node, parseErr := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
+	if parseErr != nil {
+		return nil, 0, ErrExpectedNodeNotFound
+	}
+	// Convert positions in src into positions in content.
+	offset := func(p token.Pos) int {
+		return int(p) - 1 - len(prefix) + fnEnd + 1
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		for _, e := range lit.Elts {
+			kv, ok := e.(*ast.KeyValueExpr)
+			if !ok {
+				err = ErrExpectedNodeNotFound
+				return false
+			}
+			if _, ok := kv.Key.(*ast.Ident); !ok {
+				err = ErrExpectedNodeNotFound
+				return false
+			}
+			args = append(args, NamedArg{
+				NameStart:  offset(kv.Key.Pos()),
+				NameEnd:    offset(kv.Key.End()),
+				ValueStart: offset(kv.Value.Pos()),
+				ValueEnd:   offset(kv.Value.End()),
+			})
+		}
+		return false
+	})
+	if err != nil || len(args) == 0 {
+		return nil, 0, ErrExpectedNodeNotFound
+	}
+	return args, closeParen + 1, nil
+}
+
+// hasNamedArgPrefix cheaply checks that content starts with a function name, followed by
+// an opening paren and `name:`, before the expression is fully parsed.
+func hasNamedArgPrefix(content string, fnEnd int) bool {
+	for _, r := range content[:fnEnd] {
+		if !(r == '.' || isIdentRune(r)) {
+			return false
+		}
+	}
+	rest := strings.TrimLeftFunc(content[fnEnd+1:], unicode.IsSpace)
+	name := strings.TrimLeftFunc(rest, isIdentRune)
+	if len(name) == len(rest) {
+		return false
+	}
+	name = strings.TrimLeft(name, " \t")
+	return strings.HasPrefix(name, ":") && !strings.HasPrefix(name, ":=")
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// findClosingParen returns the index of the paren that closes the paren at index start.
+func findClosingParen(content string, start int) (index int, ok bool) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(content)-start)
+	var s scanner.Scanner
+	s.Init(file, []byte(content[start:]), nil, 0)
+	var depth int
+	for {
+		pos, tok, _ := s.Scan()
+		switch tok {
+		case token.EOF:
+			return 0, false
+		case token.LPAREN, token.LBRACK, token.LBRACE:
+			depth++
+		case token.RPAREN, token.RBRACK, token.RBRACE:
+			depth--
+			if depth == 0 {
+				if tok != token.RPAREN {
+					return 0, false
+				}
+				return start + file.Offset(pos), true
+			}
+		}
+	}
+}
+
 // Func returns the Go code up to the opening brace of the function body.
 func Func(content string) (name, expr string, err error) {
 	prefix := "package main\n"
@@ -172,7 +487,10 @@ func Func(content string) (name, expr string, err error) {
 	}
 
 	ast.Inspect(node, func(n ast.Node) bool {
-		// Find the first function declaration.
+		// Find the first function declaration, ignoring any Go functions that follow it.
+		if name != "" {
+			return false
+		}
 		fn, ok := n.(*ast.FuncDecl)
 		if !ok {
 			return true
@@ -268,6 +268,69 @@ func TestSliceArgs(t *testing.T) {
 	}
 }
 
+func TestNamedArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "two named args",
+			input:    `Card(title: "Hi", body: content) }`,
+			expected: []string{"title", `"Hi"`, "body", "content"},
+		},
+		{
+			name:     "package qualified function",
+			input:    `components.Card(title: fmt.Sprintf("%d: %s", 1, "a")) }`,
+			expected: []string{"title", `fmt.Sprintf("%d: %s", 1, "a")`},
+		},
+		{
+			name: "multiline with trailing comma",
+			input: `Card(
+				title: "Hi",
+				items: []string{"a", "b"},
+			) }`,
+			expected: []string{"title", `"Hi"`, "items", `[]string{"a", "b"}`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, args, end, err := NamedArgs(test.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actual []string
+			for _, arg := range args {
+				actual = append(actual, test.input[arg.NameStart:arg.NameEnd], test.input[arg.ValueStart:arg.ValueEnd])
+			}
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if suffix := test.input[end:]; suffix != " }" {
+				t.Errorf("expected the call to end before the closing brace, got suffix %q", suffix)
+			}
+		})
+	}
+}
+
+func TestNamedArgsNotFound(t *testing.T) {
+	tests := []string{
+		`Card("Hi", content) }`,
+		`Card(p.Title) }`,
+		`components[0](title: "Hi") }`,
+		`Card(x := 1) }`,
+		`Card(title: "Hi", "body") }`,
+	}
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, _, _, err := NamedArgs(input)
+			if err != ErrExpectedNodeNotFound {
+				t.Errorf("expected ErrExpectedNodeNotFound, got %v", err)
+			}
+		})
+	}
+}
+
 func TestChildren(t *testing.T) {
 	prefix := ""
 	suffixes := []string{
@@ -335,3 +398,159 @@ func run(test testInput, prefix, suffix string, e extractor) func(t *testing.T)
 		}
 	}
 }
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		end      string
+	}{
+		{
+			name:     "call with arguments",
+			input:    "templ.JSONScript(\"data\", map[string]int{\"a\": 1})\n<div></div>\n}",
+			expected: []string{`"data"`, `map[string]int{"a": 1}`},
+			end:      `templ.JSONScript("data", map[string]int{"a": 1})`,
+		},
+		{
+			name:     "multiline call",
+			input:    "templ.JSONScript(\n\t\"data\",\n\tuser,\n)\n}",
+			expected: []string{`"data"`, `user`},
+			end:      "templ.JSONScript(\n\t\"data\",\n\tuser,\n)",
+		},
+		{
+			name:  "call without arguments",
+			input: "f()\n}",
+			end:   "f()",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			args, end, err := Call(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actual []string
+			for _, arg := range args {
+				actual = append(actual, tt.input[arg.Start:arg.End])
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.end, tt.input[:end]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("expressions that aren't calls are rejected", func(t *testing.T) {
+		if _, _, err := Call("a + b\n}"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestSliceElements(t *testing.T) {
+	input := `"btn", "active": isActive, templ.KV("a", ok)`
+	elements, err := SliceElements(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual []string
+	for _, el := range elements {
+		if el.KeyEnd > 0 {
+			actual = append(actual, input[el.KeyStart:el.KeyEnd]+" => "+input[el.Start:el.End])
+			continue
+		}
+		actual = append(actual, input[el.Start:el.End])
+	}
+	expected := []string{`"btn"`, `"active" => isActive`, `templ.KV("a", ok)`}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestConst(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		end      string
+	}{
+		{
+			name:     "single constant",
+			input:    "const greeting = \"Hello\"\n<div>{ greeting }</div>\n}",
+			expected: []string{`greeting = "Hello"`},
+			end:      `const greeting = "Hello"`,
+		},
+		{
+			name:     "const block",
+			input:    "const (\n\tmaxItems = 10\n\tprefix string = \"item-\"\n)\n<div>{ prefix }</div>\n}",
+			expected: []string{`maxItems = 10`, `prefix string = "item-"`},
+			end:      "const (\n\tmaxItems = 10\n\tprefix string = \"item-\"\n)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			specs, end, err := Const(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actual []string
+			for _, spec := range specs {
+				actual = append(actual, tt.input[spec.Start:spec.End])
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.end, tt.input[:end]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestIfExpr(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "if else",
+			input:    `if user != nil { user.Name } else { "Guest" } }`,
+			expected: []string{"user != nil", "user.Name", `"Guest"`},
+		},
+		{
+			name:     "if without else",
+			input:    `if ok { "Yes" } }`,
+			expected: []string{"ok", `"Yes"`, ""},
+		},
+		{
+			name:     "nested braces",
+			input:    `if len(items) > 0 { strings.Join(items, map[string]string{"a": "b"}["a"]) } else { "none" } }`,
+			expected: []string{"len(items) > 0", `strings.Join(items, map[string]string{"a": "b"}["a"])`, `"none"`},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := IfExpr(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual := []string{
+				tt.input[r.CondStart:r.CondEnd],
+				tt.input[r.ThenStart:r.ThenEnd],
+				tt.input[r.ElseStart:r.ElseEnd],
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if suffix := tt.input[r.End:]; suffix != " }" {
+				t.Errorf("expected the statement to end before the closing brace, got suffix %q", suffix)
+			}
+		})
+	}
+}
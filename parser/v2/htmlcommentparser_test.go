@@ -18,6 +18,10 @@ func TestHTMLCommentParser(t *testing.T) {
 			input: `<!-- single line comment -->`,
 			expected: HTMLComment{
 				Contents: " single line comment ",
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 28, Line: 0, Col: 28},
+				},
 			},
 		},
 		{
@@ -25,6 +29,10 @@ func TestHTMLCommentParser(t *testing.T) {
 			input: `<!--no whitespace between sequence open and close-->`,
 			expected: HTMLComment{
 				Contents: "no whitespace between sequence open and close",
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 52, Line: 0, Col: 52},
+				},
 			},
 		},
 		{
@@ -36,6 +44,10 @@ func TestHTMLCommentParser(t *testing.T) {
 				Contents: ` multiline
 								comment
 					`,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 39, Line: 2, Col: 8},
+				},
 			},
 		},
 		{
@@ -43,6 +55,10 @@ func TestHTMLCommentParser(t *testing.T) {
 			input: `<!-- <p class="test">tag</p> -->`,
 			expected: HTMLComment{
 				Contents: ` <p class="test">tag</p> `,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 32, Line: 0, Col: 32},
+				},
 			},
 		},
 		{
@@ -50,6 +66,31 @@ func TestHTMLCommentParser(t *testing.T) {
 			input: `<!-- <div> hello world </div> -->`,
 			expected: HTMLComment{
 				Contents: ` <div> hello world </div> `,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 33, Line: 0, Col: 33},
+				},
+			},
+		},
+		{
+			name:  "comment - empty",
+			input: `<!---->`,
+			expected: HTMLComment{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 7, Line: 0, Col: 7},
+				},
+			},
+		},
+		{
+			name:  "comment - contains quoted strings",
+			input: `<!-- title="a" -->`,
+			expected: HTMLComment{
+				Contents: ` title="a" `,
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 18, Line: 0, Col: 18},
+				},
 			},
 		},
 	}
@@ -108,3 +149,33 @@ func TestHTMLCommentParserErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestHTMLCommentIgnoreDirective(t *testing.T) {
+	input := parse.NewInput(`templ Name() {
+	<!-- templ:ignore -->
+	<div>experimental</div>
+	<p>visible</p>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+	var elements []Element
+	for _, n := range tem.Children {
+		if e, isElement := n.(Element); isElement {
+			elements = append(elements, e)
+		}
+	}
+	if len(elements) != 2 {
+		t.Fatalf("expected 2 elements, got %d", len(elements))
+	}
+	if !elements[0].Ignored {
+		t.Errorf("expected the <div> following the ignore directive to be ignored")
+	}
+	if elements[1].Ignored {
+		t.Errorf("expected the <p> to not be ignored")
+	}
+}
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// HTMLNodeOptions customise the output of ToHTMLNodeWithOptions.
+type HTMLNodeOptions struct {
+	// CommentPlaceholders replaces nodes that can't be converted statically, e.g. string
+	// expressions, or if expressions, with a comment containing their template source, e.g.
+	// `<!--{ name }-->`, and drops attributes that can't be converted. If it isn't set, an error
+	// is returned instead.
+	CommentPlaceholders bool
+}
+
+// ToHTMLNode converts the nodes into a golang.org/x/net/html document node, so that they can be
+// rendered or changed with that package. Like Render, only static nodes can be converted, and
+// whitespace is collapsed to a single space, so html.Render writes HTML that's equivalent to the
+// output of Render.
+func ToHTMLNode(nodes []Node) (*nethtml.Node, error) {
+	return ToHTMLNodeWithOptions(nodes, HTMLNodeOptions{})
+}
+
+// ToHTMLNodeWithOptions converts the nodes into a golang.org/x/net/html document node, see
+// ToHTMLNode.
+func ToHTMLNodeWithOptions(nodes []Node, opts HTMLNodeOptions) (*nethtml.Node, error) {
+	doc := &nethtml.Node{Type: nethtml.DocumentNode}
+	if err := appendHTMLNodes(doc, nodes, opts); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func appendHTMLNodes(parent *nethtml.Node, nodes []Node, opts HTMLNodeOptions) error {
+	for _, n := range nodes {
+		if err := appendHTMLNode(parent, n, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendHTMLNode(parent *nethtml.Node, node Node, opts HTMLNodeOptions) (err error) {
+	switch n := node.(type) {
+	case Element:
+		if n.Ignored {
+			return nil
+		}
+		e, err := newHTMLElementNode(n.Name, n.Attributes, opts)
+		if err != nil {
+			return err
+		}
+		if !n.IsVoidElement() {
+			if err = appendHTMLNodes(e, n.Children, opts); err != nil {
+				return err
+			}
+		}
+		parent.AppendChild(e)
+		appendHTMLTrailingSpace(parent, n.TrailingSpace)
+		return nil
+	case RawElement:
+		if n.Parts != nil {
+			// The contents include Go expressions.
+			break
+		}
+		e, err := newHTMLElementNode(n.Name, n.Attributes, opts)
+		if err != nil {
+			return err
+		}
+		e.AppendChild(&nethtml.Node{Type: nethtml.TextNode, Data: n.Contents})
+		parent.AppendChild(e)
+		return nil
+	case Text:
+		value := n.Value
+		if !n.Unescaped {
+			value = html.UnescapeString(value)
+		}
+		parent.AppendChild(&nethtml.Node{Type: nethtml.TextNode, Data: value})
+		appendHTMLTrailingSpace(parent, n.TrailingSpace)
+		return nil
+	case Whitespace:
+		if n.Value != "" {
+			parent.AppendChild(&nethtml.Node{Type: nethtml.TextNode, Data: " "})
+		}
+		return nil
+	case DocType:
+		if strings.ContainsAny(n.Value, `"'`) {
+			// The doctype node escapes quotes, so legacy doctypes are written as they are.
+			parent.AppendChild(&nethtml.Node{Type: nethtml.RawNode, Data: "<!DOCTYPE " + n.Value + ">"})
+			return nil
+		}
+		parent.AppendChild(&nethtml.Node{Type: nethtml.DoctypeNode, Data: n.Value})
+		return nil
+	case HTMLComment:
+		if !n.IsIgnoreDirective() {
+			parent.AppendChild(&nethtml.Node{Type: nethtml.CommentNode, Data: n.Contents})
+		}
+		return nil
+	case CData:
+		parent.AppendChild(&nethtml.Node{Type: nethtml.RawNode, Data: "<![CDATA[" + n.Value + "]]>"})
+		return nil
+	case RawHTML:
+		parent.AppendChild(&nethtml.Node{Type: nethtml.RawNode, Data: n.Value})
+		return nil
+	case GoComment:
+		// Go comments aren't rendered.
+		return nil
+	}
+	if !opts.CommentPlaceholders {
+		return fmt.Errorf("html node: %T can't be converted statically", node)
+	}
+	source := new(strings.Builder)
+	if err = node.Write(source, 0); err != nil {
+		return err
+	}
+	parent.AppendChild(&nethtml.Node{Type: nethtml.CommentNode, Data: strings.TrimSpace(source.String())})
+	return nil
+}
+
+func newHTMLElementNode(name string, attrs []Attribute, opts HTMLNodeOptions) (*nethtml.Node, error) {
+	e := &nethtml.Node{
+		Type:     nethtml.ElementNode,
+		Data:     name,
+		DataAtom: atom.Lookup([]byte(name)),
+	}
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case ConstantAttribute:
+			e.Attr = append(e.Attr, nethtml.Attribute{Key: attr.Name, Val: attr.Value})
+		case BoolConstantAttribute:
+			e.Attr = append(e.Attr, nethtml.Attribute{Key: attr.Name})
+		case StyleAttribute:
+			e.Attr = append(e.Attr, nethtml.Attribute{Key: "style", Val: attr.Value()})
+		case SrcsetAttribute:
+			e.Attr = append(e.Attr, nethtml.Attribute{Key: "srcset", Val: attr.Value()})
+		default:
+			if !opts.CommentPlaceholders {
+				return nil, fmt.Errorf("html node: <%s>: %T can't be converted statically", name, attr)
+			}
+		}
+	}
+	return e, nil
+}
+
+func appendHTMLTrailingSpace(parent *nethtml.Node, ts TrailingSpace) {
+	if ts != SpaceNone {
+		parent.AppendChild(&nethtml.Node{Type: nethtml.TextNode, Data: " "})
+	}
+}
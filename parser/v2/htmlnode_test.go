@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
+)
+
+func TestToHTMLNode(t *testing.T) {
+	parseTemplate := func(t *testing.T, src string) HTMLTemplate {
+		t.Helper()
+		tem, ok, err := template.Parse(parse.NewInput(src))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		return tem
+	}
+	renderHTMLNode := func(t *testing.T, n *html.Node) string {
+		t.Helper()
+		w := new(strings.Builder)
+		if err := html.Render(w, n); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		return w.String()
+	}
+
+	t.Run("static nodes are converted", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x() {
+	<!DOCTYPE html>
+	<div class="a &amp; b" data-name='"quoted"'>
+		<!-- comment -->
+		<p>Tom &amp; Jerry <b>run</b></p>
+		<input type="checkbox" checked/>
+		<script>if (a < b) { go(); }</script>
+	</div>
+}`)
+		n, err := ToHTMLNode(tem.Children)
+		if err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		expected := ` <!DOCTYPE html> <div class="a &amp; b" data-name="&#34;quoted&#34;"> <!-- comment --> <p>Tom &amp; Jerry <b>run</b></p> <input type="checkbox" checked=""/> <script>if (a < b) { go(); }</script> </div> `
+		if diff := cmp.Diff(expected, renderHTMLNode(t, n)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("dynamic nodes return an error", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x(name string) {
+	<p>{ name }</p>
+}`)
+		_, err := ToHTMLNode(tem.Children)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if diff := cmp.Diff("html node: parser.StringExpression can't be converted statically", err.Error()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("dynamic nodes can be replaced with comments", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x(name string, ok bool) {
+	<p title={ name }>Hello, { name }</p>
+	if ok {
+		<b>OK</b>
+	}
+}`)
+		n, err := ToHTMLNodeWithOptions(tem.Children, HTMLNodeOptions{CommentPlaceholders: true})
+		if err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		expected := " <p>Hello, <!--{ name }--></p> <!--if ok {\n\t<b>OK</b>\n}--> "
+		if diff := cmp.Diff(expected, renderHTMLNode(t, n)); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
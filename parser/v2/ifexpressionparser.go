@@ -25,7 +25,7 @@ func (ifExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	}
 
 	// Eat " {\n".
-	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+	if _, ok, err = openBraceAndNewLine.Parse(pi); err != nil || !ok {
 		err = parse.Error("if: "+unterminatedMissingCurly, pi.PositionAt(start))
 		return
 	}
@@ -61,6 +61,7 @@ func (ifExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 		err = parse.Error("if: "+unterminatedMissingEnd, pi.Position())
 		return
 	}
+	r.Range = sourceRange(pi, start)
 
 	return r, true, nil
 }
@@ -86,7 +87,7 @@ func (elseIfExpressionParser) Parse(pi *parse.Input) (r ElseIfExpression, ok boo
 	}
 
 	// Eat " {\n".
-	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+	if _, ok, err = openBraceAndNewLine.Parse(pi); err != nil || !ok {
 		err = parse.Error("else if: "+unterminatedMissingCurly, pi.PositionAt(start))
 		return
 	}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/a-h/parse"
@@ -59,10 +60,46 @@ func TestIfExpression(t *testing.T) {
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 21,
+										Line:  2,
+										Col:   2,
+									},
+									To: Position{
+										Index: 39,
+										Line:  2,
+										Col:   20,
+									},
+								},
 							},
 						},
 						IndentChildren: true,
 						TrailingSpace:  SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 12,
+								Line:  1,
+								Col:   0,
+							},
+							To: Position{
+								Index: 47,
+								Line:  3,
+								Col:   7,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 49,
+						Line:  4,
+						Col:   1,
 					},
 				},
 			},
@@ -109,6 +146,18 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 17,
+								Line:  1,
+								Col:   8,
+							},
+						},
 					},
 				},
 				Else: []Node{
@@ -129,6 +178,30 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 28,
+								Line:  3,
+								Col:   1,
+							},
+							To: Position{
+								Index: 35,
+								Line:  3,
+								Col:   8,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 37,
+						Line:  4,
+						Col:   1,
 					},
 				},
 			},
@@ -157,7 +230,34 @@ func TestIfExpression(t *testing.T) {
 				},
 				Then: []Node{
 					Whitespace{Value: "  "},
-					Text{Value: "text", TrailingSpace: SpaceVertical},
+					Text{
+						Value:         "text",
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 15,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 19,
+								Line:  1,
+								Col:   6,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 21,
+						Line:  2,
+						Col:   1,
+					},
 				},
 			},
 		},
@@ -207,10 +307,46 @@ func TestIfExpression(t *testing.T) {
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 21,
+										Line:  2,
+										Col:   2,
+									},
+									To: Position{
+										Index: 39,
+										Line:  2,
+										Col:   20,
+									},
+								},
 							},
 						},
 						IndentChildren: true,
 						TrailingSpace:  SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 12,
+								Line:  1,
+								Col:   0,
+							},
+							To: Position{
+								Index: 47,
+								Line:  3,
+								Col:   7,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 49,
+						Line:  4,
+						Col:   1,
 					},
 				},
 			},
@@ -257,6 +393,18 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 9,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 16,
+								Line:  1,
+								Col:   8,
+							},
+						},
 					},
 				},
 				Else: []Node{
@@ -277,6 +425,30 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 27,
+								Line:  3,
+								Col:   1,
+							},
+							To: Position{
+								Index: 34,
+								Line:  3,
+								Col:   8,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 36,
+						Line:  4,
+						Col:   1,
 					},
 				},
 			},
@@ -343,14 +515,62 @@ func TestIfExpression(t *testing.T) {
 												},
 											},
 										},
+										Range: Range{
+											From: Position{
+												Index: 34,
+												Line:  2,
+												Col:   11,
+											},
+											To: Position{
+												Index: 41,
+												Line:  2,
+												Col:   18,
+											},
+										},
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 29,
+										Line:  2,
+										Col:   6,
+									},
+									To: Position{
+										Index: 47,
+										Line:  2,
+										Col:   24,
+									},
+								},
+							},
+						},
+						Range: Range{
+							From: Position{
+								Index: 14,
+								Line:  1,
+								Col:   5,
+							},
+							To: Position{
+								Index: 54,
+								Line:  3,
+								Col:   6,
 							},
 						},
 					},
 					Whitespace{Value: "\n\t\t\t\t"},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 60,
+						Line:  4,
+						Col:   5,
+					},
+				},
 			},
 		},
 		{
@@ -379,6 +599,18 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 17,
+								Line:  1,
+								Col:   8,
+							},
+						},
 					},
 				},
 				ElseIfs: []ElseIfExpression{
@@ -401,10 +633,34 @@ func TestIfExpression(t *testing.T) {
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 35,
+										Line:  3,
+										Col:   1,
+									},
+									To: Position{
+										Index: 42,
+										Line:  3,
+										Col:   8,
+									},
+								},
 							},
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 44,
+						Line:  4,
+						Col:   1,
+					},
+				},
 			},
 		},
 		{
@@ -435,6 +691,18 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 17,
+								Line:  1,
+								Col:   8,
+							},
+						},
 					},
 				},
 				ElseIfs: []ElseIfExpression{
@@ -457,6 +725,18 @@ func TestIfExpression(t *testing.T) {
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 35,
+										Line:  3,
+										Col:   1,
+									},
+									To: Position{
+										Index: 42,
+										Line:  3,
+										Col:   8,
+									},
+								},
 							},
 						},
 					},
@@ -479,10 +759,34 @@ func TestIfExpression(t *testing.T) {
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 60,
+										Line:  5,
+										Col:   1,
+									},
+									To: Position{
+										Index: 67,
+										Line:  5,
+										Col:   8,
+									},
+								},
 							},
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 69,
+						Line:  6,
+						Col:   1,
+					},
+				},
 			},
 		},
 		{
@@ -515,6 +819,18 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 10,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 17,
+								Line:  1,
+								Col:   8,
+							},
+						},
 					},
 				},
 				ElseIfs: []ElseIfExpression{
@@ -537,6 +853,18 @@ func TestIfExpression(t *testing.T) {
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 35,
+										Line:  3,
+										Col:   1,
+									},
+									To: Position{
+										Index: 42,
+										Line:  3,
+										Col:   8,
+									},
+								},
 							},
 						},
 					},
@@ -559,6 +887,18 @@ func TestIfExpression(t *testing.T) {
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 60,
+										Line:  5,
+										Col:   1,
+									},
+									To: Position{
+										Index: 67,
+										Line:  5,
+										Col:   8,
+									},
+								},
 							},
 						},
 					},
@@ -573,6 +913,30 @@ func TestIfExpression(t *testing.T) {
 							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 78,
+								Line:  7,
+								Col:   1,
+							},
+							To: Position{
+								Index: 85,
+								Line:  7,
+								Col:   8,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 87,
+						Line:  8,
+						Col:   1,
 					},
 				},
 			},
@@ -615,3 +979,100 @@ func TestIncompleteIf(t *testing.T) {
 		}
 	})
 }
+
+func TestIfExpressionNested(t *testing.T) {
+	parseIf := func(t *testing.T, src string) IfExpression {
+		t.Helper()
+		n, ok, err := ifExpression.Parse(parse.NewInput(src))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse if expression: %v", err)
+		}
+		return n.(IfExpression)
+	}
+	// conditions returns the condition of each branch in turn, with the text within it.
+	var conditions func(n IfExpression) (branches []string)
+	conditions = func(n IfExpression) (branches []string) {
+		branches = append(branches, n.Expression.Value+": "+strings.TrimSpace(TextContent(Element{Children: n.Then}, "")))
+		if len(n.Else) == 1 {
+			if next, ok := n.Else[0].(IfExpression); ok {
+				return append(branches, conditions(next)...)
+			}
+		}
+		if len(n.Else) > 0 {
+			branches = append(branches, "else: "+strings.TrimSpace(TextContent(Element{Children: n.Else}, "")))
+		}
+		return branches
+	}
+
+	t.Run("if, else if and else", func(t *testing.T) {
+		n := parseIf(t, `if a {
+	<p>A</p>
+} else if b {
+	<p>B</p>
+} else if c {
+	<p>C</p>
+} else {
+	<p>D</p>
+}`)
+		if len(n.ElseIfs) != 2 {
+			t.Fatalf("expected the parser to keep 2 else if branches, got %d", len(n.ElseIfs))
+		}
+		nested := n.Nested()
+		if nested.ElseIfs != nil {
+			t.Errorf("expected no else if branches, got %v", nested.ElseIfs)
+		}
+		expected := []string{"a: A", "b: B", "c: C", "else: D"}
+		if diff := cmp.Diff(expected, conditions(nested)); diff != "" {
+			t.Error(diff)
+		}
+		last := nested.Else[0].(IfExpression).Else[0].(IfExpression)
+		if diff := cmp.Diff(n.ElseIfs[1].Expression, last.Expression); diff != "" {
+			t.Errorf("expected the range of the condition to be kept:\n%s", diff)
+		}
+	})
+	t.Run("else if without else", func(t *testing.T) {
+		n := parseIf(t, `if a {
+	<p>A</p>
+} else if b {
+	<p>B</p>
+} else if c {
+	<p>C</p>
+}`)
+		expected := []string{"a: A", "b: B", "c: C"}
+		if diff := cmp.Diff(expected, conditions(n.Nested())); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("else belongs to the if that it follows", func(t *testing.T) {
+		n := parseIf(t, `if a {
+	if b {
+		<p>B</p>
+	}
+} else if c {
+	<p>C</p>
+} else {
+	<p>D</p>
+}`)
+		expected := []string{"a: B", "c: C", "else: D"}
+		if diff := cmp.Diff(expected, conditions(n.Nested())); diff != "" {
+			t.Error(diff)
+		}
+		var inner []IfExpression
+		for _, child := range n.Then {
+			if ie, ok := child.(IfExpression); ok {
+				inner = append(inner, ie)
+			}
+		}
+		if len(inner) != 1 || inner[0].ElseIfs != nil || inner[0].Else != nil {
+			t.Errorf("expected the inner if to have no else branches, got %v", inner)
+		}
+	})
+	t.Run("an if without else is unchanged", func(t *testing.T) {
+		n := parseIf(t, `if a {
+	<p>A</p>
+}`)
+		if diff := cmp.Diff(n, n.Nested()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
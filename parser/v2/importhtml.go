@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ImportHTML converts static HTML, e.g. an existing page, into nodes, so that it can be used to
+// start a template. Elements, text, comments and doctypes become Element, Text, HTMLComment and
+// DocType nodes, and the contents of <script> and <style> elements become a RawElement. Text
+// within tags that's only whitespace becomes Whitespace, apart from within <pre> and <textarea>.
+//
+// Every attribute becomes a ConstantAttribute, with its value decoded, so `disabled` is imported
+// as `disabled=""`. Text is kept as it was written, HTML encoded. The HTML is tokenized rather
+// than parsed, so elements aren't moved or added as browsers would, e.g. a missing <tbody>, but
+// names are lowercased, including SVG names such as viewBox. End tags without a start tag are
+// ignored, and elements that aren't closed end with their parent.
+func ImportHTML(r io.Reader) (nodes []Node, err error) {
+	z := html.NewTokenizer(r)
+	// open elements, with the innermost last.
+	var open []Element
+	add := func(n Node) {
+		if len(open) == 0 {
+			nodes = append(nodes, n)
+			return
+		}
+		parent := &open[len(open)-1]
+		parent.Children = append(parent.Children, n)
+	}
+	closeElement := func() {
+		e := open[len(open)-1]
+		open = open[:len(open)-1]
+		add(e)
+	}
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err = z.Err(); err != io.EOF {
+				return nil, err
+			}
+			for len(open) > 0 {
+				closeElement()
+			}
+			return nodes, nil
+		case html.TextToken:
+			raw := string(z.Raw())
+			preserveWhitespace := len(open) > 0 && open[len(open)-1].PreserveWhitespace
+			if strings.TrimSpace(raw) == "" && !preserveWhitespace {
+				add(Whitespace{Value: raw})
+				continue
+			}
+			add(Text{Value: raw})
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			attrs := importAttributes(t.Attr)
+			if tt == html.StartTagToken && (t.Data == "script" || t.Data == "style") {
+				e, err := importRawElement(z, t.Data, attrs)
+				if err != nil {
+					return nil, err
+				}
+				add(e)
+				continue
+			}
+			e := Element{
+				Name:       t.Data,
+				Attributes: attrs,
+				Key:        elementKey(attrs),
+			}
+			if e.IsVoidElement() || tt == html.SelfClosingTagToken {
+				add(e)
+				continue
+			}
+			e.PreserveWhitespace = isWhitespaceSensitive(e.Name) || (len(open) > 0 && open[len(open)-1].PreserveWhitespace)
+			open = append(open, e)
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			i := len(open) - 1
+			for i >= 0 && open[i].Name != string(name) {
+				i--
+			}
+			for i >= 0 && len(open) > i {
+				closeElement()
+			}
+		case html.CommentToken:
+			add(HTMLComment{Contents: string(z.Text())})
+		case html.DoctypeToken:
+			add(DocType{Value: string(z.Text())})
+		}
+	}
+}
+
+func importAttributes(attrs []html.Attribute) []Attribute {
+	if len(attrs) == 0 {
+		return nil
+	}
+	op := make([]Attribute, len(attrs))
+	for i, a := range attrs {
+		op[i] = ConstantAttribute{
+			Name:        a.Key,
+			Value:       a.Val,
+			SingleQuote: strings.Contains(a.Val, `"`),
+		}
+	}
+	return op
+}
+
+// importRawElement reads the contents of a <script> or <style> element, up to its end tag. The
+// tokenizer returns the contents as text, without looking for tags within them.
+func importRawElement(z *html.Tokenizer, name string, attrs []Attribute) (RawElement, error) {
+	e := RawElement{Name: name, Attributes: attrs}
+	contents := new(strings.Builder)
+	for {
+		switch z.Next() {
+		case html.TextToken:
+			contents.Write(z.Raw())
+			continue
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return e, err
+			}
+		}
+		e.Contents = contents.String()
+		return e, nil
+	}
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImportHTML(t *testing.T) {
+	t.Run("imported HTML renders equivalently", func(t *testing.T) {
+		input := `<!DOCTYPE html>
+<html>
+	<head><title>A &amp; B</title><style>p > a { color: red; }</style></head>
+	<body class="main">
+		<!-- nav -->
+		<p id="x" title="&quot;Hi&quot;">Hello, <b>World</b>!<br>Next line</p>
+		<input type="checkbox" checked>
+		<pre>  a
+  b</pre>
+	</body>
+</html>
+`
+		nodes, err := ImportHTML(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to import: %v", err)
+		}
+		w := new(strings.Builder)
+		if err = Render(w, nodes); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		expected := `<!doctype html> <html> <head><title>A &amp; B</title><style>p > a { color: red; }</style></head> <body class="main"> <!-- nav --> <p id="x" title="&#34;Hi&#34;">Hello, <b>World</b>!<br>Next line</p> <input type="checkbox" checked=""> <pre>  a
+  b</pre> </body> </html> `
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("tags are converted to nodes", func(t *testing.T) {
+		nodes, err := ImportHTML(strings.NewReader("<p data-key=\"a\">Hi <b>there</b>\n</p><div>"))
+		if err != nil {
+			t.Fatalf("failed to import: %v", err)
+		}
+		expected := []Node{
+			Element{
+				Name:       "p",
+				Attributes: []Attribute{ConstantAttribute{Name: "data-key", Value: "a"}},
+				Key:        "a",
+				Children: []Node{
+					Text{Value: "Hi "},
+					Element{Name: "b", Children: []Node{Text{Value: "there"}}},
+					Whitespace{Value: "\n"},
+				},
+			},
+			Element{Name: "div"},
+		}
+		if diff := cmp.Diff(expected, nodes); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("read errors are returned", func(t *testing.T) {
+		expected := errors.New("read failed")
+		if _, err := ImportHTML(iotest.ErrReader(expected)); !errors.Is(err, expected) {
+			t.Errorf("expected %v, got %v", expected, err)
+		}
+	})
+}
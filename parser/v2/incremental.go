@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+// IncrementalParse returns the template that old becomes when the text within editRange of
+// oldSource, the file that old was parsed from, is replaced to give newSource. It's intended for
+// editors, which reparse on each change.
+//
+// Only the template that contains the edit is reparsed, from its position in newSource, so the
+// positions of the nodes after the edit include the change in length. If the edit isn't within
+// the body of the template, e.g. it changes the template's name, or the text before it, the whole
+// of newSource is parsed instead. Either way, the result is the same as the template returned by
+// ParseString(newSource).
+func IncrementalParse(old HTMLTemplate, oldSource, newSource string, editRange Range) (HTMLTemplate, error) {
+	delta := len(newSource) - len(oldSource)
+	start, ok := incrementalParseStart(old, oldSource, newSource, editRange)
+	if !ok {
+		return parseTemplateAt(old, newSource, editRange, delta)
+	}
+	p := template
+	if old.TextMode {
+		p = textTemplate
+	}
+	pi := parse.NewInput(newSource)
+	pi.Seek(start)
+	t, ok, err := p.Parse(pi)
+	if err != nil || !ok {
+		// Let the full parse report the error.
+		return parseTemplateAt(old, newSource, editRange, delta)
+	}
+	return t, nil
+}
+
+// incrementalParseStart returns the index of the start of the template, i.e. `templ`, if edit is
+// within the template body, and the text outside of the edit is unchanged. The file parser reads
+// the text before the template the same way, so parsing the template from the start index gives
+// the same result as parsing the whole file.
+func incrementalParseStart(old HTMLTemplate, oldSource, newSource string, editRange Range) (start int, ok bool) {
+	from, to := int(editRange.From.Index), int(editRange.To.Index)
+	delta := len(newSource) - len(oldSource)
+	if from < 0 || from > to || to > len(oldSource) || to+delta < from || to+delta > len(newSource) {
+		return 0, false
+	}
+	if oldSource[:from] != newSource[:from] || oldSource[to:] != newSource[to+delta:] {
+		return 0, false
+	}
+	start = int(old.Expression.Range.From.Index) - len("templ ")
+	if start < 0 || !strings.HasPrefix(oldSource[start:], "templ ") {
+		return 0, false
+	}
+	// The edit must start after the first line, `templ name() {`.
+	lineEnd := strings.IndexByte(oldSource[start:], '\n')
+	if lineEnd < 0 || from <= start+lineEnd {
+		return 0, false
+	}
+	return start, true
+}
+
+// parseTemplateAt parses the whole of newSource, and returns the template that starts where old
+// did, after the edit is applied.
+func parseTemplateAt(old HTMLTemplate, newSource string, editRange Range, delta int) (HTMLTemplate, error) {
+	p := NewTemplateFileParser("main")
+	p.TextMode = old.TextMode
+	tf, ok, err := p.Parse(parse.NewInput(newSource))
+	if err != nil {
+		return HTMLTemplate{}, err
+	}
+	if !ok {
+		return HTMLTemplate{}, ErrTemplateNotFound
+	}
+	from := old.Expression.Range.From.Index
+	if from >= editRange.To.Index && from > editRange.From.Index {
+		from += int64(delta)
+	}
+	for _, n := range tf.Nodes {
+		if t, ok := n.(HTMLTemplate); ok && t.Expression.Range.From.Index == from {
+			return t, nil
+		}
+	}
+	return HTMLTemplate{}, ErrTemplateNotFound
+}
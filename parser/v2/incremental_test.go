@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIncrementalParse(t *testing.T) {
+	oldSource := `package main
+
+templ header(title string) {
+	<h1>{ title }</h1>
+}
+
+templ page(name string) {
+	<div class="page">
+		<p>Hello</p>
+		<a href="/home" title={ name }>Home</a>
+	</div>
+	@header(name)
+}
+`
+	var tests = []struct {
+		name        string
+		old         string
+		replacement string
+	}{
+		{
+			name:        "inside a text node",
+			old:         "Hello",
+			replacement: "Hello, World",
+		},
+		{
+			name:        "inside a text node, shortening it",
+			old:         "Hello",
+			replacement: "Hi",
+		},
+		{
+			name:        "inside an attribute",
+			old:         `/home`,
+			replacement: `/home/index.html`,
+		},
+		{
+			name:        "inside an attribute expression",
+			old:         `title={ name }`,
+			replacement: `title={ "Home: " + name }`,
+		},
+		{
+			name:        "adding a line",
+			old:         "\t\t<p>Hello</p>\n",
+			replacement: "\t\t<p>Hello</p>\n\t\t<p>World</p>\n",
+		},
+		{
+			name:        "inside an earlier template",
+			old:         "<h1>",
+			replacement: "<h1 class=\"title\">",
+		},
+		{
+			name:        "changing the template name",
+			old:         "templ page(",
+			replacement: "templ homePage(",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := ParseString(oldSource)
+			if err != nil {
+				t.Fatalf("failed to parse old source: %v", err)
+			}
+			from := strings.Index(oldSource, tt.old)
+			if from < 0 {
+				t.Fatalf("%q not found in the source", tt.old)
+			}
+			to := from + len(tt.old)
+			newSource := oldSource[:from] + tt.replacement + oldSource[to:]
+			pi := parse.NewInput(oldSource)
+			editRange := NewRange(pi.PositionAt(from), pi.PositionAt(to))
+
+			actual, err := IncrementalParse(old.Nodes[1].(HTMLTemplate), oldSource, newSource, editRange)
+			if err != nil {
+				t.Fatalf("failed to parse incrementally: %v", err)
+			}
+
+			expected, err := ParseString(newSource)
+			if err != nil {
+				t.Fatalf("failed to parse new source: %v", err)
+			}
+			if diff := cmp.Diff(expected.Nodes[1], actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("an edit that breaks the template returns the error", func(t *testing.T) {
+		old, err := ParseString(oldSource)
+		if err != nil {
+			t.Fatalf("failed to parse old source: %v", err)
+		}
+		from := strings.Index(oldSource, "</div>")
+		newSource := oldSource[:from] + oldSource[from+len("</div>"):]
+		pi := parse.NewInput(oldSource)
+		editRange := NewRange(pi.PositionAt(from), pi.PositionAt(from+len("</div>")))
+
+		_, err = IncrementalParse(old.Nodes[1].(HTMLTemplate), oldSource, newSource, editRange)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		_, expected := ParseString(newSource)
+		if diff := cmp.Diff(expected.Error(), err.Error()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
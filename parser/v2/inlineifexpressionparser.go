@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
+)
+
+var inlineIfExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+
+	// Check the prefix first.
+	if _, ok, err = openBraceWithOptionalSpace.Parse(pi); err != nil || !ok {
+		return
+	}
+	if !peekPrefix(pi, "if ") {
+		pi.Seek(start)
+		return n, false, nil
+	}
+
+	// Parse the Go if statement.
+	var r InlineIfExpression
+	from := pi.Index()
+	src, _ := pi.Peek(-1)
+	expr, err := goexpression.IfExpr(src)
+	if err != nil {
+		return r, false, parse.Error(fmt.Sprintf("inline if: invalid go expression, each branch must contain a single expression: %v", err.Error()), pi.Position())
+	}
+	r.Expression = NewExpression(src[expr.CondStart:expr.CondEnd], pi.PositionAt(from+expr.CondStart), pi.PositionAt(from+expr.CondEnd))
+	r.Then = NewExpression(src[expr.ThenStart:expr.ThenEnd], pi.PositionAt(from+expr.ThenStart), pi.PositionAt(from+expr.ThenEnd))
+	if expr.ElseEnd > 0 {
+		r.Else = NewExpression(src[expr.ElseStart:expr.ElseEnd], pi.PositionAt(from+expr.ElseStart), pi.PositionAt(from+expr.ElseEnd))
+	}
+	pi.Take(expr.End)
+
+	// Clear any optional whitespace.
+	_, _, _ = parse.OptionalWhitespace.Parse(pi)
+
+	// }
+	if _, ok, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		err = parse.Error("inline if: missing close brace", pi.Position())
+		return
+	}
+
+	// Parse trailing whitespace.
+	ws, _, err := parse.Whitespace.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	r.TrailingSpace, err = NewTrailingSpace(ws)
+	if err != nil {
+		return r, false, err
+	}
+
+	return r, true, nil
+})
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInlineIfExpressionParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected InlineIfExpression
+	}{
+		{
+			name:  "inline if: with else",
+			input: `{ if user != nil { user.Name } else { "Guest" } }`,
+			expected: InlineIfExpression{
+				Expression: Expression{
+					Value: "user != nil",
+					Range: Range{
+						From: Position{Index: 5, Line: 0, Col: 5},
+						To:   Position{Index: 16, Line: 0, Col: 16},
+					},
+				},
+				Then: Expression{
+					Value: "user.Name",
+					Range: Range{
+						From: Position{Index: 19, Line: 0, Col: 19},
+						To:   Position{Index: 28, Line: 0, Col: 28},
+					},
+				},
+				Else: Expression{
+					Value: `"Guest"`,
+					Range: Range{
+						From: Position{Index: 38, Line: 0, Col: 38},
+						To:   Position{Index: 45, Line: 0, Col: 45},
+					},
+				},
+			},
+		},
+		{
+			name:  "inline if: without else",
+			input: `{ if ok { "Yes" } }`,
+			expected: InlineIfExpression{
+				Expression: Expression{
+					Value: "ok",
+					Range: Range{
+						From: Position{Index: 5, Line: 0, Col: 5},
+						To:   Position{Index: 7, Line: 0, Col: 7},
+					},
+				},
+				Then: Expression{
+					Value: `"Yes"`,
+					Range: Range{
+						From: Position{Index: 10, Line: 0, Col: 10},
+						To:   Position{Index: 15, Line: 0, Col: 15},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := inlineIfExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestInlineIfExpressionParserErrors(t *testing.T) {
+	input := parse.NewInput(`{ if ok { <div>Yes</div> } }`)
+	_, _, err := inlineIfExpression.Parse(input)
+	if err == nil {
+		t.Fatal("expected an error for an if statement containing elements")
+	}
+}
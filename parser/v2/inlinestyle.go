@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"io"
+	"strings"
+)
+
+// CSSDeclaration is a property and value pair within an inline style attribute.
+type CSSDeclaration struct {
+	Property string
+	Value    string
+}
+
+// StyleAttribute is a constant style attribute, parsed into its declarations.
+// It's only produced when the TemplateFileParser's ParseInlineStyle option is set.
+//
+//	style="color: red; background-image: url('a.png')"
+type StyleAttribute struct {
+	Declarations []CSSDeclaration
+}
+
+// Value returns the declarations formatted as the value of a style attribute.
+func (sa StyleAttribute) Value() string {
+	sb := new(strings.Builder)
+	for i, d := range sa.Declarations {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(d.Property)
+		sb.WriteString(": ")
+		sb.WriteString(d.Value)
+		sb.WriteString(";")
+	}
+	return sb.String()
+}
+
+func (sa StyleAttribute) String() string {
+	value := sa.Value()
+	return ConstantAttribute{
+		Name:        "style",
+		Value:       value,
+		SingleQuote: strings.Contains(value, `"`),
+	}.String()
+}
+
+func (sa StyleAttribute) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, sa.String())
+}
+
+// ParseCSSDeclarations parses the value of an inline style attribute into its declarations.
+// Semicolons within parentheses, e.g. `url(...)`, or within quotes don't end a declaration.
+// Empty declarations are ignored.
+func ParseCSSDeclarations(s string) (declarations []CSSDeclaration) {
+	var depth int
+	var quote rune
+	var start int
+	add := func(end int) {
+		declaration := strings.TrimSpace(s[start:end])
+		start = end + 1
+		if declaration == "" {
+			return
+		}
+		property, value, _ := strings.Cut(declaration, ":")
+		declarations = append(declarations, CSSDeclaration{
+			Property: strings.TrimSpace(property),
+			Value:    strings.TrimSpace(value),
+		})
+	}
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth > 0 {
+				depth--
+			}
+		case r == ';' && depth == 0:
+			add(i)
+		}
+	}
+	add(len(s))
+	return declarations
+}
+
+// parseInlineStyles replaces the constant style attributes of elements with a StyleAttribute.
+func parseInlineStyles(nodes []Node) []Node {
+	return mapElements(nodes, func(e Element) Element {
+		e.Attributes = mapAttributes(e.Attributes, func(a Attribute) Attribute {
+			if ca, ok := a.(ConstantAttribute); ok && strings.EqualFold(ca.Name, "style") {
+				return StyleAttribute{
+					Declarations: ParseCSSDeclarations(ca.Value),
+				}
+			}
+			return a
+		})
+		return e
+	})
+}
@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCSSDeclarations(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []CSSDeclaration
+	}{
+		{
+			name:  "single declaration",
+			input: `color: red`,
+			expected: []CSSDeclaration{
+				{Property: "color", Value: "red"},
+			},
+		},
+		{
+			name:  "trailing semicolon",
+			input: `color: red; background-color: blue;`,
+			expected: []CSSDeclaration{
+				{Property: "color", Value: "red"},
+				{Property: "background-color", Value: "blue"},
+			},
+		},
+		{
+			name:  "url containing semicolons",
+			input: `background-image: url(data:image/png;base64,iVBORw0KGgo=); color: red`,
+			expected: []CSSDeclaration{
+				{Property: "background-image", Value: "url(data:image/png;base64,iVBORw0KGgo=)"},
+				{Property: "color", Value: "red"},
+			},
+		},
+		{
+			name:  "quoted value containing semicolons",
+			input: `content: ";"; color: red`,
+			expected: []CSSDeclaration{
+				{Property: "content", Value: `";"`},
+				{Property: "color", Value: "red"},
+			},
+		},
+		{
+			name:     "empty style",
+			input:    ``,
+			expected: nil,
+		},
+		{
+			name:     "whitespace and semicolons only",
+			input:    ` ; ;`,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ParseCSSDeclarations(tt.input)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestStyleAttributeString(t *testing.T) {
+	attr := StyleAttribute{
+		Declarations: []CSSDeclaration{
+			{Property: "color", Value: "red"},
+			{Property: "background-image", Value: `url("a.png")`},
+		},
+	}
+	expected := `style='color: red; background-image: url("a.png");'`
+	if diff := cmp.Diff(expected, attr.String()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTemplateFileParserParseInlineStyle(t *testing.T) {
+	input := `package main
+
+templ Name() {
+	<div style="color: red;">
+		<span style="">Empty</span>
+	</div>
+}
+`
+	var tests = []struct {
+		name             string
+		parseInlineStyle bool
+		expected         []Attribute
+	}{
+		{
+			name:             "styles are constant attributes by default",
+			parseInlineStyle: false,
+			expected: []Attribute{
+				ConstantAttribute{
+					Name:  "style",
+					Value: "color: red;",
+					Range: Range{
+						From: Position{
+							Index: 35,
+							Line:  3,
+							Col:   6,
+						},
+						To: Position{
+							Index: 54,
+							Line:  3,
+							Col:   25,
+						},
+					},
+				},
+				ConstantAttribute{
+					Name:  "style",
+					Value: "",
+					Range: Range{
+						From: Position{
+							Index: 64,
+							Line:  4,
+							Col:   8,
+						},
+						To: Position{
+							Index: 72,
+							Line:  4,
+							Col:   16,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:             "styles are parsed when the option is set",
+			parseInlineStyle: true,
+			expected: []Attribute{
+				StyleAttribute{Declarations: []CSSDeclaration{{Property: "color", Value: "red"}}},
+				StyleAttribute{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTemplateFileParser("main")
+			p.ParseInlineStyle = tt.parseInlineStyle
+			tf, ok, err := p.Parse(parse.NewInput(input))
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatal("failed to parse template file")
+			}
+			div := firstElement(t, tf.Nodes[0].(HTMLTemplate).Children)
+			span := firstElement(t, div.Children)
+			actual := []Attribute{div.Attributes[0], span.Attributes[0]}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func firstElement(t *testing.T, nodes []Node) Element {
+	t.Helper()
+	for _, n := range nodes {
+		if e, ok := n.(Element); ok {
+			return e
+		}
+	}
+	t.Fatal("no element found")
+	return Element{}
+}
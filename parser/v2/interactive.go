@@ -0,0 +1,20 @@
+package parser
+
+import "strings"
+
+var interactiveElements = map[string]struct{}{
+	"button": {}, "details": {}, "dialog": {}, "input": {}, "select": {}, "textarea": {},
+}
+
+// InteractiveElements returns the <dialog>, <details> and <button> elements, and the form
+// controls (<input>, <select> and <textarea>) within the template, e.g. to audit them for
+// accessibility. Nested elements are listed before the element that contains them.
+func (t HTMLTemplate) InteractiveElements() (elements []Element) {
+	mapElements(t.Children, func(e Element) Element {
+		if _, ok := interactiveElements[strings.ToLower(e.Name)]; ok {
+			elements = append(elements, e)
+		}
+		return e
+	})
+	return elements
+}
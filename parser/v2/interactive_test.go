@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestInteractiveElements(t *testing.T) {
+	input := parse.NewInput(`templ Name() {
+	<details open>
+		<summary>Settings</summary>
+		<input type="checkbox" name="notify"/>
+	</details>
+	<dialog open>
+		<p>Saved</p>
+		<button>Close</button>
+	</dialog>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+
+	elements := tem.InteractiveElements()
+	var names []string
+	for _, e := range elements {
+		names = append(names, e.Name)
+	}
+	if diff := cmp.Diff([]string{"input", "details", "button", "dialog"}, names); diff != "" {
+		t.Fatal(diff)
+	}
+
+	for _, e := range []Element{elements[1], elements[3]} {
+		open, ok := e.Attr("open")
+		if !ok {
+			t.Errorf("<%s>: expected an open attribute", e.Name)
+		}
+		if diff := cmp.Diff(BoolConstantAttribute{Name: "open"}, open, cmpopts.IgnoreTypes(Range{})); diff != "" {
+			t.Errorf("<%s>: %s", e.Name, diff)
+		}
+		if e.IsVoidElement() {
+			t.Errorf("<%s>: expected not to be a void element", e.Name)
+		}
+		if len(e.Children) == 0 {
+			t.Errorf("<%s>: expected children to be parsed", e.Name)
+		}
+	}
+}
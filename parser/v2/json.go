@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	nodeInterfaceType      = reflect.TypeOf((*Node)(nil)).Elem()
+	attributeInterfaceType = reflect.TypeOf((*Attribute)(nil)).Elem()
+)
+
+// jsonNodeTypes and jsonAttributeTypes are the types that FromJSON can decode, keyed by name.
+var (
+	jsonNodeTypes = jsonTypes(
+		Whitespace{}, DocType{}, Text{}, Element{}, RawElement{}, GoComment{}, ConstBlock{},
+		GoCode{}, HTMLComment{}, CData{}, CallTemplateExpression{}, TemplElementExpression{},
+		JSONScriptExpression{}, OnceExpression{}, AssetExpression{}, RawHTML{}, TranslationExpression{},
+		FragmentBlock{}, ChildrenExpression{}, IfExpression{}, SwitchExpression{}, ForExpression{},
+		StringExpression{}, InlineIfExpression{},
+	)
+	jsonAttributeTypes = jsonTypes(
+		BoolConstantAttribute{}, ConstantAttribute{}, BoolExpressionAttribute{},
+		ExpressionAttribute{}, ClassAttribute{}, SpreadAttributes{}, ConditionalAttribute{},
+		StyleAttribute{}, SrcsetAttribute{},
+	)
+)
+
+func jsonTypes(values ...any) map[string]reflect.Type {
+	types := make(map[string]reflect.Type, len(values))
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		types[t.Name()] = t
+	}
+	return types
+}
+
+// ToJSON encodes the nodes as JSON, e.g. to send the parsed template to a tool that isn't
+// written in Go. Each node is an object with a "type" field containing the name of the Go type,
+// e.g. "Element", and each attribute has a "kind" field instead, e.g. "ConstantAttribute". The
+// other fields of the node are included with the first letter in lower case, e.g. "children".
+// Fields with zero values are omitted. Use FromJSON to decode the nodes.
+func ToJSON(nodes []Node) ([]byte, error) {
+	v, err := toJSONValue(reflect.ValueOf(nodes))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func toJSONValue(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		discriminator := "type"
+		if v.Type() == attributeInterfaceType {
+			discriminator = "kind"
+		}
+		elem := v.Elem()
+		if elem.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("json: unsupported %s %v", discriminator, elem.Type())
+		}
+		m, err := structToJSONValue(elem)
+		if err != nil {
+			return nil, err
+		}
+		m[discriminator] = elem.Type().Name()
+		return m, nil
+	case reflect.Struct:
+		return structToJSONValue(v)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return toJSONValue(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		items := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := toJSONValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return v.Interface(), nil
+}
+
+func structToJSONValue(v reflect.Value) (map[string]any, error) {
+	m := map[string]any{}
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := v.Type().Field(i), v.Field(i)
+		if !f.IsExported() || fv.IsZero() {
+			continue
+		}
+		value, err := toJSONValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", v.Type().Name(), f.Name, err)
+		}
+		m[jsonFieldName(f.Name)] = value
+	}
+	return m, nil
+}
+
+func jsonFieldName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// FromJSON decodes nodes encoded by ToJSON.
+func FromJSON(data []byte) (nodes []Node, err error) {
+	v := reflect.New(reflect.TypeOf(nodes)).Elem()
+	if err = fromJSONValue(data, v); err != nil {
+		return nil, err
+	}
+	return v.Interface().([]Node), nil
+}
+
+func fromJSONValue(data json.RawMessage, v reflect.Value) error {
+	if string(data) == "null" {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		discriminator, types := "type", jsonNodeTypes
+		if v.Type() == attributeInterfaceType {
+			discriminator, types = "kind", jsonAttributeTypes
+		} else if v.Type() != nodeInterfaceType {
+			return fmt.Errorf("json: unsupported interface %v", v.Type())
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		var name string
+		if err := json.Unmarshal(fields[discriminator], &name); err != nil {
+			return fmt.Errorf("json: invalid %s: %w", discriminator, err)
+		}
+		t, ok := types[name]
+		if !ok {
+			return fmt.Errorf("json: unknown %s %q", discriminator, name)
+		}
+		elem := reflect.New(t).Elem()
+		if err := structFromJSONValue(fields, elem); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		return structFromJSONValue(fields, v)
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := fromJSONValue(data, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := fromJSONValue(item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+func structFromJSONValue(fields map[string]json.RawMessage, v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		data, ok := fields[jsonFieldName(f.Name)]
+		if !f.IsExported() || !ok {
+			continue
+		}
+		if err := fromJSONValue(data, v.Field(i)); err != nil {
+			return fmt.Errorf("%s.%s: %w", v.Type().Name(), f.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSON(t *testing.T) {
+	input := parse.NewInput(`templ x(items []Item, ok bool) {
+	<!DOCTYPE html>
+	<div class="list" data-count={ len(items) } hidden?={ !ok } if ok { aria-busy="false" }>
+		<!-- items -->
+		<ul>
+			for _, item := range items {
+				<li class={ "item", templ.KV("selected", item.Selected) }>{ item.Name } <b>!</b></li>
+			}
+		</ul>
+		switch len(items) {
+			case 0:
+				None
+			default:
+				@Summary(items) {
+					{ children... }
+				}
+		}
+		{{ total := 0 }}
+		<script>var x = 1;</script>
+	</div>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	data, err := ToJSON(tem.Children)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	actual, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if diff := cmp.Diff(tem.Children, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	nodes := []Node{
+		Element{
+			Name: "a",
+			Attributes: []Attribute{
+				ConstantAttribute{Name: "href", Value: "/"},
+				BoolConstantAttribute{Name: "download"},
+			},
+			Children: []Node{
+				Text{Value: "Home"},
+			},
+		},
+	}
+	data, err := ToJSON(nodes)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	var actual any
+	if err = json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	var expected any
+	if err = json.Unmarshal([]byte(`[{
+		"type": "Element",
+		"name": "a",
+		"attributes": [
+			{"kind": "ConstantAttribute", "name": "href", "value": "/"},
+			{"kind": "BoolConstantAttribute", "name": "download"}
+		],
+		"children": [
+			{"type": "Text", "value": "Home"}
+		]
+	}]`), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	_, err := FromJSON([]byte(`[{"type": "Unknown"}]`))
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if diff := cmp.Diff(`json: unknown type "Unknown"`, err.Error()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
+)
+
+var jsonScriptExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	// Check the prefix first.
+	if !peekPrefix(pi, "@templ.JSONScript(") {
+		return n, false, nil
+	}
+	pi.Take(len("@"))
+
+	// Once we have a prefix, we must have a call with the ID and data.
+	from := pi.Index()
+	src, _ := pi.Peek(-1)
+	args, end, err := goexpression.Call(src)
+	if err != nil {
+		return n, false, parse.Error("@templ.JSONScript: invalid go expression: "+err.Error(), pi.Position())
+	}
+	if len(args) != 2 {
+		return n, false, parse.Error("@templ.JSONScript: expected an id and data argument", pi.Position())
+	}
+	r := JSONScriptExpression{
+		ID:   NewExpression(src[args[0].Start:args[0].End], pi.PositionAt(from+args[0].Start), pi.PositionAt(from+args[0].End)),
+		Data: NewExpression(src[args[1].Start:args[1].End], pi.PositionAt(from+args[1].Start), pi.PositionAt(from+args[1].End)),
+	}
+	pi.Take(end)
+
+	return r, true, nil
+})
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONScriptExpressionParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected JSONScriptExpression
+	}{
+		{
+			name:  "jsonscript: constant id",
+			input: `@templ.JSONScript("user-data", user)`,
+			expected: JSONScriptExpression{
+				ID: Expression{
+					Value: `"user-data"`,
+					Range: Range{
+						From: Position{Index: 18, Line: 0, Col: 18},
+						To:   Position{Index: 29, Line: 0, Col: 29},
+					},
+				},
+				Data: Expression{
+					Value: `user`,
+					Range: Range{
+						From: Position{Index: 31, Line: 0, Col: 31},
+						To:   Position{Index: 35, Line: 0, Col: 35},
+					},
+				},
+			},
+		},
+		{
+			name: "jsonscript: multiline arguments",
+			input: `@templ.JSONScript(
+	id,
+	map[string]int{"a": 1},
+)`,
+			expected: JSONScriptExpression{
+				ID: Expression{
+					Value: `id`,
+					Range: Range{
+						From: Position{Index: 20, Line: 1, Col: 1},
+						To:   Position{Index: 22, Line: 1, Col: 3},
+					},
+				},
+				Data: Expression{
+					Value: `map[string]int{"a": 1}`,
+					Range: Range{
+						From: Position{Index: 25, Line: 2, Col: 1},
+						To:   Position{Index: 47, Line: 2, Col: 23},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := jsonScriptExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if input.Index() != len(tt.input) {
+				t.Errorf("expected all input to be consumed, got index %d", input.Index())
+			}
+		})
+	}
+}
+
+func TestJSONScriptExpressionParserErrors(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "jsonscript: missing data",
+			input: `@templ.JSONScript("id")`,
+		},
+		{
+			name:  "jsonscript: unclosed call",
+			input: `@templ.JSONScript("id", data`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := jsonScriptExpression.Parse(parse.NewInput(tt.input))
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
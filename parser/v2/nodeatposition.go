@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"reflect"
+)
+
+// NodeAtPosition returns the most deeply nested node with source that contains the line and
+// column of pos, e.g. to find the node under the cursor in an editor. The index of pos isn't
+// used, since editors work in lines and columns.
+//
+// Only the parts of the template that are recorded in the parse tree have a position, i.e. Go
+// expressions, such as an if expression's condition, or an element's expression attributes, and
+// HTML comments. Positions elsewhere, e.g. within text, whitespace or an element's tag name, return
+// false. A position on either end of a range is within it.
+func NodeAtPosition(nodes []Node, pos Position) (n Node, ok bool) {
+	Walk(nodes, func(candidate Node) bool {
+		nodeRanges(reflect.ValueOf(candidate), func(r Range) {
+			if r.containsLineCol(pos) {
+				n, ok = candidate, true
+			}
+		})
+		return true
+	})
+	return n, ok
+}
+
+var (
+	nodeListType   = reflect.TypeOf([]Node{})
+	diagnosticType = reflect.TypeOf(Diagnostic{})
+)
+
+// nodeRangeTypes have a Range that covers the whole node, e.g. an element's tags and children.
+// It isn't used, so that only the expressions within them are found.
+var nodeRangeTypes = map[reflect.Type]bool{
+	reflect.TypeOf(Element{}):                true,
+	reflect.TypeOf(RawElement{}):             true,
+	reflect.TypeOf(Text{}):                   true,
+	reflect.TypeOf(StringExpression{}):       true,
+	reflect.TypeOf(IfExpression{}):           true,
+	reflect.TypeOf(ForExpression{}):          true,
+	reflect.TypeOf(SwitchExpression{}):       true,
+	reflect.TypeOf(TemplElementExpression{}): true,
+}
+
+// nodeRanges calls fn with the range of each expression within the node, including those within
+// attributes, and the branches of if and switch expressions, but not within child nodes.
+func nodeRanges(v reflect.Value, fn func(r Range)) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		switch v.Type() {
+		case expressionType:
+			fn(v.Interface().(Expression).Range)
+			return
+		case rangeType:
+			fn(v.Interface().(Range))
+			return
+		case diagnosticType:
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.IsExported() && !(f.Name == "Range" && nodeRangeTypes[v.Type()]) {
+				nodeRanges(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice:
+		if v.Type() == nodeListType {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			nodeRanges(v.Index(i), fn)
+		}
+	}
+}
+
+func (r Range) containsLineCol(pos Position) bool {
+	if r == (Range{}) {
+		return false
+	}
+	after := pos.Line > r.From.Line || (pos.Line == r.From.Line && pos.Col >= r.From.Col)
+	before := pos.Line < r.To.Line || (pos.Line == r.To.Line && pos.Col <= r.To.Col)
+	return after && before
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+)
+
+func TestNodeAtPosition(t *testing.T) {
+	input := parse.NewInput(`templ x(items []Item) {
+	<ul class={ listClass }>
+		for _, item := range items {
+			if item.Visible {
+				<li>{ item.Name }</li>
+			}
+		}
+	</ul>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		line     uint32
+		col      uint32
+		expected string
+	}{
+		{
+			name:     "string expression nested within elements, for and if expressions",
+			line:     4,
+			col:      12,
+			expected: "StringExpression: item.Name",
+		},
+		{
+			name:     "start of a range",
+			line:     4,
+			col:      10,
+			expected: "StringExpression: item.Name",
+		},
+		{
+			name:     "end of a range",
+			line:     4,
+			col:      19,
+			expected: "StringExpression: item.Name",
+		},
+		{
+			name:     "if expression condition",
+			line:     3,
+			col:      10,
+			expected: "IfExpression: item.Visible",
+		},
+		{
+			name:     "for expression",
+			line:     2,
+			col:      8,
+			expected: "ForExpression: _, item := range items",
+		},
+		{
+			name:     "element expression attribute",
+			line:     1,
+			col:      14,
+			expected: "Element: ul",
+		},
+		{
+			name: "whitespace",
+			line: 4,
+			col:  1,
+		},
+		{
+			name: "text outside of a range",
+			line: 4,
+			col:  9,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			n, ok := NodeAtPosition(tem.Children, Position{Line: tt.line, Col: tt.col})
+			var actual string
+			switch n := n.(type) {
+			case StringExpression:
+				actual = "StringExpression: " + n.Expression.Value
+			case IfExpression:
+				actual = "IfExpression: " + n.Expression.Value
+			case ForExpression:
+				actual = "ForExpression: " + n.Expression.Value
+			case Element:
+				actual = "Element: " + n.Name
+			}
+			if ok != (tt.expected != "") {
+				t.Fatalf("expected ok to be %v, got %v, with node %#v", tt.expected != "", ok, n)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
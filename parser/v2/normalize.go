@@ -0,0 +1,69 @@
+package parser
+
+import "strings"
+
+// Normalize returns a copy of the template where each run of adjacent Text and Whitespace
+// nodes that starts and ends with Text is merged into a single Text node, similar to the DOM
+// normalize() method. The merged text renders the same output as the original nodes.
+// Whitespace before the first and after the last Text node of a run is left as-is, since it
+// may be stripped at the start or end of an element.
+func Normalize(t HTMLTemplate) HTMLTemplate {
+	t.Children = mapNodeLists(t.Children, mergeAdjacentText)
+	return t
+}
+
+func mergeAdjacentText(nodes []Node) []Node {
+	op := make([]Node, 0, len(nodes))
+	for i := 0; i < len(nodes); i++ {
+		start, ok := nodes[i].(Text)
+		if !ok {
+			op = append(op, nodes[i])
+			continue
+		}
+		// Find the last Text node in the run.
+		end := i
+		for j := i + 1; j < len(nodes); j++ {
+			if _, isText := nodes[j].(Text); isText {
+				end = j
+				continue
+			}
+			if _, isWhitespace := nodes[j].(Whitespace); isWhitespace {
+				continue
+			}
+			break
+		}
+		if end == i {
+			op = append(op, start)
+			continue
+		}
+		op = append(op, mergeText(nodes[i:end+1]))
+		i = end
+	}
+	return op
+}
+
+// mergeText merges a run of Text and Whitespace nodes that starts and ends with Text.
+func mergeText(nodes []Node) Text {
+	var sb strings.Builder
+	var merged Text
+	for i, n := range nodes {
+		switch n := n.(type) {
+		case Text:
+			sb.WriteString(n.Value)
+			merged.TrailingSpace = n.TrailingSpace
+			// Trailing space is rendered as a single space if the next node is text.
+			if i < len(nodes)-1 && n.TrailingSpace != SpaceNone {
+				if _, nextIsText := nodes[i+1].(Text); nextIsText {
+					sb.WriteString(" ")
+				}
+			}
+		case Whitespace:
+			// Whitespace nodes are rendered as a single space.
+			if n.Value != "" {
+				sb.WriteString(" ")
+			}
+		}
+	}
+	merged.Value = sb.String()
+	return merged
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalize(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    []Node
+		expected []Node
+	}{
+		{
+			name: "adjacent text and whitespace is merged",
+			input: []Node{
+				Text{Value: "Hello,", TrailingSpace: SpaceHorizontal},
+				Text{Value: "world"},
+				Whitespace{Value: "\n\t"},
+				Text{Value: "and"},
+				Text{Value: "goodbye", TrailingSpace: SpaceVertical},
+			},
+			expected: []Node{
+				Text{Value: "Hello, world andgoodbye", TrailingSpace: SpaceVertical},
+			},
+		},
+		{
+			name: "whitespace outside of the run is not merged",
+			input: []Node{
+				Whitespace{Value: "\t"},
+				Text{Value: "a", TrailingSpace: SpaceHorizontal},
+				Text{Value: "b"},
+				Whitespace{Value: "\n"},
+			},
+			expected: []Node{
+				Whitespace{Value: "\t"},
+				Text{Value: "a b"},
+				Whitespace{Value: "\n"},
+			},
+		},
+		{
+			name: "text separated by other nodes is not merged",
+			input: []Node{
+				Text{Value: "a"},
+				Element{Name: "br"},
+				Text{Value: "b"},
+			},
+			expected: []Node{
+				Text{Value: "a"},
+				Element{Name: "br"},
+				Text{Value: "b"},
+			},
+		},
+		{
+			name: "nested text is merged",
+			input: []Node{
+				Element{
+					Name: "p",
+					Children: []Node{
+						IfExpression{
+							Then: []Node{
+								Text{Value: "a", TrailingSpace: SpaceHorizontal},
+								Text{Value: "b"},
+							},
+						},
+					},
+				},
+			},
+			expected: []Node{
+				Element{
+					Name: "p",
+					Children: []Node{
+						IfExpression{
+							Then: []Node{
+								Text{Value: "a b"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Normalize(HTMLTemplate{Children: tt.input})
+			if diff := cmp.Diff(tt.expected, actual.Children); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
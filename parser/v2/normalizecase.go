@@ -0,0 +1,68 @@
+package parser
+
+import "strings"
+
+// foreignElements contain SVG or MathML, where element and attribute names are case sensitive,
+// e.g. <linearGradient> or viewBox.
+var foreignElements = map[string]struct{}{
+	"svg": {}, "math": {},
+}
+
+func isForeignElement(name string) bool {
+	_, ok := foreignElements[strings.ToLower(name)]
+	return ok
+}
+
+// normalizeCase lowercases the names of HTML elements and their attributes. SVG and MathML
+// elements, and everything within them, are left as they were written. Attribute values and
+// text aren't changed.
+func normalizeCase(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	op := make([]Node, len(nodes))
+	for i, n := range nodes {
+		op[i] = normalizeNodeCase(n)
+	}
+	return op
+}
+
+func normalizeNodeCase(node Node) Node {
+	switch n := node.(type) {
+	case Element:
+		if isForeignElement(n.Name) {
+			return n
+		}
+		n.Name = strings.ToLower(n.Name)
+		n.Attributes = mapAttributes(n.Attributes, lowercaseAttributeName)
+		node = n
+	case RawElement:
+		n.Name = strings.ToLower(n.Name)
+		n.Attributes = mapAttributes(n.Attributes, lowercaseAttributeName)
+		return n
+	}
+	return mapChildNodeLists(node, normalizeCase)
+}
+
+// lowercaseAttributeName lowercases the name of the attribute. Spread attributes don't have a
+// name, and the names of StyleAttribute and SrcsetAttribute are already lowercase.
+func lowercaseAttributeName(attr Attribute) Attribute {
+	switch a := attr.(type) {
+	case BoolConstantAttribute:
+		a.Name = strings.ToLower(a.Name)
+		return a
+	case ConstantAttribute:
+		a.Name = strings.ToLower(a.Name)
+		return a
+	case BoolExpressionAttribute:
+		a.Name = strings.ToLower(a.Name)
+		return a
+	case ExpressionAttribute:
+		a.Name = strings.ToLower(a.Name)
+		return a
+	case ClassAttribute:
+		a.Name = strings.ToLower(a.Name)
+		return a
+	}
+	return attr
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTemplateFileParserNormalizeCase(t *testing.T) {
+	input := `package main
+
+templ Name() {
+	<DIV CLASS="Mixed Case" Data-Id="A1"><P>HELLO, World</P><BR/><SCRIPT TYPE="module">var X = "<B>";</SCRIPT><svg viewBox="0 0 10 10"><linearGradient gradientUnits="userSpaceOnUse"></linearGradient></svg></DIV>
+}
+`
+	var tests = []struct {
+		name          string
+		normalizeCase bool
+		expected      string
+	}{
+		{
+			name:          "names keep their case by default",
+			normalizeCase: false,
+			expected:      `<DIV CLASS="Mixed Case" Data-Id="A1"><P>HELLO, World</P><BR><SCRIPT TYPE="module">var X = "<B>";</SCRIPT><svg viewBox="0 0 10 10"><linearGradient gradientUnits="userSpaceOnUse"></linearGradient></svg></DIV>`,
+		},
+		{
+			name:          "HTML names are lowercased when the option is set",
+			normalizeCase: true,
+			expected:      `<div class="Mixed Case" data-id="A1"><p>HELLO, World</p><br><script type="module">var X = "<B>";</script><svg viewBox="0 0 10 10"><linearGradient gradientUnits="userSpaceOnUse"></linearGradient></svg></div>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTemplateFileParser("main")
+			p.NormalizeCase = tt.normalizeCase
+			tf, ok, err := p.Parse(parse.NewInput(input))
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatal("failed to parse template file")
+			}
+			w := new(strings.Builder)
+			if err := Render(w, tf.Nodes[0].(HTMLTemplate).Children); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, strings.TrimSpace(w.String())); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("expression and conditional attribute names are lowercased", func(t *testing.T) {
+		input := `package main
+
+templ Name(id string, ok bool) {
+	<INPUT ID={ id } Disabled?={ ok } if ok { Data-OK="Yes" } { "Spread"... }/>
+}
+`
+		p := NewTemplateFileParser("main")
+		p.NormalizeCase = true
+		tf, ok, err := p.Parse(parse.NewInput(input))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse template file: %v", err)
+		}
+		e := firstElement(t, tf.Nodes[0].(HTMLTemplate).Children)
+		var names []string
+		for _, attr := range e.Attributes {
+			if ca, ok := attr.(ConditionalAttribute); ok {
+				for _, attr := range ca.Then {
+					names = append(names, attributeName(attr))
+				}
+				continue
+			}
+			names = append(names, attributeName(attr))
+		}
+		if diff := cmp.Diff("input", e.Name); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"id", "disabled", "data-ok", ""}, names); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
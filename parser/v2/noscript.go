@@ -0,0 +1,22 @@
+package parser
+
+import "strings"
+
+// IsNoscript returns true if the element is a <noscript> element, which browsers only display
+// when scripting is disabled.
+func (e Element) IsNoscript() bool {
+	return strings.EqualFold(e.Name, "noscript")
+}
+
+// NoscriptBlocks returns the <noscript> elements within the template, e.g. so that a renderer
+// that knows whether scripting is enabled can decide whether to include them. Nested elements
+// are listed before the element that contains them.
+func (t HTMLTemplate) NoscriptBlocks() (elements []Element) {
+	mapElements(t.Children, func(e Element) Element {
+		if e.IsNoscript() {
+			elements = append(elements, e)
+		}
+		return e
+	})
+	return elements
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNoscriptBlocks(t *testing.T) {
+	input := parse.NewInput(`templ Name() {
+	<script src="/app.js"></script>
+	<noscript>
+		<p>This page needs JavaScript, or use the <a href="/basic">basic version</a>.</p>
+	</noscript>
+	<div>After</div>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+
+	blocks := tem.NoscriptBlocks()
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 noscript block, got %d", len(blocks))
+	}
+	noscript := blocks[0]
+	if !noscript.IsNoscript() {
+		t.Error("expected the element to be flagged as noscript")
+	}
+	elements := func(nodes []Node) (op []Element) {
+		for _, n := range nodes {
+			if e, ok := n.(Element); ok {
+				op = append(op, e)
+			}
+		}
+		return op
+	}
+	children := elements(noscript.Children)
+	if len(children) != 1 || children[0].Name != "p" {
+		t.Fatalf("expected the <p> element to be parsed as a child, got %#v", noscript.Children)
+	}
+	links := elements(children[0].Children)
+	if len(links) != 1 || links[0].Name != "a" {
+		t.Fatalf("expected the <a> element to be parsed, got %#v", children[0].Children)
+	}
+	link := links[0]
+	if diff := cmp.Diff(ConstantAttribute{Name: "href", Value: "/basic"}, link.Attributes[0], cmpopts.IgnoreTypes(Range{})); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]Node{Text{Value: "basic version", Range: Range{
+		From: Position{Index: 121, Line: 3, Col: 61},
+		To:   Position{Index: 134, Line: 3, Col: 74},
+	}}}, link.Children); diff != "" {
+		t.Error(diff)
+	}
+
+	// Content after the noscript element is parsed as normal.
+	last, ok := tem.Children[len(tem.Children)-1].(Element)
+	if !ok || last.Name != "div" || last.IsNoscript() {
+		t.Errorf("expected the <div> after the noscript element, got %#v", tem.Children[len(tem.Children)-1])
+	}
+}
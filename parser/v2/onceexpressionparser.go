@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+)
+
+var onceExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+
+	// Check the prefix first.
+	if !peekPrefix(pi, "@once {", "@once{") {
+		return n, false, nil
+	}
+	pi.Take(len("@once"))
+	if _, ok, err = openBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return n, false, err
+	}
+
+	// Node contents.
+	var r OnceExpression
+	np := newTemplateNodeParser(closeBraceWithOptionalPadding, "@once closing brace")
+	var nodes Nodes
+	if nodes, ok, err = np.Parse(pi); err != nil || !ok {
+		err = parse.Error("@once: expected nodes, but none were found", pi.Position())
+		return
+	}
+	r.Children = nodes.Nodes
+	r.Diagnostics = nodes.Diagnostics
+
+	// Read the required closing brace.
+	if _, ok, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		err = parse.Error("@once: "+unterminatedMissingEnd, pi.Position())
+		return
+	}
+
+	return r, true, nil
+})
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOnceExpressionParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected OnceExpression
+	}{
+		{
+			name: "once: block with a script",
+			input: `@once {
+	<script src="/button.js"></script>
+}`,
+			expected: OnceExpression{
+				Children: []Node{
+					Whitespace{Value: "\n\t"},
+					RawElement{
+						Name: "script",
+						Attributes: []Attribute{
+							ConstantAttribute{
+								Name:  "src",
+								Value: "/button.js",
+								Range: Range{
+									From: Position{
+										Index: 17,
+										Line:  1,
+										Col:   9,
+									},
+									To: Position{
+										Index: 33,
+										Line:  1,
+										Col:   25,
+									},
+								},
+							},
+						},
+						Range: Range{
+							From: Position{
+								Index: 9,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 43,
+								Line:  1,
+								Col:   35,
+							},
+						},
+					},
+					Whitespace{Value: "\n"},
+				},
+			},
+		},
+		{
+			name:  "once: without padding",
+			input: `@once{<style>.button { color: red; }</style>}`,
+			expected: OnceExpression{
+				Children: []Node{
+					RawElement{
+						Name:     "style",
+						Contents: ".button { color: red; }",
+						Range: Range{
+							From: Position{
+								Index: 6,
+								Line:  0,
+								Col:   6,
+							},
+							To: Position{
+								Index: 44,
+								Line:  0,
+								Col:   44,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := onceExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestOnceExpressionParserDoesNotMatchTemplates(t *testing.T) {
+	input := parse.NewInput(`@onceMore()`)
+	_, ok, err := onceExpression.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected no match")
+	}
+	if input.Index() != 0 {
+		t.Errorf("expected no input to be consumed, got index %d", input.Index())
+	}
+}
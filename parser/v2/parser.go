@@ -10,12 +10,16 @@ var expressionFuncEnd = parse.All(parse.Rune(')'), openBraceWithOptionalPadding)
 // Template
 
 var template = parse.Func(func(pi *parse.Input) (r HTMLTemplate, ok bool, err error) {
+	// The template is only returned once it's complete, so that callers get an empty template,
+	// not a partial one, when there's an error.
+	var t HTMLTemplate
+
 	// templ FuncName(p Person, other Other) {
 	var te templateExpression
 	if te, ok, err = templateExpressionParser.Parse(pi); err != nil || !ok {
 		return
 	}
-	r.Expression = te.Expression
+	t.Expression = te.Expression
 
 	// Once we're in a template, we should expect some template whitespace, if/switch/for,
 	// or node string expressions etc.
@@ -28,8 +32,8 @@ var template = parse.Func(func(pi *parse.Input) (r HTMLTemplate, ok bool, err er
 		err = parse.Error("templ: expected nodes in templ body, but found none", pi.Position())
 		return
 	}
-	r.Children = nodes.Nodes
-	r.Diagnostics = nodes.Diagnostics
+	t.Children = trimWhitespace(nodes.Nodes)
+	t.Diagnostics = nodes.Diagnostics
 
 	// Eat any whitespace.
 	_, _, err = parse.OptionalWhitespace.Parse(pi)
@@ -43,5 +47,5 @@ var template = parse.Func(func(pi *parse.Input) (r HTMLTemplate, ok bool, err er
 		return
 	}
 
-	return r, true, nil
+	return t, true, nil
 })
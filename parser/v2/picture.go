@@ -0,0 +1,21 @@
+package parser
+
+import "strings"
+
+// PictureSources returns the attributes of the <source> elements within each <picture>
+// element in the template, in the order they appear. The attributes of each source are in
+// the order they're written, so that media conditions are preserved.
+func (t HTMLTemplate) PictureSources() (sources [][]Attribute) {
+	mapElements(t.Children, func(e Element) Element {
+		if !strings.EqualFold(e.Name, "picture") {
+			return e
+		}
+		for _, c := range e.Children {
+			if source, ok := c.(Element); ok && strings.EqualFold(source.Name, "source") {
+				sources = append(sources, source.Attributes)
+			}
+		}
+		return e
+	})
+	return sources
+}
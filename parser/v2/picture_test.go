@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPictureSources(t *testing.T) {
+	input := parse.NewInput(`templ Name() {
+	<picture>
+		<source media="(min-width: 800px)" srcset="large.webp 1x, large@2x.webp 2x" type="image/webp"/>
+		<source media="(min-width: 400px)" srcset="medium.jpg"/>
+		<img src="small.jpg" alt="A photo"/>
+	</picture>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+	expected := [][]Attribute{
+		{
+			ConstantAttribute{Name: "media", Value: "(min-width: 800px)"},
+			ConstantAttribute{Name: "srcset", Value: "large.webp 1x, large@2x.webp 2x"},
+			ConstantAttribute{Name: "type", Value: "image/webp"},
+		},
+		{
+			ConstantAttribute{Name: "media", Value: "(min-width: 400px)"},
+			ConstantAttribute{Name: "srcset", Value: "medium.jpg"},
+		},
+	}
+	if diff := cmp.Diff(expected, tem.PictureSources(), cmpopts.IgnoreTypes(Range{})); diff != "" {
+		t.Error(diff)
+	}
+}
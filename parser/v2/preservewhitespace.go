@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/a-h/parse"
+)
+
+// whitespaceSensitiveElements contain text where whitespace is significant.
+var whitespaceSensitiveElements = map[string]struct{}{
+	"pre": {}, "textarea": {},
+}
+
+func isWhitespaceSensitive(name string) bool {
+	_, ok := whitespaceSensitiveElements[strings.ToLower(name)]
+	return ok
+}
+
+// parsePreservedNode parses a node within a whitespace sensitive element. Whitespace nodes, and
+// the trailing whitespace of nodes, are collapsed when they're rendered, so the whitespace is
+// returned as Text instead, with the same value as the source.
+func parsePreservedNode(pi *parse.Input) (node Node, matched bool, err error) {
+	start := pi.Index()
+	for _, p := range templateNodeParsers {
+		if _, isElement := p.(elementParser); isElement {
+			p = elementParser{preserveWhitespace: true}
+		}
+		if node, matched, err = p.Parse(pi); err != nil || matched {
+			break
+		}
+	}
+	if err != nil || !matched {
+		return node, matched, err
+	}
+	if ws, ok := node.(Whitespace); ok {
+		return Text{Value: ws.Value, Range: NewRange(pi.PositionAt(start), pi.Position())}, true, nil
+	}
+	if wt, ok := node.(WhitespaceTrailer); ok && wt.Trailing() != SpaceNone {
+		// Go back to the start of the trailing whitespace, so that it's parsed as the next node.
+		end := pi.Index()
+		pi.Seek(start)
+		src, _ := pi.Take(end - start)
+		pi.Seek(start + len(strings.TrimRightFunc(src, unicode.IsSpace)))
+		node = withoutTrailingSpace(node)
+	}
+	return node, true, nil
+}
@@ -0,0 +1,190 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Query returns the elements that match the CSS selector, in the order they appear in the
+// template, e.g. to find elements in tests. Elements within if, for and switch expressions, and
+// other nodes with children, are included.
+//
+// A subset of CSS selectors is supported: element names, e.g. `p`, ids, e.g. `#main`, classes,
+// e.g. `.a.b`, attributes, e.g. `[disabled]` or `[type="text"]`, and descendant combinators,
+// e.g. `ul li`. Ids, classes and attribute values only match constant attributes. An invalid
+// selector doesn't match any elements.
+func Query(nodes []Node, selector string) (matches []Element) {
+	sel, ok := parseSelector(selector)
+	if !ok {
+		return nil
+	}
+	query(nodes, nil, sel, &matches)
+	return matches
+}
+
+func query(nodes []Node, ancestors []Element, sel []compoundSelector, matches *[]Element) {
+	for _, n := range nodes {
+		e, ok := n.(Element)
+		if !ok {
+			query(ChildNodes(n), ancestors, sel, matches)
+			continue
+		}
+		if selectorMatches(sel, e, ancestors) {
+			*matches = append(*matches, e)
+		}
+		query(e.Children, append(ancestors, e), sel, matches)
+	}
+}
+
+// selectorMatches returns true if the last compound selector matches the element, and each of
+// the others matches one of its ancestors, in order.
+func selectorMatches(sel []compoundSelector, e Element, ancestors []Element) bool {
+	last := len(sel) - 1
+	if !sel[last].matches(e) {
+		return false
+	}
+	i := last - 1
+	for a := len(ancestors) - 1; a >= 0 && i >= 0; a-- {
+		if sel[i].matches(ancestors[a]) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+// compoundSelector is a sequence of simple selectors without a combinator, e.g. `a.b[c]`.
+type compoundSelector struct {
+	name    string
+	id      string
+	classes []string
+	attrs   []attributeSelector
+}
+
+type attributeSelector struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+func (cs compoundSelector) matches(e Element) bool {
+	if cs.name != "" && !strings.EqualFold(cs.name, e.Name) {
+		return false
+	}
+	if cs.id != "" && !constantAttributeEquals(e, "id", cs.id) {
+		return false
+	}
+	if len(cs.classes) > 0 {
+		classes := make(map[string]struct{})
+		for _, c := range e.ClassList() {
+			classes[c] = struct{}{}
+		}
+		for _, c := range cs.classes {
+			if _, ok := classes[c]; !ok {
+				return false
+			}
+		}
+	}
+	for _, as := range cs.attrs {
+		if as.hasValue && !constantAttributeEquals(e, as.name, as.value) {
+			return false
+		}
+		if !as.hasValue && !e.HasAttr(as.name) {
+			return false
+		}
+	}
+	return true
+}
+
+func constantAttributeEquals(e Element, name, value string) bool {
+	attr, ok := e.Attr(name)
+	if !ok {
+		return false
+	}
+	ca, ok := attr.(ConstantAttribute)
+	return ok && ca.Value == value
+}
+
+// parseSelector parses the compound selectors separated by descendant combinators.
+func parseSelector(s string) (sel []compoundSelector, ok bool) {
+	var inBrackets bool
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		switch r {
+		case '[':
+			inBrackets = true
+		case ']':
+			inBrackets = false
+		}
+		return !inBrackets && (r == ' ' || r == '\t' || r == '\n' || r == '\r')
+	})
+	for _, part := range parts {
+		cs, ok := parseCompoundSelector(part)
+		if !ok {
+			return nil, false
+		}
+		sel = append(sel, cs)
+	}
+	return sel, len(sel) > 0
+}
+
+func parseCompoundSelector(s string) (cs compoundSelector, ok bool) {
+	if strings.HasPrefix(s, "*") {
+		s = s[1:]
+	} else {
+		cs.name, s = cutSelectorIdentifier(s)
+	}
+	for s != "" {
+		var ident string
+		switch s[0] {
+		case '#':
+			if ident, s = cutSelectorIdentifier(s[1:]); ident == "" {
+				return cs, false
+			}
+			cs.id = ident
+		case '.':
+			if ident, s = cutSelectorIdentifier(s[1:]); ident == "" {
+				return cs, false
+			}
+			cs.classes = append(cs.classes, ident)
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return cs, false
+			}
+			as, ok := parseAttributeSelector(s[1:end])
+			if !ok {
+				return cs, false
+			}
+			cs.attrs = append(cs.attrs, as)
+			s = s[end+1:]
+		default:
+			return cs, false
+		}
+	}
+	return cs, true
+}
+
+// parseAttributeSelector parses the contents of an attribute selector, e.g. `type="text"`.
+func parseAttributeSelector(s string) (as attributeSelector, ok bool) {
+	name, value, hasValue := strings.Cut(s, "=")
+	if as.name, name = cutSelectorIdentifier(name); as.name == "" || name != "" {
+		return as, false
+	}
+	as.hasValue = hasValue
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	as.value = value
+	return as, true
+}
+
+// cutSelectorIdentifier returns the name, id or class at the start of s, and the rest of s.
+func cutSelectorIdentifier(s string) (ident, rest string) {
+	i := 0
+	for i < len(s) && isSelectorIdentifierByte(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isSelectorIdentifierByte(b byte) bool {
+	return b == '-' || b == '_' || b == ':' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQuery(t *testing.T) {
+	input := `templ x(items []Item) {
+	<main id="main" class="page wide">
+		<nav class="menu">
+			<a class="link active" href="/">Home</a>
+			<a class="link" href="/about">About</a>
+		</nav>
+		<form>
+			<input type="text" name="q"/>
+			<button type="submit" disabled>Search</button>
+		</form>
+		if ok {
+			<p class="note">Note</p>
+		}
+		for _, item := range items {
+			<a class={ item.Class } href={ item.URL }>{ item.Name }</a>
+		}
+	</main>
+	<p class="note">Footer</p>
+}`
+	tem, ok, err := template.Parse(parse.NewInput(input))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	var tests = []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "nav", expected: []string{"nav.menu"}},
+		{selector: "#main", expected: []string{"main.page wide"}},
+		{selector: ".link", expected: []string{"a.link active", "a.link"}},
+		{selector: ".link.active", expected: []string{"a.link active"}},
+		{selector: "a.active", expected: []string{"a.link active"}},
+		{selector: "a", expected: []string{"a.link active", "a.link", "a"}},
+		{selector: `[href="/about"]`, expected: []string{"a.link"}},
+		{selector: "[type=text]", expected: []string{"input"}},
+		{selector: "[disabled]", expected: []string{"button"}},
+		{selector: "[href]", expected: []string{"a.link active", "a.link", "a"}},
+		{selector: "button[type='submit'][disabled]", expected: []string{"button"}},
+		{selector: "main .note", expected: []string{"p.note"}},
+		{selector: ".note", expected: []string{"p.note", "p.note"}},
+		{selector: "#main form input", expected: []string{"input"}},
+		{selector: "nav input", expected: nil},
+		{selector: "* > a", expected: nil},
+		{selector: "", expected: nil},
+		{selector: "[unterminated", expected: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.selector, func(t *testing.T) {
+			var actual []string
+			for _, e := range Query(tem.Children, tt.selector) {
+				name := e.Name
+				if classes := e.ClassList(); classes != nil {
+					name += "." + strings.Join(classes, " ")
+				}
+				actual = append(actual, name)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
 )
 
 var styleElement = rawElementParser{
@@ -26,9 +28,9 @@ func (p rawElementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 		return
 	}
 
-	// Element name.
+	// Element name, which isn't case sensitive, e.g. <SCRIPT>.
 	var e RawElement
-	if e.Name, ok, err = parse.String(p.name).Parse(pi); err != nil || !ok {
+	if e.Name, ok, err = parse.StringInsensitive(p.name).Parse(pi); err != nil || !ok {
 		pi.Seek(start)
 		return
 	}
@@ -37,6 +39,9 @@ func (p rawElementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 		pi.Seek(start)
 		return
 	}
+	if strings.EqualFold(e.Name, "script") {
+		e.Attributes, e.Interpolate = removeInterpolateAttribute(e.Attributes)
+	}
 
 	// Optional whitespace.
 	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
@@ -52,13 +57,95 @@ func (p rawElementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 
 	// Once we've got an open tag, parse anything until the end tag as the tag contents.
 	// It's going to be rendered out raw.
-	end := parse.All(parse.String("</"), parse.String(p.name), parse.String(">"))
+	contentsStart := pi.Index()
+	end := parse.All(parse.String("</"), parse.StringInsensitive(p.name), parse.String(">"))
 	if e.Contents, ok, err = parse.StringUntil(end).Parse(pi); err != nil || !ok {
 		err = parse.Error(fmt.Sprintf("<%s>: expected end tag not present", e.Name), pi.Position())
 		return
 	}
+	if e.Interpolate && strings.Contains(e.Contents, "{{") {
+		contentsEnd := pi.Index()
+		pi.Seek(contentsStart)
+		if e.Parts, err = scriptParts(pi, contentsEnd); err != nil {
+			return e, false, err
+		}
+	}
 	// Cut the end element.
 	_, _, _ = end.Parse(pi)
+	e.Range = sourceRange(pi, start)
 
 	return e, true, nil
 }
+
+// interpolateAttribute opts in to Go expressions within a <script> element, e.g.
+// `<script templ:interpolate>var x = {{ data }};</script>`. Without it, `{{` is text, since
+// scripts often contain it, e.g. in Vue or Handlebars templates.
+const interpolateAttribute = "templ:interpolate"
+
+// removeInterpolateAttribute returns the attributes without the interpolate attribute, and
+// whether it was present.
+func removeInterpolateAttribute(attrs []Attribute) (op []Attribute, interpolate bool) {
+	for _, attr := range attrs {
+		if bca, ok := attr.(BoolConstantAttribute); ok && strings.EqualFold(bca.Name, interpolateAttribute) {
+			interpolate = true
+			continue
+		}
+		op = append(op, attr)
+	}
+	if !interpolate {
+		return attrs, false
+	}
+	return op, true
+}
+
+// scriptParts splits the contents of a <script> element, up to the end index, into the text,
+// and the Go expressions within `{{ expr }}`. A single brace, e.g. of an object literal, is text,
+// and `\{{` is written as a literal `{{`.
+func scriptParts(pi *parse.Input, end int) (parts []Node, err error) {
+	for {
+		src, _ := pi.Peek(end - pi.Index())
+		i := strings.Index(src, "{{")
+		if i < 0 {
+			break
+		}
+		if i > 0 && src[i-1] == '\\' {
+			parts = appendScriptText(parts, src[:i-1]+"{{")
+			pi.Take(i + len("{{"))
+			continue
+		}
+		if i > 0 {
+			parts = appendScriptText(parts, src[:i])
+		}
+		start := pi.PositionAt(pi.Index() + i)
+		pi.Take(i + len("{{"))
+		_, _, _ = parse.OptionalWhitespace.Parse(pi)
+		var r StringExpression
+		if r.Expression, err = parseGo("script interpolation", pi, goexpression.Expression); err != nil {
+			return nil, err
+		}
+		_, _, _ = parse.OptionalWhitespace.Parse(pi)
+		if !peekPrefix(pi, "}}") || pi.Index() > end-len("}}") {
+			return nil, parse.Error("<script>: missing closing braces '}}' for the expression", start)
+		}
+		pi.Take(len("}}"))
+		parts = append(parts, r)
+	}
+	if src, _ := pi.Peek(end - pi.Index()); src != "" {
+		parts = appendScriptText(parts, src)
+		pi.Take(len(src))
+	}
+	return parts, nil
+}
+
+// appendScriptText adds the text to the parts, joining it to the text before it, e.g. after an
+// escaped `\{{`.
+func appendScriptText(parts []Node, text string) []Node {
+	if len(parts) > 0 {
+		if t, ok := parts[len(parts)-1].(Text); ok {
+			t.Value += text
+			parts[len(parts)-1] = t
+			return parts
+		}
+	}
+	return append(parts, Text{Value: text})
+}
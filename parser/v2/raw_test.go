@@ -1,10 +1,12 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/a-h/parse"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 var ignoredContent = `{
@@ -27,9 +29,33 @@ func TestRawElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "type",
 						Value: "text/css",
+						Range: Range{
+							From: Position{
+								Index: 7,
+								Line:  0,
+								Col:   7,
+							},
+							To: Position{
+								Index: 22,
+								Line:  0,
+								Col:   22,
+							},
+						},
 					},
 				},
 				Contents: "contents",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 39,
+						Line:  0,
+						Col:   39,
+					},
+				},
 			},
 		},
 		{
@@ -41,9 +67,33 @@ func TestRawElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "type",
 						Value: "text/css",
+						Range: Range{
+							From: Position{
+								Index: 7,
+								Line:  0,
+								Col:   7,
+							},
+							To: Position{
+								Index: 22,
+								Line:  0,
+								Col:   22,
+							},
+						},
 					},
 				},
 				Contents: ignoredContent,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 52,
+						Line:  3,
+						Col:   9,
+					},
+				},
 			},
 		},
 		{
@@ -55,9 +105,33 @@ func TestRawElementParser(t *testing.T) {
 					ConstantAttribute{
 						Name:  "type",
 						Value: "vbscript",
+						Range: Range{
+							From: Position{
+								Index: 8,
+								Line:  0,
+								Col:   8,
+							},
+							To: Position{
+								Index: 23,
+								Line:  0,
+								Col:   23,
+							},
+						},
 					},
 				},
 				Contents: "dim x = 1",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 42,
+						Line:  0,
+						Col:   42,
+					},
+				},
 			},
 		},
 	}
@@ -78,3 +152,147 @@ func TestRawElementParser(t *testing.T) {
 		})
 	}
 }
+
+func TestRawElementParserScriptInterpolation(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []Node
+	}{
+		{
+			name:  "an interpolated expression and a literal brace",
+			input: `<script templ:interpolate>var x = {{ data }}; var y = { a: 1 };</script>`,
+			expected: []Node{
+				Text{Value: "var x = "},
+				StringExpression{
+					Expression: Expression{
+						Value: "data",
+						Range: Range{
+							From: Position{Index: 37, Line: 0, Col: 37},
+							To:   Position{Index: 41, Line: 0, Col: 41},
+						},
+					},
+				},
+				Text{Value: "; var y = { a: 1 };"},
+			},
+		},
+		{
+			name:  "expressions containing braces",
+			input: `<script templ:interpolate>{{ map[string]any{"a": struct{}{}} }}{{x}}</script>`,
+			expected: []Node{
+				StringExpression{
+					Expression: Expression{
+						Value: `map[string]any{"a": struct{}{}}`,
+						Range: Range{
+							From: Position{Index: 29, Line: 0, Col: 29},
+							To:   Position{Index: 60, Line: 0, Col: 60},
+						},
+					},
+				},
+				StringExpression{
+					Expression: Expression{
+						Value: "x",
+						Range: Range{
+							From: Position{Index: 65, Line: 0, Col: 65},
+							To:   Position{Index: 66, Line: 0, Col: 66},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "escaped braces are literal",
+			input: `<script templ:interpolate>var t = "\{{ name }}"; var x = {{ x }};</script>`,
+			expected: []Node{
+				Text{Value: `var t = "{{ name }}"; var x = `},
+				StringExpression{
+					Expression: Expression{
+						Value: "x",
+						Range: Range{
+							From: Position{Index: 60, Line: 0, Col: 60},
+							To:   Position{Index: 61, Line: 0, Col: 61},
+						},
+					},
+				},
+				Text{Value: ";"},
+			},
+		},
+		{
+			name:     "interpolating scripts without expressions have no parts",
+			input:    `<script templ:interpolate>function f() { return { a: 1 }; }</script>`,
+			expected: nil,
+		},
+		{
+			name:     "scripts are not interpolated by default",
+			input:    `<script>var x = {{ data }};</script>`,
+			expected: nil,
+		},
+		{
+			name:     "Vue templates within scripts are unchanged",
+			input:    `<script>new Vue({ template: '<p>{{ message }}</p>' })</script>`,
+			expected: nil,
+		},
+		{
+			name:     "Handlebars strings within scripts are unchanged",
+			input:    `<script>var s = "{{#each items}}";</script>`,
+			expected: nil,
+		},
+		{
+			name:     "unclosed braces within scripts are unchanged",
+			input:    "<script>var a = {{\n}</script>",
+			expected: nil,
+		},
+		{
+			name:     "styles are not interpolated",
+			input:    `<style templ:interpolate>{{ name }}</style>`,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := rawElements.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			e := actual.(RawElement)
+			if diff := cmp.Diff(tt.expected, e.Parts); diff != "" {
+				t.Error(diff)
+			}
+			if !strings.HasSuffix(tt.input, ">"+e.Contents+"</"+e.Name+">") {
+				t.Errorf("expected the contents to be kept verbatim, got %q", e.Contents)
+			}
+			w := new(strings.Builder)
+			if err = e.Write(w, 0); err != nil {
+				t.Fatalf("failed to write: %v", err)
+			}
+			if diff := cmp.Diff(tt.input, w.String()); diff != "" {
+				t.Errorf("expected the element to be written as it was parsed:\n%s", diff)
+			}
+		})
+	}
+	t.Run("the interpolate attribute isn't kept with the other attributes", func(t *testing.T) {
+		actual, _, err := rawElements.Parse(parse.NewInput(`<script type="module" templ:interpolate>{{ x }}</script>`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		e := actual.(RawElement)
+		if !e.Interpolate {
+			t.Error("expected Interpolate to be set")
+		}
+		if diff := cmp.Diff([]Attribute{ConstantAttribute{Name: "type", Value: "module"}}, e.Attributes, cmpopts.IgnoreTypes(Range{})); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("missing closing braces are an error", func(t *testing.T) {
+		_, _, err := rawElements.Parse(parse.NewInput(`<script templ:interpolate>var x = {{ a; </script>`))
+		expected := parse.Error("<script>: missing closing braces '}}' for the expression", parse.Position{Index: 34, Line: 0, Col: 34})
+		if diff := cmp.Diff(expected, err); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
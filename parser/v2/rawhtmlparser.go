@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+var rawHTMLExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+
+	// Check the prefix first.
+	if !peekPrefix(pi, "@raw {", "@raw{") {
+		return n, false, nil
+	}
+	pi.Take(len("@raw"))
+	src, _ := pi.Peek(-1)
+	pi.Take(strings.Index(src, "{") + 1)
+
+	// Read the contents up to the matching closing brace, without parsing them.
+	var r RawHTML
+	src, _ = pi.Peek(-1)
+	end := matchingCloseBrace(src)
+	if end < 0 {
+		return r, false, parse.Error("@raw: "+unterminatedMissingEnd, pi.PositionAt(start))
+	}
+	r.Value = src[:end]
+	pi.Take(end + 1)
+
+	return r, true, nil
+})
+
+// matchingCloseBrace returns the index of the closing brace that matches an opening brace
+// before the start of s, or -1 if there isn't one.
+func matchingCloseBrace(s string) int {
+	depth := 1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRawHTMLExpressionParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected RawHTML
+	}{
+		{
+			name:     "raw: elements are not parsed",
+			input:    `@raw {<div class="a">Hello</div>}`,
+			expected: RawHTML{Value: `<div class="a">Hello</div>`},
+		},
+		{
+			name:     "raw: without a space before the brace",
+			input:    `@raw{<br>}`,
+			expected: RawHTML{Value: `<br>`},
+		},
+		{
+			name:     "raw: unbalanced tags and entities are kept",
+			input:    `@raw { <p>a &lt; b > c }`,
+			expected: RawHTML{Value: ` <p>a &lt; b > c `},
+		},
+		{
+			name:     "raw: nested braces are matched",
+			input:    `@raw {<script>function f() { return {}; }</script>}`,
+			expected: RawHTML{Value: `<script>function f() { return {}; }</script>`},
+		},
+		{
+			name:     "raw: multiline",
+			input:    "@raw {\n\t<ul>\n\t\t<li>{ x }</li>\n\t</ul>\n}",
+			expected: RawHTML{Value: "\n\t<ul>\n\t\t<li>{ x }</li>\n\t</ul>\n"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := rawHTMLExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if _, ok := input.Peek(1); ok {
+				t.Errorf("expected all input to be consumed")
+			}
+		})
+	}
+}
+
+func TestRawHTMLExpressionParserErrors(t *testing.T) {
+	_, _, err := rawHTMLExpression.Parse(parse.NewInput(`@raw { <div>{ </div> }`))
+	if err == nil {
+		t.Error("expected an error for an unterminated raw block")
+	}
+}
+
+func TestRawHTMLExpressionParserDoesNotMatchTemplates(t *testing.T) {
+	input := parse.NewInput(`@rawHTML()`)
+	_, ok, err := rawHTMLExpression.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected no match")
+	}
+}
+
+func TestRawHTMLIsRenderedVerbatim(t *testing.T) {
+	input := `templ x() {
+	<div>
+		@raw {<b>a & b</b><i>}
+	</div>
+}`
+	tem, ok, err := template.Parse(parse.NewInput(input))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	if elements := Query(tem.Children, "b"); len(elements) != 0 {
+		t.Errorf("expected the contents of the raw block not to be parsed as elements, got %d", len(elements))
+	}
+	var sb strings.Builder
+	if err = Render(&sb, tem.Children); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if diff := cmp.Diff(`<div> <b>a & b</b><i> </div>`, strings.TrimSpace(sb.String())); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+// ParseError is an error found by ParseWithRecovery.
+type ParseError struct {
+	Message string
+	// Range of the source that couldn't be parsed, from the start of the node that contains
+	// the error.
+	Range Range
+}
+
+func (e ParseError) Error() string {
+	return e.Message + ": " + e.Range.From.String()
+}
+
+// ContextString returns the error, followed by the line of the source that the error starts
+// on, and a caret that points to the column, e.g.:
+//
+//	template: missing closing brace: line 2, col 1
+//		<div>
+//		^
+//
+// Tabs before the column are kept, so that the caret lines up with the source.
+func (e ParseError) ContextString(source string) string {
+	lines := strings.Split(source, "\n")
+	if int(e.Range.From.Line) >= len(lines) {
+		return e.Error()
+	}
+	line := strings.TrimSuffix(lines[e.Range.From.Line], "\r")
+	col := int(e.Range.From.Col)
+	if col > len(line) {
+		col = len(line)
+	}
+	var caret strings.Builder
+	for _, r := range line[:col] {
+		if r == '\t' {
+			caret.WriteRune('\t')
+			continue
+		}
+		caret.WriteRune(' ')
+	}
+	caret.WriteRune('^')
+	return e.Error() + "\n" + line + "\n" + caret.String()
+}
+
+// ParseWithRecovery parses a single `templ` template, like the template parser, but carries on
+// past errors, e.g. for editor diagnostics. It returns the nodes that could be parsed, and an
+// error for each node that couldn't.
+//
+// When a node can't be parsed, the rest of the line it starts on is skipped, and parsing
+// carries on with the next line, so the contents of an element that's missing its end tag are
+// parsed as if they were siblings of the element.
+func ParseWithRecovery(input string) (t HTMLTemplate, errs []ParseError) {
+	pi := parse.NewInput(input)
+	addError := func(err error, from, to parse.Position) {
+		msg := err.Error()
+		var pe parse.ParseError
+		if errors.As(err, &pe) {
+			msg = pe.Msg
+			if pe.Pos.Index > from.Index {
+				to = pe.Pos
+			}
+		}
+		errs = append(errs, ParseError{Message: msg, Range: NewRange(from, to)})
+	}
+
+	// templ FuncName(p Person, other Other) {
+	start := pi.Position()
+	te, ok, err := templateExpressionParser.Parse(pi)
+	if err != nil || !ok {
+		if err == nil {
+			err = errors.New("templ: expected `templ functionName() {`")
+		}
+		addError(err, start, pi.Position())
+		return t, errs
+	}
+	t.Expression = te.Expression
+
+	var ignoreNext bool
+	for {
+		// Try for }
+		start = pi.Position()
+		if _, ok, _ = closeBraceWithOptionalPadding.Parse(pi); ok {
+			t.Children = trimWhitespace(t.Children)
+			return t, errs
+		}
+		if _, isEOF, _ := parse.EOF[string]().Parse(pi); isEOF {
+			addError(errors.New("template: missing closing brace"), start, start)
+			t.Children = trimWhitespace(t.Children)
+			return t, errs
+		}
+
+		var node Node
+		var matched bool
+		node, matched, err = parseTemplateNode(pi)
+		if err == nil && matched {
+			t.Diagnostics = append(t.Diagnostics, nodeDiagnostics(node)...)
+			node, ignoreNext = applyIgnoreDirective(node, ignoreNext)
+			t.Children = append(t.Children, node)
+			continue
+		}
+		if err == nil {
+			err = errors.New("template: unexpected content, expected a node or the template closing brace")
+		}
+
+		// Skip to the start of the next line, and carry on.
+		pi.Seek(start.Index)
+		_, _, _ = stringUntilNewLineOrEOF.Parse(pi)
+		addError(err, start, pi.Position())
+		_, _, _ = parse.NewLine.Parse(pi)
+	}
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseWithRecovery(t *testing.T) {
+	input := `templ x() {
+	<p>before</p>
+	<a href="/>
+	<p>between</p>
+	<div>
+		<span>after</span>
+}`
+	tem, errs := ParseWithRecovery(input)
+	expectedErrs := []ParseError{
+		{
+			Message: "<a>: malformed open element",
+			Range: Range{
+				From: Position{Index: 28, Line: 2, Col: 1},
+				To:   Position{Index: 31, Line: 2, Col: 4},
+			},
+		},
+		{
+			Message: "<div>: expected end tag '</div>' not present or invalid tag contents",
+			Range: Range{
+				From: Position{Index: 57, Line: 4, Col: 1},
+				To:   Position{Index: 84, Line: 6, Col: 0},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedErrs, errs); diff != "" {
+		t.Errorf("unexpected errors:\n%s", diff)
+	}
+	var elements []string
+	Walk(tem.Children, func(n Node) bool {
+		if e, ok := n.(Element); ok {
+			elements = append(elements, e.Name)
+		}
+		return true
+	})
+	if diff := cmp.Diff([]string{"p", "p", "span"}, elements); diff != "" {
+		t.Errorf("unexpected elements:\n%s", diff)
+	}
+}
+
+func TestParseWithRecoveryValidTemplate(t *testing.T) {
+	input := `templ x() {
+	<p>Hello</p>
+}`
+	tem, errs := ParseWithRecovery(input)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	if diff := cmp.Diff("x()", tem.Expression.Value); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParseErrorContextString(t *testing.T) {
+	source := "templ x() {\n\t\t<a href=\"/>\n\t<p>é</p>\r\n}"
+	var tests = []struct {
+		name     string
+		from     Position
+		expected string
+	}{
+		{
+			name:     "the caret lines up with the column of a tab indented line",
+			from:     Position{Index: 17, Line: 1, Col: 5},
+			expected: "msg: line 1, col 5 (index 17)\n\t\t<a href=\"/>\n\t\t   ^",
+		},
+		{
+			name:     "multi-byte characters take up one column",
+			from:     Position{Index: 31, Line: 2, Col: 6},
+			expected: "msg: line 2, col 6 (index 31)\n\t<p>é</p>\n\t    ^",
+		},
+		{
+			name:     "start of a line",
+			from:     Position{Index: 0, Line: 0, Col: 0},
+			expected: "msg: line 0, col 0 (index 0)\ntempl x() {\n^",
+		},
+		{
+			name:     "lines outside of the source only return the error",
+			from:     Position{Index: 100, Line: 10, Col: 0},
+			expected: "msg: line 10, col 0 (index 100)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			e := ParseError{Message: "msg", Range: Range{From: tt.from, To: tt.from}}
+			if diff := cmp.Diff(tt.expected, e.ContextString(source)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseWithRecoveryContextString(t *testing.T) {
+	input := "templ x() {\n\t\t<a href=\"/>\n}"
+	_, errs := ParseWithRecovery(input)
+	if len(errs) == 0 {
+		t.Fatal("expected an error")
+	}
+	expected := "<a>: malformed open element: line 1, col 2 (index 14)\n\t\t<a href=\"/>\n\t\t^"
+	if diff := cmp.Diff(expected, errs[0].ContextString(input)); diff != "" {
+		t.Error(diff)
+	}
+}
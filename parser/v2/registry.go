@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/a-h/parse"
+)
+
+type registeredNodeParser struct {
+	name   string
+	parser parse.Parser[Node]
+}
+
+var registeredNodeParsers []registeredNodeParser
+
+// RegisterNodeParser adds a parser for custom nodes within templates, to support syntax
+// that isn't built in to templ, e.g. a `<chart>` directive.
+//
+// Registered parsers are tried in the order they were registered, before the built-in
+// parsers, so they can take precedence over built-in syntax such as elements. A parser
+// must return ok as false without consuming input if it doesn't match.
+//
+// Nodes returned by custom parsers can't be generated into Go code by templ, so they
+// must be transformed into built-in nodes before generation.
+//
+// RegisterNodeParser is not safe for concurrent use with parsing, and should be called
+// during program initialisation. It panics if a parser with the same name has already
+// been registered.
+func RegisterNodeParser(name string, p parse.Parser[Node]) {
+	for _, rp := range registeredNodeParsers {
+		if rp.name == name {
+			panic(fmt.Sprintf("parser: node parser %q is already registered", name))
+		}
+	}
+	registeredNodeParsers = append(registeredNodeParsers, registeredNodeParser{name: name, parser: p})
+	parsers := make([]parse.Parser[Node], 0, len(registeredNodeParsers)+len(builtInNodeParsers))
+	for _, rp := range registeredNodeParsers {
+		parsers = append(parsers, rp.parser)
+	}
+	templateNodeParsers = append(parsers, builtInNodeParsers...)
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"io"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+type chartNode struct {
+	Kind string
+}
+
+func (c chartNode) IsNode() bool { return true }
+func (c chartNode) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "<chart ", c.Kind, "/>")
+}
+
+var chartParser = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	if _, ok, err = parse.String("<chart ").Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return n, false, err
+	}
+	var c chartNode
+	if c.Kind, ok, err = parse.StringUntil(parse.String("/>")).Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return n, false, err
+	}
+	pi.Take(len("/>"))
+	return c, true, nil
+})
+
+func TestRegisterNodeParser(t *testing.T) {
+	t.Cleanup(func() {
+		registeredNodeParsers = nil
+		templateNodeParsers = builtInNodeParsers
+	})
+	RegisterNodeParser("chart", chartParser)
+
+	input := parse.NewInput(`templ Name() {
+	<div><chart bar/></div>
+}`)
+	actual, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("unexpected failure to parse")
+	}
+	expected := []Node{
+		Whitespace{Value: "\t"},
+		Element{
+			Name: "div",
+			Children: []Node{
+				chartNode{Kind: "bar"},
+			},
+			TrailingSpace: SpaceVertical,
+			Range: Range{
+				From: Position{Index: 16, Line: 1, Col: 1},
+				To:   Position{Index: 39, Line: 1, Col: 24},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, actual.Children); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("registering a duplicate name panics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		RegisterNodeParser("chart", chartParser)
+	})
+}
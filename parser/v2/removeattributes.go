@@ -0,0 +1,62 @@
+package parser
+
+import "strings"
+
+// RemoveAttributes returns a copy of the template with the named attributes removed from
+// every element, and the number of attributes that were removed. Names are compared
+// case-insensitively, and a name ending with `*` matches any attribute with that prefix,
+// e.g. `on*` matches `onclick`. Constant, boolean and expression attributes are removed,
+// including those within conditional attributes.
+func RemoveAttributes(t HTMLTemplate, names ...string) (HTMLTemplate, int) {
+	var removed int
+	t.Children = mapElements(t.Children, func(e Element) Element {
+		var n int
+		e.Attributes, n = removeAttributes(e.Attributes, names)
+		removed += n
+		return e
+	})
+	return t, removed
+}
+
+func removeAttributes(attrs []Attribute, names []string) (op []Attribute, removed int) {
+	if attrs == nil {
+		return nil, 0
+	}
+	op = make([]Attribute, 0, len(attrs))
+	for _, a := range attrs {
+		if ca, ok := a.(ConditionalAttribute); ok {
+			var thenRemoved, elseRemoved int
+			ca.Then, thenRemoved = removeAttributes(ca.Then, names)
+			ca.Else, elseRemoved = removeAttributes(ca.Else, names)
+			removed += thenRemoved + elseRemoved
+			op = append(op, ca)
+			continue
+		}
+		if attributeNameMatches(attributeName(a), names) {
+			removed++
+			continue
+		}
+		op = append(op, a)
+	}
+	return op, removed
+}
+
+func attributeNameMatches(name string, patterns []string) bool {
+	if name == "" {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if prefix, isPrefix := strings.CutSuffix(p, "*"); isPrefix {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+			continue
+		}
+		if name == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRemoveAttributes(t *testing.T) {
+	input := parse.NewInput(`templ Name(handler string, disabled bool) {
+	<div class="card" onClick="alert(1)">
+		<button type="button" onmouseover={ handler } onfocus if disabled { disabled }>Go</button>
+	</div>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+
+	actual, removed := RemoveAttributes(tem, "on*")
+	if removed != 3 {
+		t.Errorf("expected 3 attributes to be removed, got %d", removed)
+	}
+
+	div := firstElement(t, actual.Children)
+	expectedDivAttributes := []Attribute{
+		ConstantAttribute{Name: "class", Value: "card"},
+	}
+	if diff := cmp.Diff(expectedDivAttributes, div.Attributes, cmpopts.IgnoreTypes(Range{})); diff != "" {
+		t.Error(diff)
+	}
+	button := firstElement(t, div.Children)
+	expectedButtonAttributes := []Attribute{
+		ConstantAttribute{Name: "type", Value: "button"},
+		ConditionalAttribute{
+			Expression: Expression{Value: "disabled"},
+			Then:       []Attribute{BoolConstantAttribute{Name: "disabled"}},
+		},
+	}
+	if diff := cmp.Diff(expectedButtonAttributes, button.Attributes, cmpopts.IgnoreTypes(Range{})); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRemoveAttributesByName(t *testing.T) {
+	tem := HTMLTemplate{
+		Children: []Node{
+			Element{
+				Name: "p",
+				Attributes: []Attribute{
+					ConstantAttribute{Name: "STYLE", Value: "color: red"},
+					ConstantAttribute{Name: "stylesheet", Value: "a"},
+				},
+			},
+		},
+	}
+	actual, removed := RemoveAttributes(tem, "style")
+	if removed != 1 {
+		t.Errorf("expected 1 attribute to be removed, got %d", removed)
+	}
+	expected := []Attribute{ConstantAttribute{Name: "stylesheet", Value: "a"}}
+	if diff := cmp.Diff(expected, actual.Children[0].(Element).Attributes); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// RenderOptions customise the output of RenderWithOptions.
+type RenderOptions struct {
+	// Placeholder writes the output of nodes that can't be rendered statically, e.g. string
+	// expressions, or if expressions. If Placeholder is nil, an error is returned instead.
+	Placeholder func(w io.Writer, n Node) error
+	// SourceMap, if set, records the node that each part of the output was rendered from, see
+	// RenderWithSourceMap.
+	SourceMap *RenderSourceMap
+	// BlockNewlines pretty-prints the output, for reading, e.g. when debugging. Block elements,
+	// e.g. <div>, doctypes, comments, and <script> and <style> elements start on a new line, and
+	// the nodes within block elements are indented. The contents of whitespace sensitive
+	// elements, e.g. <pre>, are written as they are.
+	BlockNewlines bool
+	// Indent is written at the start of each line for each level of nesting when BlockNewlines
+	// is set, e.g. "\t" or "  ".
+	Indent string
+	// InlineOnOneLine keeps inline elements, e.g. <span>, and text, on the same line as the
+	// nodes before them when BlockNewlines is set, and writes block elements that only contain
+	// inline nodes on one line. Otherwise, every node starts on a new line.
+	InlineOnOneLine bool
+}
+
+// Render writes the nodes to w as HTML. Only static nodes can be rendered, i.e. elements
+// with constant attributes, text, whitespace, doctypes and comments, so an error is returned
+// for expressions. Use Flatten first to render template output for some data.
+//
+// The output matches the generated code, so text is written as-is, since it's already HTML
+// encoded, unless its entities were decoded when it was parsed, and whitespace is collapsed to
+// a single space.
+func Render(w io.Writer, nodes []Node) error {
+	return RenderWithOptions(w, nodes, RenderOptions{})
+}
+
+// RenderWithOptions writes the nodes to w as HTML, see Render.
+func RenderWithOptions(w io.Writer, nodes []Node, opts RenderOptions) error {
+	r := renderer{w: w, opts: opts}
+	if opts.BlockNewlines {
+		r.pretty = &prettyPrinter{}
+	}
+	if opts.SourceMap != nil {
+		r.output = &countingWriter{w: w}
+		r.w = r.output
+	}
+	return r.nodes(nodes)
+}
+
+type renderer struct {
+	w    io.Writer
+	opts RenderOptions
+	// output counts the bytes written, when there's a source map.
+	output *countingWriter
+	// pretty lays out the nodes when BlockNewlines is set. It's nil within whitespace sensitive
+	// elements, and within nodes that are written on one line.
+	pretty *prettyPrinter
+}
+
+// prettyPrinter is the layout state of a list of nodes.
+type prettyPrinter struct {
+	// depth is the number of block elements that the nodes are within.
+	depth int
+	// started is set once anything has been written, so that the first line isn't blank.
+	started bool
+	// newline is set when the next node starts on a new line, e.g. after a block element.
+	newline bool
+	// space is set when there was whitespace before the next node.
+	space bool
+}
+
+func (r renderer) nodes(nodes []Node) error {
+	for _, n := range nodes {
+		if r.pretty != nil {
+			if ws, ok := n.(Whitespace); ok {
+				r.pretty.space = r.pretty.space || ws.Value != ""
+				continue
+			}
+			if err := r.layout(n); err != nil {
+				return err
+			}
+		}
+		if r.output == nil {
+			if err := r.node(n); err != nil {
+				return err
+			}
+			continue
+		}
+		start := r.output.n
+		if err := r.node(n); err != nil {
+			return err
+		}
+		r.opts.SourceMap.add(n, start, r.output.n)
+	}
+	return nil
+}
+
+func (r renderer) node(node Node) (err error) {
+	switch n := node.(type) {
+	case Element:
+		if n.Ignored {
+			return nil
+		}
+		if err = r.element(n); err != nil {
+			return err
+		}
+		return r.trailingSpace(n.TrailingSpace)
+	case RawElement:
+		if err = r.startTag(n.Name, n.Attributes); err != nil {
+			return err
+		}
+		if n.Parts == nil {
+			return r.write(n.Contents, "</", html.EscapeString(n.Name), ">")
+		}
+		if err = r.nodes(n.Parts); err != nil {
+			return err
+		}
+		return r.write("</", html.EscapeString(n.Name), ">")
+	case Text:
+		if err = r.write(n.HTML()); err != nil {
+			return err
+		}
+		return r.trailingSpace(n.TrailingSpace)
+	case Whitespace:
+		if n.Value == "" {
+			return nil
+		}
+		return r.write(" ")
+	case DocType:
+		return r.write("<!doctype ", n.Value, ">")
+	case HTMLComment:
+		if n.IsIgnoreDirective() {
+			return nil
+		}
+		return r.write("<!--", n.Contents, "-->")
+	case CData:
+		return r.write("<![CDATA[", n.Value, "]]>")
+	case RawHTML:
+		return r.write(n.Value)
+	case GoComment:
+		// Go comments aren't rendered.
+		return nil
+	}
+	if r.opts.Placeholder != nil {
+		return r.opts.Placeholder(r.w, node)
+	}
+	return fmt.Errorf("render: %T can't be rendered statically", node)
+}
+
+func (r renderer) element(e Element) (err error) {
+	if err = r.startTag(e.Name, e.Attributes); err != nil {
+		return err
+	}
+	if e.IsVoidElement() {
+		return nil
+	}
+	if r.pretty != nil {
+		return r.prettyChildren(e)
+	}
+	if err = r.nodes(e.Children); err != nil {
+		return err
+	}
+	return r.write("</", html.EscapeString(e.Name), ">")
+}
+
+// layout writes the newline and indent, or space, before a node, when BlockNewlines is set.
+func (r renderer) layout(n Node) error {
+	if rendersNothing(n) {
+		return nil
+	}
+	p := r.pretty
+	block := !r.opts.InlineOnOneLine || isPrettyBlock(n)
+	var err error
+	switch {
+	case (block || p.newline) && p.started:
+		err = r.newline(p.depth)
+	case p.space && p.started:
+		err = r.write(" ")
+	}
+	p.started, p.newline, p.space = true, block, false
+	return err
+}
+
+// prettyChildren writes the children and end tag of an element when BlockNewlines is set.
+func (r renderer) prettyChildren(e Element) (err error) {
+	end := func() error {
+		return r.write("</", html.EscapeString(e.Name), ">")
+	}
+	if !(Element{Children: e.Children}).hasNonWhitespaceChildren() {
+		return end()
+	}
+	oneLine := r.opts.InlineOnOneLine && (!e.IsBlockElement() || !containsPrettyBlock(e.Children))
+	if e.PreserveWhitespace || isWhitespaceSensitive(e.Name) || oneLine {
+		compact := r
+		compact.pretty = nil
+		if err = compact.nodes(e.Children); err != nil {
+			return err
+		}
+		return end()
+	}
+	children := r
+	children.pretty = &prettyPrinter{depth: r.pretty.depth + 1, started: true, newline: true}
+	if err = children.nodes(e.Children); err != nil {
+		return err
+	}
+	if err = r.newline(r.pretty.depth); err != nil {
+		return err
+	}
+	return end()
+}
+
+func (r renderer) newline(depth int) error {
+	return r.write("\n", strings.Repeat(r.opts.Indent, depth))
+}
+
+// isPrettyBlock returns true if the node starts on a new line when BlockNewlines is set.
+func isPrettyBlock(n Node) bool {
+	switch n := n.(type) {
+	case Element:
+		return n.IsBlockElement()
+	case RawElement, DocType, HTMLComment, CData:
+		return true
+	}
+	return false
+}
+
+func containsPrettyBlock(nodes []Node) bool {
+	for _, n := range nodes {
+		if isPrettyBlock(n) && !rendersNothing(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// rendersNothing returns true for the nodes that render nothing, so that they don't affect the
+// layout.
+func rendersNothing(n Node) bool {
+	switch n := n.(type) {
+	case Element:
+		return n.Ignored
+	case HTMLComment:
+		return n.IsIgnoreDirective()
+	case GoComment:
+		return true
+	}
+	return false
+}
+
+func (r renderer) startTag(name string, attrs []Attribute) (err error) {
+	if err = r.write("<", html.EscapeString(name)); err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case ConstantAttribute:
+			err = r.write(" ", html.EscapeString(attr.Name), `="`, html.EscapeString(attr.Value), `"`)
+		case BoolConstantAttribute:
+			err = r.write(" ", html.EscapeString(attr.Name))
+		default:
+			err = fmt.Errorf("render: <%s>: %T can't be rendered statically", name, attr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return r.write(">")
+}
+
+func (r renderer) trailingSpace(ts TrailingSpace) error {
+	if ts == SpaceNone {
+		return nil
+	}
+	if r.pretty != nil {
+		r.pretty.space = true
+		return nil
+	}
+	return r.write(" ")
+}
+
+func (r renderer) write(s ...string) error {
+	for _, v := range s {
+		if _, err := io.WriteString(r.w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
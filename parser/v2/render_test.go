@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	prettyInput := `templ x() {
+	<!DOCTYPE html>
+	<html>
+		<body>
+			<div class="card"><p>Hello, <b>World</b>!</p><ul><li>One</li><li><a href="/">Two</a></li></ul></div>
+			<pre>  keep
+  this</pre>
+		</body>
+	</html>
+}`
+	var tests = []struct {
+		name     string
+		input    string
+		opts     RenderOptions
+		expected string
+	}{
+		{
+			name: "nested elements with attributes",
+			input: `templ x() {
+	<div class="card" data-title="Tom &amp; Jerry's" hidden>
+		<p>Hello, <b>World</b>!</p>
+		<br/>
+		<img src="/a.png" alt="a &lt; b"/>
+	</div>
+}`,
+			expected: `<div class="card" data-title="Tom &amp; Jerry&#39;s" hidden> <p>Hello, <b>World</b>!</p> <br> <img src="/a.png" alt="a &lt; b"> </div>`,
+		},
+		{
+			name: "attribute values are escaped",
+			input: `templ x() {
+	<a title='"quoted" <tag>'></a>
+}`,
+			expected: `<a title="&#34;quoted&#34; &lt;tag&gt;"></a>`,
+		},
+		{
+			name: "doctype and comments",
+			input: `templ x() {
+	<!DOCTYPE html>
+	<!-- comment -->
+	// Go comment
+	<html></html>
+}`,
+			expected: `<!doctype html> <!-- comment -->  <html></html>`,
+		},
+		{
+			name: "raw elements",
+			input: `templ x() {
+	<script>const a = "<b>";</script>
+}`,
+			expected: `<script>const a = "<b>";</script>`,
+		},
+		{
+			name: "svg names keep their case and namespace prefixes",
+			input: `templ x() {
+	<svg viewBox="0 0 10 10" xmlns:xlink="http://www.w3.org/1999/xlink"><linearGradient id="g"></linearGradient><use xlink:href="#g"/><svg:rect width="1"/></svg>
+}`,
+			expected: `<svg viewBox="0 0 10 10" xmlns:xlink="http://www.w3.org/1999/xlink"><linearGradient id="g"></linearGradient><use xlink:href="#g"></use><svg:rect width="1"></svg:rect></svg>`,
+		},
+		{
+			name: "placeholders",
+			input: `templ x() {
+	<p>{ name }</p>
+}`,
+			opts: RenderOptions{
+				Placeholder: func(w io.Writer, n Node) error {
+					_, err := fmt.Fprintf(w, "[%T]", n)
+					return err
+				},
+			},
+			expected: `<p>[parser.StringExpression]</p>`,
+		},
+		{
+			name:     "compact output",
+			input:    prettyInput,
+			expected: "<!doctype html> <html> <body> <div class=\"card\"><p>Hello, <b>World</b>!</p><ul><li>One</li><li><a href=\"/\">Two</a></li></ul></div> <pre>  keep\n  this</pre> </body> </html>",
+		},
+		{
+			name:  "indented output",
+			input: prettyInput,
+			opts: RenderOptions{
+				BlockNewlines:   true,
+				Indent:          "  ",
+				InlineOnOneLine: true,
+			},
+			expected: `<!doctype html>
+<html>
+  <body>
+    <div class="card">
+      <p>Hello, <b>World</b>!</p>
+      <ul>
+        <li>One</li>
+        <li><a href="/">Two</a></li>
+      </ul>
+    </div>
+    <pre>  keep
+  this</pre>
+  </body>
+</html>`,
+		},
+		{
+			name:  "every node on a new line",
+			input: prettyInput,
+			opts: RenderOptions{
+				BlockNewlines: true,
+				Indent:        "\t",
+			},
+			expected: "<!doctype html>\n<html>\n\t<body>\n\t\t<div class=\"card\">\n\t\t\t<p>\n\t\t\t\tHello, \n\t\t\t\t<b>\n\t\t\t\t\tWorld\n\t\t\t\t</b>\n\t\t\t\t!\n\t\t\t</p>\n\t\t\t<ul>\n\t\t\t\t<li>\n\t\t\t\t\tOne\n\t\t\t\t</li>\n\t\t\t\t<li>\n\t\t\t\t\t<a href=\"/\">\n\t\t\t\t\t\tTwo\n\t\t\t\t\t</a>\n\t\t\t\t</li>\n\t\t\t</ul>\n\t\t</div>\n\t\t<pre>  keep\n  this</pre>\n\t</body>\n</html>",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tem, ok, err := template.Parse(parse.NewInput(tt.input))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			var sb strings.Builder
+			if err = RenderWithOptions(&sb, tem.Children, tt.opts); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, strings.TrimSpace(sb.String())); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "string expressions",
+			input: `templ x() {
+	<p>{ name }</p>
+}`,
+			expected: "render: parser.StringExpression can't be rendered statically",
+		},
+		{
+			name: "if expressions",
+			input: `templ x() {
+	if ok {
+		<p>OK</p>
+	}
+}`,
+			expected: "render: parser.IfExpression can't be rendered statically",
+		},
+		{
+			name: "expression attributes",
+			input: `templ x() {
+	<a href={ url }></a>
+}`,
+			expected: "render: <a>: parser.ExpressionAttribute can't be rendered statically",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tem, ok, err := template.Parse(parse.NewInput(tt.input))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			err = Render(io.Discard, tem.Children)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if diff := cmp.Diff(tt.expected, err.Error()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
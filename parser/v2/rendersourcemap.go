@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"io"
+)
+
+// RenderWithSourceMap writes the nodes to w as HTML, like Render, and returns a map from each
+// part of the output to the node it was rendered from, e.g. to find the template source of an
+// element that's selected in a browser.
+func RenderWithSourceMap(w io.Writer, nodes []Node) (sm RenderSourceMap, err error) {
+	err = RenderWithOptions(w, nodes, RenderOptions{SourceMap: &sm})
+	return sm, err
+}
+
+// RenderSourceMap maps the output of RenderWithSourceMap to the nodes it was rendered from.
+type RenderSourceMap struct {
+	// Segments of the output, one for each node that was rendered, including the nodes within
+	// other nodes. The nodes within a node are listed before it.
+	Segments []RenderSourceMapSegment
+}
+
+// RenderSourceMapSegment is the part of the output that a node was rendered to.
+type RenderSourceMapSegment struct {
+	// From and To are the byte offsets of the start and end of the output.
+	From, To int
+	Node     Node
+	// Range of the node within the template. It's zero for nodes that weren't parsed, e.g.
+	// those created in Go code.
+	Range Range
+}
+
+func (sm *RenderSourceMap) add(n Node, from, to int) {
+	sm.Segments = append(sm.Segments, RenderSourceMapSegment{From: from, To: to, Node: n, Range: nodeRange(n)})
+}
+
+// Segment returns the innermost segment that contains the output offset.
+func (sm RenderSourceMap) Segment(offset int) (segment RenderSourceMapSegment, ok bool) {
+	for _, s := range sm.Segments {
+		if offset < s.From || offset >= s.To {
+			continue
+		}
+		if !ok || s.To-s.From < segment.To-segment.From {
+			segment, ok = s, true
+		}
+	}
+	return segment, ok
+}
+
+// SourceRange returns the range in the template of the innermost node that contains the output
+// offset, and records its position.
+func (sm RenderSourceMap) SourceRange(offset int) (r Range, ok bool) {
+	var size int
+	for _, s := range sm.Segments {
+		if offset < s.From || offset >= s.To || s.Range == (Range{}) {
+			continue
+		}
+		if !ok || s.To-s.From < size {
+			r, size, ok = s.Range, s.To-s.From, true
+		}
+	}
+	return r, ok
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
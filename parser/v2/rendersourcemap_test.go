@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderWithSourceMap(t *testing.T) {
+	tem, ok, err := template.Parse(parse.NewInput(`templ x() {
+	<div><!-- note --><p>{ name }</p></div>
+}`))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var sm RenderSourceMap
+	w := new(strings.Builder)
+	err = RenderWithOptions(w, tem.Children, RenderOptions{
+		Placeholder: func(w io.Writer, n Node) error {
+			_, err := io.WriteString(w, "Alice")
+			return err
+		},
+		SourceMap: &sm,
+	})
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	output := w.String()
+	if diff := cmp.Diff(` <div><!-- note --><p>Alice</p></div> `, output); diff != "" {
+		t.Fatal(diff)
+	}
+
+	t.Run("an offset within an expression maps to the expression", func(t *testing.T) {
+		r, ok := sm.SourceRange(strings.Index(output, "lice"))
+		if !ok {
+			t.Fatal("expected a source range")
+		}
+		expected := Range{
+			From: Position{Index: 34, Line: 1, Col: 22},
+			To:   Position{Index: 42, Line: 1, Col: 30},
+		}
+		if diff := cmp.Diff(expected, r); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("an offset within a comment maps to the comment", func(t *testing.T) {
+		r, ok := sm.SourceRange(strings.Index(output, "note"))
+		if !ok {
+			t.Fatal("expected a source range")
+		}
+		expected := Range{
+			From: Position{Index: 18, Line: 1, Col: 6},
+			To:   Position{Index: 31, Line: 1, Col: 19},
+		}
+		if diff := cmp.Diff(expected, r); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("an offset within an element maps to the innermost element", func(t *testing.T) {
+		offset := strings.Index(output, "</p>")
+		segment, ok := sm.Segment(offset)
+		if !ok {
+			t.Fatal("expected a segment")
+		}
+		if e, isElement := segment.Node.(Element); !isElement || e.Name != "p" {
+			t.Errorf("expected the <p> element, got %#v", segment.Node)
+		}
+		if diff := cmp.Diff("<p>Alice</p>", output[segment.From:segment.To]); diff != "" {
+			t.Error(diff)
+		}
+		r, ok := sm.SourceRange(offset)
+		if !ok {
+			t.Fatal("expected a source range")
+		}
+		expected := Range{
+			From: Position{Index: 31, Line: 1, Col: 19},
+			To:   Position{Index: 46, Line: 1, Col: 34},
+		}
+		if diff := cmp.Diff(expected, r); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("offsets outside the output aren't mapped", func(t *testing.T) {
+		if _, ok := sm.Segment(len(output)); ok {
+			t.Error("expected no segment")
+		}
+	})
+}
+
+func TestRenderWithSourceMapText(t *testing.T) {
+	tem, ok, err := template.Parse(parse.NewInput(`templ x() {
+	<p>Hello</p>
+}`))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(strings.Builder)
+	sm, err := RenderWithSourceMap(w, tem.Children)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	r, ok := sm.SourceRange(strings.Index(w.String(), "Hello"))
+	if !ok {
+		t.Fatal("expected a source range")
+	}
+	expected := Range{
+		From: Position{Index: 16, Line: 1, Col: 4},
+		To:   Position{Index: 21, Line: 1, Col: 9},
+	}
+	if diff := cmp.Diff(expected, r); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRenderWithSourceMapStatic(t *testing.T) {
+	nodes := []Node{
+		Element{Name: "p", Children: []Node{Text{Value: "Hello"}}},
+	}
+	w := new(strings.Builder)
+	sm, err := RenderWithSourceMap(w, nodes)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	expected := []RenderSourceMapSegment{
+		{From: 3, To: 8, Node: Text{Value: "Hello"}},
+		{From: 0, To: 12, Node: nodes[0]},
+	}
+	if diff := cmp.Diff(expected, sm.Segments); diff != "" {
+		t.Error(diff)
+	}
+}
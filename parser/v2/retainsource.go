@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/a-h/parse"
+)
+
+// sourceSince returns the input from start to the current position.
+func sourceSince(pi *parse.Input, start int) string {
+	end := pi.Index()
+	pi.Seek(start)
+	s, _ := pi.Take(end - start)
+	return s
+}
+
+// sourceRange returns the range of the input from start to the current position, without the
+// leading and trailing whitespace that some parsers consume as part of a node.
+func sourceRange(pi *parse.Input, start int) Range {
+	src := sourceSince(pi, start)
+	from := start + len(src) - len(strings.TrimLeftFunc(src, unicode.IsSpace))
+	to := maxInt(from, start+len(strings.TrimRightFunc(src, unicode.IsSpace)))
+	return NewRange(pi.PositionAt(from), pi.PositionAt(to))
+}
+
+// retainSource sets the Source of the nodes that have one to the text within their Range, see
+// TemplateFileParser.RetainSource.
+func retainSource(nodes []Node, pi *parse.Input) []Node {
+	index := pi.Index()
+	defer pi.Seek(index)
+	return mapNodeLists(nodes, func(nodes []Node) []Node {
+		for i, n := range nodes {
+			nodes[i] = withSource(n, pi)
+		}
+		return nodes
+	})
+}
+
+func withSource(node Node, pi *parse.Input) Node {
+	source := func(r Range) string {
+		pi.Seek(int(r.From.Index))
+		s, _ := pi.Take(int(r.To.Index - r.From.Index))
+		return s
+	}
+	switch n := node.(type) {
+	case Element:
+		n.Source = source(n.Range)
+		return n
+	case RawElement:
+		n.Source = source(n.Range)
+		return n
+	case Text:
+		n.Source = source(n.Range)
+		return n
+	case HTMLComment:
+		n.Source = source(n.Range)
+		return n
+	case StringExpression:
+		n.Source = source(n.Range)
+		return n
+	case IfExpression:
+		n.Source = source(n.Range)
+		return n
+	case ForExpression:
+		n.Source = source(n.Range)
+		return n
+	case SwitchExpression:
+		n.Source = source(n.Range)
+		return n
+	case TemplElementExpression:
+		n.Source = source(n.Range)
+		return n
+	}
+	return node
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+const retainSourceInput = `package main
+
+templ x(items []string) {
+	<ul   class="list" >
+		for _, item := range items {
+			<li>{ item  }</li>
+		}
+	</ul>
+	<!--  comment -->
+	if len(items) == 0 {
+		<p>None &amp;   more</p>
+	}
+	@card() {
+		<br/>
+	}
+}
+`
+
+func TestRetainSource(t *testing.T) {
+	p := NewTemplateFileParser("main")
+	p.RetainSource = true
+	tf, ok, err := p.Parse(parse.NewInput(retainSourceInput))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	ht := tf.Nodes[0].(HTMLTemplate)
+
+	var actual []string
+	Walk(ht.Children, func(n Node) bool {
+		switch n := n.(type) {
+		case Element:
+			actual = append(actual, n.Source)
+		case Text:
+			actual = append(actual, n.Source)
+		case HTMLComment:
+			actual = append(actual, n.Source)
+		case StringExpression:
+			actual = append(actual, n.Source)
+		case IfExpression:
+			actual = append(actual, n.Source)
+		case ForExpression:
+			actual = append(actual, n.Source)
+		case TemplElementExpression:
+			actual = append(actual, n.Source)
+		}
+		return true
+	})
+	expected := []string{
+		"<ul   class=\"list\" >\n\t\tfor _, item := range items {\n\t\t\t<li>{ item  }</li>\n\t\t}\n\t</ul>",
+		"for _, item := range items {\n\t\t\t<li>{ item  }</li>\n\t\t}",
+		"<li>{ item  }</li>",
+		"{ item  }",
+		"<!--  comment -->",
+		"if len(items) == 0 {\n\t\t<p>None &amp;   more</p>\n\t}",
+		"<p>None &amp;   more</p>",
+		"None &amp;   more",
+		"@card() {\n\t\t<br/>\n\t}",
+		"<br/>",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+	for _, s := range actual {
+		if !strings.Contains(retainSourceInput, s) {
+			t.Errorf("expected the source to be a part of the input, got %q", s)
+		}
+	}
+}
+
+func TestRetainSourceIsOffByDefault(t *testing.T) {
+	tf, err := ParseString(retainSourceInput)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	for _, e := range Query(tf.Nodes[0].(HTMLTemplate).Children, "*") {
+		if e.Source != "" {
+			t.Errorf("expected no source for <%s>, got %q", e.Name, e.Source)
+		}
+	}
+}
+
+func TestRetainSourceDiff(t *testing.T) {
+	parseRetained := func(input string) []Node {
+		p := NewTemplateFileParser("main")
+		p.RetainSource = true
+		tf, ok, err := p.Parse(parse.NewInput(input))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		return tf.Nodes[0].(HTMLTemplate).Children
+	}
+	old := parseRetained("package main\n\ntempl x() {\n\t<div class=\"a\"><p>Text</p></div>\n}\n")
+	new := parseRetained("package main\n\ntempl x() {\n\t<div class=\"b\"><p>Text</p></div>\n}\n")
+	expected := []Patch{
+		{Op: PatchSetAttribute, Path: []int{1}, Attribute: ConstantAttribute{Name: "class", Value: "b"}},
+	}
+	if diff := cmp.Diff(expected, Diff(old, new), cmp.Comparer(func(a, b Range) bool { return true })); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,47 @@
+package parser
+
+import "strings"
+
+// RewriteAssetURLs returns a copy of the template where root-relative URLs in constant src,
+// href and srcset attributes are prefixed with prefix, e.g. to serve assets from a CDN.
+// URLs that are absolute, protocol-relative or relative to the current page, and attributes
+// that are set with expressions are left unchanged.
+func RewriteAssetURLs(t HTMLTemplate, prefix string) HTMLTemplate {
+	prefix = strings.TrimSuffix(prefix, "/")
+	t.Children = mapElements(t.Children, func(e Element) Element {
+		e.Attributes = mapAttributes(e.Attributes, func(a Attribute) Attribute {
+			switch a := a.(type) {
+			case ConstantAttribute:
+				switch strings.ToLower(a.Name) {
+				case "src", "href":
+					a.Value = rewriteURL(a.Value, prefix)
+				case "srcset":
+					a.Value = rewriteSrcset(SrcsetAttribute{Candidates: ParseSrcsetCandidates(a.Value)}, prefix).Value()
+				}
+				return a
+			case SrcsetAttribute:
+				return rewriteSrcset(a, prefix)
+			}
+			return a
+		})
+		return e
+	})
+	return t
+}
+
+func rewriteURL(url, prefix string) string {
+	if !strings.HasPrefix(url, "/") || strings.HasPrefix(url, "//") {
+		return url
+	}
+	return prefix + url
+}
+
+func rewriteSrcset(sa SrcsetAttribute, prefix string) SrcsetAttribute {
+	candidates := make([]ImageCandidate, len(sa.Candidates))
+	for i, c := range sa.Candidates {
+		c.URL = rewriteURL(c.URL, prefix)
+		candidates[i] = c
+	}
+	sa.Candidates = candidates
+	return sa
+}
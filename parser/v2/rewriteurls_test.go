@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRewriteAssetURLs(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    Attribute
+		expected Attribute
+	}{
+		{
+			name:     "root-relative src attributes are prefixed",
+			input:    ConstantAttribute{Name: "src", Value: "/assets/logo.png"},
+			expected: ConstantAttribute{Name: "src", Value: "https://cdn.example.com/assets/logo.png"},
+		},
+		{
+			name:     "root-relative href attributes are prefixed",
+			input:    ConstantAttribute{Name: "HREF", Value: "/assets/site.css"},
+			expected: ConstantAttribute{Name: "HREF", Value: "https://cdn.example.com/assets/site.css"},
+		},
+		{
+			name:     "each srcset candidate is prefixed",
+			input:    ConstantAttribute{Name: "srcset", Value: "/assets/a.png 1x, /assets/b.png 2x"},
+			expected: ConstantAttribute{Name: "srcset", Value: "https://cdn.example.com/assets/a.png 1x, https://cdn.example.com/assets/b.png 2x"},
+		},
+		{
+			name: "parsed srcset candidates are prefixed",
+			input: SrcsetAttribute{Candidates: []ImageCandidate{
+				{URL: "/assets/a.png", Descriptor: "300w"},
+				{URL: "https://example.com/b.png", Descriptor: "600w"},
+			}},
+			expected: SrcsetAttribute{Candidates: []ImageCandidate{
+				{URL: "https://cdn.example.com/assets/a.png", Descriptor: "300w"},
+				{URL: "https://example.com/b.png", Descriptor: "600w"},
+			}},
+		},
+		{
+			name:     "absolute URLs are not changed",
+			input:    ConstantAttribute{Name: "src", Value: "https://example.com/logo.png"},
+			expected: ConstantAttribute{Name: "src", Value: "https://example.com/logo.png"},
+		},
+		{
+			name:     "protocol-relative URLs are not changed",
+			input:    ConstantAttribute{Name: "src", Value: "//example.com/logo.png"},
+			expected: ConstantAttribute{Name: "src", Value: "//example.com/logo.png"},
+		},
+		{
+			name:     "relative URLs are not changed",
+			input:    ConstantAttribute{Name: "href", Value: "about"},
+			expected: ConstantAttribute{Name: "href", Value: "about"},
+		},
+		{
+			name:     "expression attributes are not changed",
+			input:    ExpressionAttribute{Name: "src", Expression: Expression{Value: `"/assets/logo.png"`}},
+			expected: ExpressionAttribute{Name: "src", Expression: Expression{Value: `"/assets/logo.png"`}},
+		},
+		{
+			name:     "other attributes are not changed",
+			input:    ConstantAttribute{Name: "data-path", Value: "/assets/logo.png"},
+			expected: ConstantAttribute{Name: "data-path", Value: "/assets/logo.png"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := HTMLTemplate{
+				Children: []Node{
+					Element{
+						Name: "div",
+						Children: []Node{
+							Element{Name: "img", Attributes: []Attribute{tt.input}},
+						},
+					},
+				},
+			}
+			actual := RewriteAssetURLs(input, "https://cdn.example.com/")
+			img := actual.Children[0].(Element).Children[0].(Element)
+			if diff := cmp.Diff(tt.expected, img.Attributes[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SnapshotOptions customise the output of SnapshotWithOptions.
+type SnapshotOptions struct {
+	// Ranges includes the source range of each expression, e.g. `1:4-1:10`.
+	Ranges bool
+}
+
+// Snapshot returns a compact, stable text representation of the parsed template, with one
+// node per line, and child nodes indented, e.g. to store as a golden file in tests. Fields
+// with zero values are omitted, and so are source ranges, see SnapshotWithOptions.
+func Snapshot(t HTMLTemplate) string {
+	return SnapshotWithOptions(t, SnapshotOptions{})
+}
+
+// SnapshotWithOptions returns a snapshot of the template, see Snapshot.
+func SnapshotWithOptions(t HTMLTemplate, opts SnapshotOptions) string {
+	s := snapshotter{opts: opts}
+	s.value(0, "", reflect.ValueOf(t))
+	return s.sb.String()
+}
+
+// Dump writes an outline of the nodes to w for debugging, in the same format as Snapshot,
+// including the source range of each expression, and of the nodes that have one.
+func Dump(w io.Writer, nodes []Node) error {
+	s := snapshotter{opts: SnapshotOptions{Ranges: true}}
+	s.list(0, reflect.ValueOf(nodes))
+	_, err := io.WriteString(w, s.sb.String())
+	return err
+}
+
+type snapshotter struct {
+	opts SnapshotOptions
+	sb   strings.Builder
+}
+
+var (
+	expressionType = reflect.TypeOf(Expression{})
+	rangeType      = reflect.TypeOf(Range{})
+)
+
+// value writes a line for the struct, followed by its lists of nodes or attributes, and any
+// fields that are structs themselves. The Children field is written without a label, since
+// it's the most common.
+func (s *snapshotter) value(indent int, label string, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		s.line(indent, label+scalar(v))
+		return
+	}
+	if v.Type() == expressionType {
+		s.line(indent, label+s.expression(v.Interface().(Expression)))
+		return
+	}
+	fields := []string{v.Type().Name()}
+	type nested struct {
+		name  string
+		value reflect.Value
+	}
+	var nestedFields []nested
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := v.Type().Field(i), v.Field(i)
+		if !f.IsExported() || fv.IsZero() {
+			continue
+		}
+		switch {
+		case f.Type == expressionType:
+			fields = append(fields, f.Name+"="+s.expression(fv.Interface().(Expression)))
+		case f.Type == rangeType:
+			if s.opts.Ranges {
+				fields = append(fields, f.Name+"="+formatRange(fv.Interface().(Range)))
+			}
+		case isScalar(fv):
+			fields = append(fields, f.Name+"="+scalar(fv))
+		default:
+			nestedFields = append(nestedFields, nested{name: f.Name, value: fv})
+		}
+	}
+	s.line(indent, label+strings.Join(fields, " "))
+	for _, f := range nestedFields {
+		if f.name == "Children" {
+			s.list(indent+1, f.value)
+			continue
+		}
+		if f.value.Kind() == reflect.Slice {
+			s.line(indent+1, f.name+":")
+			s.list(indent+2, f.value)
+			continue
+		}
+		s.value(indent+1, f.name+": ", f.value)
+	}
+}
+
+func (s *snapshotter) list(indent int, v reflect.Value) {
+	for i := 0; i < v.Len(); i++ {
+		s.value(indent, "", v.Index(i))
+	}
+}
+
+func (s *snapshotter) line(indent int, text string) {
+	s.sb.WriteString(strings.Repeat("  ", indent))
+	s.sb.WriteString(text)
+	s.sb.WriteString("\n")
+}
+
+func (s *snapshotter) expression(e Expression) string {
+	if !s.opts.Ranges {
+		return strconv.Quote(e.Value)
+	}
+	return strconv.Quote(e.Value) + "@" + formatRange(e.Range)
+}
+
+func formatRange(r Range) string {
+	return fmt.Sprintf("%d:%d-%d:%d", r.From.Line, r.From.Col, r.To.Line, r.To.Col)
+}
+
+func isScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func scalar(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/txtar"
+)
+
+var updateSnapshots = flag.Bool("update-snapshots", false, "update the golden files in testdata/snapshots")
+
+// TestSnapshots parses the template in each golden file, and compares its snapshot. If the
+// comment of the file contains "ranges", the snapshot includes source ranges.
+func TestSnapshots(t *testing.T) {
+	files, _ := filepath.Glob("testdata/snapshots/*.txt")
+	if len(files) == 0 {
+		t.Errorf("no snapshot files found")
+	}
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			a, err := txtar.ParseFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(a.Files) != 2 {
+				t.Fatalf("expected 2 files, got %d", len(a.Files))
+			}
+			input := parse.NewInput(strings.TrimSuffix(string(a.Files[0].Data), "\n"))
+			tem, ok, err := template.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			actual := SnapshotWithOptions(tem, SnapshotOptions{
+				Ranges: strings.Contains(string(a.Comment), "ranges"),
+			})
+			if *updateSnapshots {
+				a.Files[1].Data = []byte(actual)
+				if err = os.WriteFile(file, txtar.Format(a), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if diff := cmp.Diff(string(a.Files[1].Data), actual); diff != "" {
+				t.Errorf("%s: run with -update-snapshots to update:\n%s", file, diff)
+			}
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	input := parse.NewInput(`templ Name(p Person) {
+	<a href={ p.URL } class="link">{ p.Name }</a>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	expected := `HTMLTemplate Expression="Name(p Person)"
+  Whitespace Value="\t"
+  Element Name="a" TrailingSpace="\n"
+    Attributes:
+      ExpressionAttribute Name="href" Expression="p.URL"
+      ConstantAttribute Name="class" Value="link"
+    StringExpression Expression="p.Name"
+`
+	if diff := cmp.Diff(expected, Snapshot(tem)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDump(t *testing.T) {
+	input := parse.NewInput(`templ Name(p Parameter) {
+<div>
+  { "div content" }
+  <span>
+	{ "span content" }
+  </span>
+  <!-- comment -->
+</div>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	expected := `Element Name="div" IndentChildren=true TrailingSpace="\n" Range=1:0-7:6
+  Whitespace Value="\n  "
+  StringExpression Expression="\"div content\""@2:4-2:17 TrailingSpace="\n" Range=2:2-2:19
+  Element Name="span" IndentChildren=true TrailingSpace="\n" Range=3:2-5:9
+    Whitespace Value="\n\t"
+    StringExpression Expression="\"span content\""@4:3-4:17 TrailingSpace="\n" Range=4:1-4:19
+  HTMLComment Contents=" comment " Range=6:2-6:18
+  Whitespace Value="\n"
+`
+	var sb strings.Builder
+	if err = Dump(&sb, tem.Children); err != nil {
+		t.Fatalf("failed to dump: %v", err)
+	}
+	if diff := cmp.Diff(expected, sb.String()); diff != "" {
+		t.Error(diff)
+	}
+}
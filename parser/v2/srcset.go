@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"io"
+	"strings"
+)
+
+// ImageCandidate is an image URL within a srcset attribute, with its optional width (`300w`)
+// or pixel density (`2x`) descriptor.
+type ImageCandidate struct {
+	URL        string
+	Descriptor string
+}
+
+// SrcsetAttribute is a constant srcset attribute, parsed into its image candidates.
+// It's only produced when the TemplateFileParser's ParseSrcset option is set.
+//
+//	srcset="image-1x.png 1x, image-2x.png 2x"
+type SrcsetAttribute struct {
+	Candidates []ImageCandidate
+}
+
+// Value returns the candidates formatted as the value of a srcset attribute.
+func (sa SrcsetAttribute) Value() string {
+	sb := new(strings.Builder)
+	for i, c := range sa.Candidates {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(c.URL)
+		if c.Descriptor != "" {
+			sb.WriteString(" ")
+			sb.WriteString(c.Descriptor)
+		}
+	}
+	return sb.String()
+}
+
+func (sa SrcsetAttribute) String() string {
+	value := sa.Value()
+	return ConstantAttribute{
+		Name:        "srcset",
+		Value:       value,
+		SingleQuote: strings.Contains(value, `"`),
+	}.String()
+}
+
+func (sa SrcsetAttribute) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, sa.String())
+}
+
+// ParseSrcsetCandidates parses the value of a srcset attribute into its image candidates,
+// following the srcset grammar of the HTML specification. URLs may contain commas, so
+// candidates are only split at commas that follow a URL and its whitespace, or at commas
+// at the end of a URL. Commas within parentheses in a descriptor don't end a candidate.
+func ParseSrcsetCandidates(s string) (candidates []ImageCandidate) {
+	isSpace := func(b byte) bool {
+		return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+	}
+	i := 0
+	for {
+		// Skip whitespace and commas between candidates.
+		for i < len(s) && (isSpace(s[i]) || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return candidates
+		}
+		// The URL continues until whitespace.
+		start := i
+		for i < len(s) && !isSpace(s[i]) {
+			i++
+		}
+		url := s[start:i]
+		// A URL ending with commas has no descriptors, and the commas end the candidate.
+		if trimmed := strings.TrimRight(url, ","); len(trimmed) < len(url) {
+			candidates = append(candidates, ImageCandidate{URL: trimmed})
+			continue
+		}
+		// The descriptors continue until a comma that isn't within parentheses.
+		start = i
+		var inParens bool
+	descriptors:
+		for ; i < len(s); i++ {
+			switch s[i] {
+			case '(':
+				inParens = true
+			case ')':
+				inParens = false
+			case ',':
+				if !inParens {
+					break descriptors
+				}
+			}
+		}
+		candidates = append(candidates, ImageCandidate{
+			URL:        url,
+			Descriptor: strings.Join(strings.Fields(s[start:i]), " "),
+		})
+	}
+}
+
+// parseSrcsets replaces the constant srcset attributes of elements with a SrcsetAttribute.
+func parseSrcsets(nodes []Node) []Node {
+	return mapElements(nodes, func(e Element) Element {
+		e.Attributes = mapAttributes(e.Attributes, func(a Attribute) Attribute {
+			if ca, ok := a.(ConstantAttribute); ok && strings.EqualFold(ca.Name, "srcset") {
+				return SrcsetAttribute{
+					Candidates: ParseSrcsetCandidates(ca.Value),
+				}
+			}
+			return a
+		})
+		return e
+	})
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSrcsetCandidates(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []ImageCandidate
+	}{
+		{
+			name:  "density descriptors",
+			input: "image-1x.png 1x, image-2x.png 2x",
+			expected: []ImageCandidate{
+				{URL: "image-1x.png", Descriptor: "1x"},
+				{URL: "image-2x.png", Descriptor: "2x"},
+			},
+		},
+		{
+			name:  "width descriptors",
+			input: "small.jpg 300w,\n\tlarge.jpg 1200w",
+			expected: []ImageCandidate{
+				{URL: "small.jpg", Descriptor: "300w"},
+				{URL: "large.jpg", Descriptor: "1200w"},
+			},
+		},
+		{
+			name:  "candidates without descriptors",
+			input: "a.png, b.png 2x",
+			expected: []ImageCandidate{
+				{URL: "a.png"},
+				{URL: "b.png", Descriptor: "2x"},
+			},
+		},
+		{
+			name:  "URLs can contain commas",
+			input: "/img?size=1,2 1x, /img?size=3,4 2x",
+			expected: []ImageCandidate{
+				{URL: "/img?size=1,2", Descriptor: "1x"},
+				{URL: "/img?size=3,4", Descriptor: "2x"},
+			},
+		},
+		{
+			name:  "data URLs can contain commas",
+			input: "data:image/png;base64,iVBORw0KGgo= 1x",
+			expected: []ImageCandidate{
+				{URL: "data:image/png;base64,iVBORw0KGgo=", Descriptor: "1x"},
+			},
+		},
+		{
+			name:     "empty",
+			input:    " , ",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ParseSrcsetCandidates(tt.input)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTemplateFileParserParseSrcset(t *testing.T) {
+	input := `package main
+
+templ Name() {
+	<img srcset="a.png 1x, b.png 2x"/>
+}
+`
+	var tests = []struct {
+		name        string
+		parseSrcset bool
+		expected    Attribute
+	}{
+		{
+			name:        "srcset is a constant attribute by default",
+			parseSrcset: false,
+			expected: ConstantAttribute{
+				Name:  "srcset",
+				Value: "a.png 1x, b.png 2x",
+				Range: Range{
+					From: Position{
+						Index: 35,
+						Line:  3,
+						Col:   6,
+					},
+					To: Position{
+						Index: 62,
+						Line:  3,
+						Col:   33,
+					},
+				},
+			},
+		},
+		{
+			name:        "srcset is parsed when the option is set",
+			parseSrcset: true,
+			expected: SrcsetAttribute{
+				Candidates: []ImageCandidate{
+					{URL: "a.png", Descriptor: "1x"},
+					{URL: "b.png", Descriptor: "2x"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTemplateFileParser("main")
+			p.ParseSrcset = tt.parseSrcset
+			tf, ok, err := p.Parse(parse.NewInput(input))
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatal("failed to parse template file")
+			}
+			img := firstElement(t, tf.Nodes[0].(HTMLTemplate).Children)
+			if diff := cmp.Diff(tt.expected, img.Attributes[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import "reflect"
+
+// TreeStats describes the size of a tree of nodes, e.g. to find templates that are too complex.
+type TreeStats struct {
+	// Nodes is the number of nodes of each kind, keyed by the name of the type, e.g. "Element"
+	// or "StringExpression", including whitespace.
+	Nodes map[string]int
+	// Depth is how deeply the nodes are nested, e.g. 1 for a list of text nodes, or 2 for an
+	// element that contains text. The branches of if, switch and for expressions count as a
+	// level, as each is within the expression.
+	Depth int
+	// Attributes is the number of attributes of elements, including those within conditional
+	// attributes, but not the conditional attributes themselves.
+	Attributes int
+	// Expressions is the number of Go expressions that are evaluated when the template is
+	// rendered, in nodes and attributes, e.g. `{ name }`, `href={ url }`, or the condition of
+	// an if expression.
+	Expressions int
+}
+
+// Stats returns the statistics of the nodes, and the nodes within them. To get the statistics
+// of a template, pass its Children.
+func Stats(nodes []Node) TreeStats {
+	s := TreeStats{
+		Nodes: make(map[string]int),
+		Depth: treeDepth(nodes),
+	}
+	Walk(nodes, func(n Node) bool {
+		s.Nodes[reflect.TypeOf(n).Name()]++
+		switch n := n.(type) {
+		case Element:
+			s.Attributes += attributeCount(n.Attributes)
+		case RawElement:
+			s.Attributes += attributeCount(n.Attributes)
+		}
+		nodeExpressions(n, func(e Expression) {
+			s.Expressions++
+		})
+		return true
+	})
+	return s
+}
+
+func treeDepth(nodes []Node) (depth int) {
+	for _, n := range nodes {
+		depth = maxInt(depth, 1+treeDepth(ChildNodes(n)))
+	}
+	return depth
+}
+
+func attributeCount(attrs []Attribute) (count int) {
+	for _, a := range attrs {
+		if ca, ok := a.(ConditionalAttribute); ok {
+			count += attributeCount(ca.Then) + attributeCount(ca.Else)
+			continue
+		}
+		count++
+	}
+	return count
+}
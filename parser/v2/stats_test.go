@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStats(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected TreeStats
+	}{
+		{
+			name: "nested elements with attributes",
+			input: `templ x() {
+	<div class="card" data-title="Tom &amp; Jerry's" hidden>
+		<p>Hello, <b>World</b>!</p>
+		<br/>
+		<img src="/a.png" alt="a &lt; b"/>
+	</div>
+}`,
+			expected: TreeStats{
+				Nodes:      map[string]int{"Element": 5, "Text": 3, "Whitespace": 2},
+				Depth:      4,
+				Attributes: 5,
+			},
+		},
+		{
+			name: "expressions",
+			input: `templ x(items []Item, ok bool) {
+	<ul class={ "list", templ.KV("active", ok) } if ok { data-ok="yes" } else { data-ok="no" }>
+		for _, item := range items {
+			<li><a href={ item.URL }>{ item.Name }</a></li>
+		}
+	</ul>
+	if ok {
+		@footer()
+	}
+}`,
+			expected: TreeStats{
+				Nodes: map[string]int{
+					"Element":                3,
+					"ForExpression":          1,
+					"IfExpression":           1,
+					"StringExpression":       1,
+					"TemplElementExpression": 1,
+					"Whitespace":             6,
+				},
+				Depth:       5,
+				Attributes:  4,
+				Expressions: 8,
+			},
+		},
+		{
+			name:     "no nodes",
+			input:    "templ x() {\n}",
+			expected: TreeStats{Nodes: map[string]int{}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tem, ok, err := template.Parse(parse.NewInput(tt.input))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, Stats(tem.Children)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
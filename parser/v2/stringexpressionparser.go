@@ -1,18 +1,41 @@
 package parser
 
 import (
+	"go/scanner"
+	"go/token"
+
 	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
 )
 
 var stringExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+
 	// Check the prefix first.
-	if _, ok, err = parse.Or(parse.String("{ "), parse.String("{")).Parse(pi); err != nil || !ok {
+	if _, ok, err = parse.String("{").Parse(pi); err != nil || !ok {
 		return
 	}
+	var r StringExpression
+	if peekPrefix(pi, "- ", "-\t", "-\n", "-\r\n") {
+		r.TrimLeft = true
+		pi.Take(1)
+	}
+	_, _, _ = parse.OptionalWhitespace.Parse(pi)
 
 	// Once we have a prefix, we must have an expression that returns a string, with optional err.
-	var r StringExpression
-	if r.Expression, err = parseGoSliceArgs(pi); err != nil {
+	src, _ := pi.Peek(-1)
+	if marker, ok := trimRightMarker(src); ok {
+		r.TrimRight = true
+		from := pi.Position()
+		var expr string
+		if expr, err = goexpression.SliceArgs(src[:marker]); err != nil {
+			return r, false, err
+		}
+		pi.Take(len(expr))
+		r.Expression = NewExpression(expr, from, pi.Position())
+		_, _, _ = parse.OptionalWhitespace.Parse(pi)
+		pi.Take(1)
+	} else if r.Expression, err = parseGoSliceArgs(pi); err != nil {
 		return r, false, err
 	}
 
@@ -24,6 +47,7 @@ var stringExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err er
 		err = parse.Error("string expression: missing close brace", pi.Position())
 		return
 	}
+	r.Range = sourceRange(pi, start)
 
 	// Parse trailing whitespace.
 	ws, _, err := parse.Whitespace.Parse(pi)
@@ -37,3 +61,35 @@ var stringExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err er
 
 	return r, true, nil
 })
+
+// trimRightMarker returns the index of the `-` in a ` -}` trim marker at the end of the string
+// expression that starts src, e.g. `name -}`. Braces, brackets and parentheses within the
+// expression, and those within strings and comments, are skipped.
+func trimRightMarker(src string) (marker int, ok bool) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+	var depth int
+	prev, prevOffset := token.ILLEGAL, -1
+	for {
+		pos, tok, _ := s.Scan()
+		offset := file.Offset(pos)
+		switch tok {
+		case token.EOF:
+			return 0, false
+		case token.LBRACE, token.LPAREN, token.LBRACK:
+			depth++
+		case token.RPAREN, token.RBRACK:
+			depth--
+		case token.RBRACE:
+			if depth > 0 {
+				depth--
+				break
+			}
+			isMarker := prev == token.SUB && prevOffset == offset-1 && prevOffset > 0 && isWhitespace(src[prevOffset-1:prevOffset])
+			return prevOffset, isMarker
+		}
+		prev, prevOffset = tok, offset
+	}
+}
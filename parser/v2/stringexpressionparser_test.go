@@ -33,6 +33,18 @@ func TestStringExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 10,
+						Line:  0,
+						Col:   10,
+					},
+				},
 			},
 		},
 		{
@@ -55,6 +67,18 @@ func TestStringExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 8,
+						Line:  0,
+						Col:   8,
+					},
+				},
 			},
 		},
 		{
@@ -79,6 +103,196 @@ func TestStringExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 29,
+						Line:  2,
+						Col:   7,
+					},
+				},
+			},
+		},
+		{
+			name:  "trim markers",
+			input: `{- name -}`,
+			expected: StringExpression{
+				Expression: Expression{
+					Value: "name",
+					Range: Range{
+						From: Position{Index: 3, Line: 0, Col: 3},
+						To:   Position{Index: 7, Line: 0, Col: 7},
+					},
+				},
+				TrimLeft:  true,
+				TrimRight: true,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 10,
+						Line:  0,
+						Col:   10,
+					},
+				},
+			},
+		},
+		{
+			name:  "trim marker after braces within the expression",
+			input: `{ f(func() string { return "}" }) -}`,
+			expected: StringExpression{
+				Expression: Expression{
+					Value: `f(func() string { return "}" })`,
+					Range: Range{
+						From: Position{Index: 2, Line: 0, Col: 2},
+						To:   Position{Index: 33, Line: 0, Col: 33},
+					},
+				},
+				TrimRight: true,
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 36,
+						Line:  0,
+						Col:   36,
+					},
+				},
+			},
+		},
+		{
+			name:  "negative numbers and subtraction aren't trim markers",
+			input: `{-1 - x}`,
+			expected: StringExpression{
+				Expression: Expression{
+					Value: "-1 - x",
+					Range: Range{
+						From: Position{Index: 1, Line: 0, Col: 1},
+						To:   Position{Index: 7, Line: 0, Col: 7},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 8,
+						Line:  0,
+						Col:   8,
+					},
+				},
+			},
+		},
+		{
+			name:  "multiline expression with a composite literal",
+			input: "{ strings.Join([]string{\n\t\"a\",\n\t\"b\",\n}, \",\") }",
+			expected: StringExpression{
+				Expression: Expression{
+					Value: "strings.Join([]string{\n\t\"a\",\n\t\"b\",\n}, \",\")",
+					Range: Range{
+						From: Position{Index: 2, Line: 0, Col: 2},
+						To:   Position{Index: 44, Line: 3, Col: 7},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 46,
+						Line:  3,
+						Col:   9,
+					},
+				},
+			},
+		},
+		{
+			name:  "struct literal",
+			input: `{ fmt.Sprint(Point{X: 1, Y: 2}) }`,
+			expected: StringExpression{
+				Expression: Expression{
+					Value: `fmt.Sprint(Point{X: 1, Y: 2})`,
+					Range: Range{
+						From: Position{Index: 2, Line: 0, Col: 2},
+						To:   Position{Index: 31, Line: 0, Col: 31},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 33,
+						Line:  0,
+						Col:   33,
+					},
+				},
+			},
+		},
+		{
+			name:  "closing brace within a string",
+			input: `{ "}" }`,
+			expected: StringExpression{
+				Expression: Expression{
+					Value: `"}"`,
+					Range: Range{
+						From: Position{Index: 2, Line: 0, Col: 2},
+						To:   Position{Index: 5, Line: 0, Col: 5},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 7,
+						Line:  0,
+						Col:   7,
+					},
+				},
+			},
+		},
+		{
+			name:  "braces within raw strings and runes",
+			input: "{ `}` + string('}') + `{` }",
+			expected: StringExpression{
+				Expression: Expression{
+					Value: "`}` + string('}') + `{`",
+					Range: Range{
+						From: Position{Index: 2, Line: 0, Col: 2},
+						To:   Position{Index: 25, Line: 0, Col: 25},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 27,
+						Line:  0,
+						Col:   27,
+					},
+				},
 			},
 		},
 	}
@@ -24,11 +24,14 @@ func (_ switchExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err err
 		return r, false, err
 	}
 
-	// Eat " {\n".
-	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+	// Eat " {".
+	if _, ok, err = openBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
 		err = parse.Error("switch: "+unterminatedMissingCurly, pi.PositionAt(start))
 		return
 	}
+	// The newline is optional, since Go case clauses don't use braces, the cases can be
+	// written on the same line, e.g. `switch x { case "a": <span>A</span> }`.
+	_, _, _ = parse.NewLine.Parse(pi)
 
 	// Once we've had the start of a switch block, we must conclude the block.
 
@@ -50,6 +53,7 @@ func (_ switchExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err err
 		err = parse.Error("switch: "+unterminatedMissingEnd, pi.Position())
 		return
 	}
+	r.Range = sourceRange(pi, start)
 
 	return r, true, nil
 }
@@ -33,6 +33,18 @@ func TestSwitchExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 20,
+						Line:  1,
+						Col:   1,
+					},
+				},
 			},
 		},
 		{
@@ -99,14 +111,50 @@ default:
 											},
 										},
 										TrailingSpace: SpaceVertical,
+										Range: Range{
+											From: Position{
+												Index: 39,
+												Line:  3,
+												Col:   3,
+											},
+											To: Position{
+												Index: 57,
+												Line:  3,
+												Col:   21,
+											},
+										},
 									},
 								},
 								IndentChildren: true,
 								TrailingSpace:  SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 29,
+										Line:  2,
+										Col:   1,
+									},
+									To: Position{
+										Index: 66,
+										Line:  4,
+										Col:   8,
+									},
+								},
 							},
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 68,
+						Line:  5,
+						Col:   1,
+					},
+				},
 			},
 		},
 		{
@@ -172,14 +220,50 @@ default:
 											},
 										},
 										TrailingSpace: SpaceVertical,
+										Range: Range{
+											From: Position{
+												Index: 45,
+												Line:  3,
+												Col:   2,
+											},
+											To: Position{
+												Index: 63,
+												Line:  3,
+												Col:   20,
+											},
+										},
 									},
 								},
 								IndentChildren: true,
 								TrailingSpace:  SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 36,
+										Line:  2,
+										Col:   0,
+									},
+									To: Position{
+										Index: 71,
+										Line:  4,
+										Col:   7,
+									},
+								},
 							},
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 73,
+						Line:  5,
+						Col:   1,
+					},
+				},
 			},
 		},
 		{
@@ -244,6 +328,18 @@ default:
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 32,
+										Line:  2,
+										Col:   2,
+									},
+									To: Position{
+										Index: 39,
+										Line:  2,
+										Col:   9,
+									},
+								},
 							},
 						},
 					},
@@ -284,10 +380,314 @@ default:
 									},
 								},
 								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 53,
+										Line:  4,
+										Col:   2,
+									},
+									To: Position{
+										Index: 60,
+										Line:  4,
+										Col:   9,
+									},
+								},
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 62,
+						Line:  5,
+						Col:   1,
+					},
+				},
+			},
+		},
+		{
+			name:  "switch: cases on a single line",
+			input: `switch x { case "a": Letter <span>A</span> case "b": Letter <span>B</span> }`,
+			expected: SwitchExpression{
+				Expression: Expression{
+					Value: `x`,
+					Range: Range{
+						From: Position{Index: 7, Line: 0, Col: 7},
+						To:   Position{Index: 8, Line: 0, Col: 8},
+					},
+				},
+				Cases: []CaseExpression{
+					{
+						Expression: Expression{
+							Value: `case "a":`,
+							Range: Range{
+								From: Position{Index: 11, Line: 0, Col: 11},
+								To:   Position{Index: 20, Line: 0, Col: 20},
+							},
+						},
+						Children: []Node{
+							Text{
+								Value: "Letter ",
+								Range: Range{
+									From: Position{
+										Index: 21,
+										Line:  0,
+										Col:   21,
+									},
+									To: Position{
+										Index: 28,
+										Line:  0,
+										Col:   28,
+									},
+								},
+							},
+							Element{
+								Name: "span",
+								Children: []Node{
+									Text{
+										Value: "A",
+										Range: Range{
+											From: Position{
+												Index: 34,
+												Line:  0,
+												Col:   34,
+											},
+											To: Position{
+												Index: 35,
+												Line:  0,
+												Col:   35,
+											},
+										},
+									},
+								},
+								TrailingSpace: SpaceHorizontal,
+								Range: Range{
+									From: Position{
+										Index: 28,
+										Line:  0,
+										Col:   28,
+									},
+									To: Position{
+										Index: 42,
+										Line:  0,
+										Col:   42,
+									},
+								},
+							},
+						},
+					},
+					{
+						Expression: Expression{
+							Value: `case "b":`,
+							Range: Range{
+								From: Position{Index: 43, Line: 0, Col: 43},
+								To:   Position{Index: 52, Line: 0, Col: 52},
+							},
+						},
+						Children: []Node{
+							Text{
+								Value: "Letter ",
+								Range: Range{
+									From: Position{
+										Index: 53,
+										Line:  0,
+										Col:   53,
+									},
+									To: Position{
+										Index: 60,
+										Line:  0,
+										Col:   60,
+									},
+								},
+							},
+							Element{
+								Name: "span",
+								Children: []Node{
+									Text{
+										Value: "B",
+										Range: Range{
+											From: Position{
+												Index: 66,
+												Line:  0,
+												Col:   66,
+											},
+											To: Position{
+												Index: 67,
+												Line:  0,
+												Col:   67,
+											},
+										},
+									},
+								},
+								TrailingSpace: SpaceHorizontal,
+								Range: Range{
+									From: Position{
+										Index: 60,
+										Line:  0,
+										Col:   60,
+									},
+									To: Position{
+										Index: 74,
+										Line:  0,
+										Col:   74,
+									},
+								},
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 76,
+						Line:  0,
+						Col:   76,
+					},
+				},
+			},
+		},
+		{
+			name: "switch: two cases and a default containing an element",
+			input: `switch x {
+	case 1:
+		One
+	case 2:
+		Two
+	default:
+		<span>Other</span>
+}`,
+			expected: SwitchExpression{
+				Expression: Expression{
+					Value: `x`,
+					Range: Range{
+						From: Position{Index: 7, Line: 0, Col: 7},
+						To:   Position{Index: 8, Line: 0, Col: 8},
+					},
+				},
+				Cases: []CaseExpression{
+					{
+						Expression: Expression{
+							Value: `case 1:`,
+							Range: Range{
+								From: Position{Index: 12, Line: 1, Col: 1},
+								To:   Position{Index: 19, Line: 1, Col: 8},
+							},
+						},
+						Children: []Node{
+							Whitespace{Value: "\t\t"},
+							Text{
+								Value:         "One",
+								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 22,
+										Line:  2,
+										Col:   2,
+									},
+									To: Position{
+										Index: 25,
+										Line:  2,
+										Col:   5,
+									},
+								},
+							},
+						},
+					},
+					{
+						Expression: Expression{
+							Value: `case 2:`,
+							Range: Range{
+								From: Position{Index: 27, Line: 3, Col: 1},
+								To:   Position{Index: 34, Line: 3, Col: 8},
+							},
+						},
+						Children: []Node{
+							Whitespace{Value: "\t\t"},
+							Text{
+								Value:         "Two",
+								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 37,
+										Line:  4,
+										Col:   2,
+									},
+									To: Position{
+										Index: 40,
+										Line:  4,
+										Col:   5,
+									},
+								},
+							},
+						},
+					},
+					{
+						Expression: Expression{
+							Value: `default:`,
+							Range: Range{
+								From: Position{Index: 42, Line: 5, Col: 1},
+								To:   Position{Index: 50, Line: 5, Col: 9},
+							},
+						},
+						Children: []Node{
+							Whitespace{Value: "\t\t"},
+							Element{
+								Name: "span",
+								Children: []Node{
+									Text{
+										Value: "Other",
+										Range: Range{
+											From: Position{
+												Index: 59,
+												Line:  6,
+												Col:   8,
+											},
+											To: Position{
+												Index: 64,
+												Line:  6,
+												Col:   13,
+											},
+										},
+									},
+								},
+								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 53,
+										Line:  6,
+										Col:   2,
+									},
+									To: Position{
+										Index: 71,
+										Line:  6,
+										Col:   20,
+									},
+								},
 							},
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 73,
+						Line:  7,
+						Col:   1,
+					},
+				},
 			},
 		},
 	}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +19,16 @@ func Parse(fileName string) (TemplateFile, error) {
 	return ParseString(string(fc))
 }
 
+// ParseReader parses the template file read from r, e.g. from stdin. The parser needs to look
+// back at earlier input, so r is read in full before it's parsed.
+func ParseReader(r io.Reader) (TemplateFile, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return TemplateFile{}, err
+	}
+	return ParseString(string(b))
+}
+
 func getDefaultPackageName(fileName string) (pkg string) {
 	parent := filepath.Base(filepath.Dir(fileName))
 	if !isGoIdentifier(parent) {
@@ -65,11 +76,41 @@ var ErrTemplateNotFound = errors.New("template not found")
 
 type TemplateFileParser struct {
 	DefaultPackage string
+	// ParseInlineStyle parses constant style attributes into a StyleAttribute, instead of
+	// a ConstantAttribute.
+	ParseInlineStyle bool
+	// ParseSrcset parses constant srcset attributes into a SrcsetAttribute, instead of a
+	// ConstantAttribute.
+	ParseSrcset bool
+	// TextMode parses the body of each templ template as text, instead of HTML, for templates
+	// that output other formats, e.g. config files or SQL. Only `{ expr }` is interpolated,
+	// and the output isn't HTML escaped.
+	TextMode bool
+	// UnescapeEntities decodes the HTML character references in text, e.g. `&amp;` or `&#x27;`,
+	// so that the Value of each Text node is the text that's displayed. The text is encoded
+	// again when it's rendered or formatted. It has no effect with TextMode.
+	UnescapeEntities bool
+	// RetainSource sets the Source field of the nodes that have one, e.g. Element, to the text
+	// they were parsed from, so that unformatted output can be reconstructed. The Source is a
+	// slice of the input, so it isn't copied, but it keeps the input in memory.
+	RetainSource bool
+	// NormalizeCase lowercases the names of HTML elements and attributes, e.g. `<DIV CLASS="x">`
+	// is parsed as `<div class="x">`. SVG and MathML elements, and the elements within them,
+	// keep their case, since it's significant, e.g. viewBox. Attribute values and text aren't
+	// changed.
+	NormalizeCase bool
 }
 
 var legacyPackageParser = parse.String("{% package")
 
+// byteOrderMark is written at the start of files by some editors.
+var byteOrderMark = parse.String("\uFEFF")
+
 func (p TemplateFileParser) Parse(pi *parse.Input) (tf TemplateFile, ok bool, err error) {
+	// Skip any byte order mark. Positions are still relative to the start of the input, so
+	// they include the skipped bytes.
+	_, _, _ = byteOrderMark.Parse(pi)
+
 	// If we're parsing a legacy file, complain that migration needs to happen.
 	_, ok, err = legacyPackageParser.Parse(pi)
 	if err != nil {
@@ -80,6 +121,7 @@ func (p TemplateFileParser) Parse(pi *parse.Input) (tf TemplateFile, ok bool, er
 	}
 
 	// Read until the package.
+	start := pi.Position()
 	for {
 		// Package.
 		// package name
@@ -98,7 +140,8 @@ func (p TemplateFileParser) Parse(pi *parse.Input) (tf TemplateFile, ok bool, er
 			return
 		}
 		if !ok {
-			break
+			err = parse.Error("template file: missing package clause, e.g. `package main`", start)
+			return
 		}
 		var newLine string
 		newLine, _, _ = parse.NewLine.Parse(pi)
@@ -113,11 +156,30 @@ outer:
 		// Optional templates, CSS, and script templates.
 		// templ Name(p Parameter)
 		var tn HTMLTemplate
-		tn, ok, err = template.Parse(pi)
+		if p.TextMode {
+			tn, ok, err = textTemplate.Parse(pi)
+		} else {
+			tn, ok, err = template.Parse(pi)
+		}
 		if err != nil {
 			return tf, false, err
 		}
 		if ok {
+			if p.RetainSource {
+				tn.Children = retainSource(tn.Children, pi)
+			}
+			if p.NormalizeCase {
+				tn.Children = normalizeCase(tn.Children)
+			}
+			if p.ParseInlineStyle {
+				tn.Children = parseInlineStyles(tn.Children)
+			}
+			if p.ParseSrcset {
+				tn.Children = parseSrcsets(tn.Children)
+			}
+			if p.UnescapeEntities && !p.TextMode {
+				tn.Children = unescapeTextEntities(tn.Children)
+			}
 			tf.Nodes = append(tf.Nodes, tn)
 			tf.Diagnostics = append(tf.Diagnostics, tn.Diagnostics...)
 			_, _, _ = parse.OptionalWhitespace.Parse(pi)
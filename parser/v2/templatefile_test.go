@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
@@ -52,6 +55,69 @@ templ Hello() {
 			t.Errorf("expected 2 node, got %d nodes with content %+v", len(tf.Nodes), tf.Nodes)
 		}
 	})
+	t.Run("can start with a byte order mark", func(t *testing.T) {
+		input := "\uFEFFpackage goof\n\ntempl Hello() {\n\tHello\n}"
+		tf, err := ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		if tf.Package.Expression.Value != "package goof" {
+			t.Errorf("expected \"package goof\", got %q", tf.Package.Expression.Value)
+		}
+		if len(tf.Header) != 0 {
+			t.Errorf("expected no header, got %+v", tf.Header)
+		}
+		if len(tf.Nodes) != 1 {
+			t.Fatalf("expected 1 node, got %d nodes with content %+v", len(tf.Nodes), tf.Nodes)
+		}
+		expectedPackageRange := Range{
+			From: Position{Index: 3, Line: 0, Col: 3},
+			To:   Position{Index: 15, Line: 0, Col: 15},
+		}
+		if diff := cmp.Diff(expectedPackageRange, tf.Package.Expression.Range); diff != "" {
+			t.Errorf("unexpected package range:\n%s", diff)
+		}
+		expectedNameRange := Range{
+			From: Position{Index: 23, Line: 2, Col: 6},
+			To:   Position{Index: 30, Line: 2, Col: 13},
+		}
+		if diff := cmp.Diff(expectedNameRange, tf.Nodes[0].(HTMLTemplate).Expression.Range); diff != "" {
+			t.Errorf("unexpected template range:\n%s", diff)
+		}
+	})
+	t.Run("can start with blank lines", func(t *testing.T) {
+		input := "\n\n  \npackage goof\n\ntempl Hello() {\n\tHello\n}"
+		tf, err := ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		if tf.Package.Expression.Value != "package goof" {
+			t.Errorf("expected \"package goof\", got %q", tf.Package.Expression.Value)
+		}
+		// The blank lines are kept in the header, so that formatting round trips.
+		for _, h := range tf.Header {
+			if strings.TrimSpace(h.Expression.Value) != "" {
+				t.Errorf("expected only whitespace in the header, got %q", h.Expression.Value)
+			}
+		}
+		if len(tf.Nodes) != 1 {
+			t.Fatalf("expected 1 node, got %d nodes with content %+v", len(tf.Nodes), tf.Nodes)
+		}
+		expectedPackageRange := Range{
+			From: Position{Index: 5, Line: 3, Col: 0},
+			To:   Position{Index: 17, Line: 3, Col: 12},
+		}
+		if diff := cmp.Diff(expectedPackageRange, tf.Package.Expression.Range); diff != "" {
+			t.Errorf("unexpected package range:\n%s", diff)
+		}
+		expectedNameRange := Range{
+			From: Position{Index: 25, Line: 5, Col: 6},
+			To:   Position{Index: 32, Line: 5, Col: 13},
+		}
+		if diff := cmp.Diff(expectedNameRange, tf.Nodes[0].(HTMLTemplate).Expression.Range); diff != "" {
+			t.Errorf("unexpected template range:\n%s", diff)
+		}
+	})
 	t.Run("template files can end with Go expressions", func(t *testing.T) {
 		input := `package goof
 
@@ -125,6 +191,56 @@ const y = ` + "`456`"
 			t.Errorf("2: unexpected expression: %q", expr.Expression.Value)
 		}
 	})
+	t.Run("requires a package clause", func(t *testing.T) {
+		input := `// Example comment.
+
+templ Hello() {
+	Hello
+}`
+		_, err := ParseString(input)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if !strings.Contains(err.Error(), "missing package clause") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("can contain imports and multiple templates", func(t *testing.T) {
+		input := `package goof
+
+import (
+	"fmt"
+	c "github.com/a-h/app/components"
+)
+
+templ Hello() {
+	Hello
+}
+
+func name() string {
+	return fmt.Sprint("World")
+}
+
+templ World() {
+	@c.Button(name())
+}`
+		tf, err := ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		if diff := cmp.Diff([]string{"fmt", "github.com/a-h/app/components"}, tf.Imports()); diff != "" {
+			t.Errorf("unexpected imports:\n%s", diff)
+		}
+		var templates []string
+		for _, n := range tf.Nodes {
+			if t, ok := n.(HTMLTemplate); ok {
+				templates = append(templates, t.Expression.Value)
+			}
+		}
+		if diff := cmp.Diff([]string{"Hello()", "World()"}, templates); diff != "" {
+			t.Errorf("unexpected templates:\n%s", diff)
+		}
+	})
 }
 
 func TestTemplateFileRoundTrip(t *testing.T) {
@@ -307,3 +423,48 @@ func TestDefaultPackageName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReader(t *testing.T) {
+	input := `package main
+
+// A comment.
+templ x(name string) {
+	<div class="a">Hello, { name }</div>
+}
+`
+	expected, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse string: %v", err)
+	}
+	var tests = []struct {
+		name string
+		r    io.Reader
+	}{
+		{name: "strings.Reader", r: strings.NewReader(input)},
+		{name: "bytes.Buffer", r: bytes.NewBufferString(input)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseReader(tt.r)
+			if err != nil {
+				t.Fatalf("failed to parse reader: %v", err)
+			}
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+type errorReader struct{}
+
+func (errorReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestParseReaderReturnsReadErrors(t *testing.T) {
+	if _, err := ParseReader(errorReader{}); err == nil || err.Error() != "read failed" {
+		t.Errorf("expected the read error, got %v", err)
+	}
+}
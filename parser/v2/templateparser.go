@@ -33,7 +33,7 @@ var templateExpressionParser = parse.Func(func(pi *parse.Input) (r templateExpre
 	}
 
 	// Eat " {\n".
-	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+	if _, ok, err = openBraceAndNewLine.Parse(pi); err != nil || !ok {
 		err = parse.Error("templ: malformed templ expression, expected `templ functionName() {`", pi.PositionAt(start))
 		return
 	}
@@ -57,28 +57,44 @@ func newTemplateNodeParser[TUntil any](until parse.Parser[TUntil], untilName str
 type templateNodeParser[TUntil any] struct {
 	until     parse.Parser[TUntil]
 	untilName string
+	// preserveWhitespace parses whitespace verbatim, see parsePreservedNode.
+	preserveWhitespace bool
 }
 
 var rawElements = parse.Any[Node](styleElement, scriptElement)
 
-var templateNodeParsers = []parse.Parser[Node]{
+var builtInNodeParsers = []parse.Parser[Node]{
 	docType,                // <!DOCTYPE html>
 	htmlComment,            // <!--
+	cData,                  // <![CDATA[
 	goComment,              // // or /*
 	rawElements,            // <text>, <>, or <style> element (special behaviour - contents are not parsed).
 	element,                // <a>, <br/> etc.
 	ifExpression,           // if {}
 	forExpression,          // for {}
 	switchExpression,       // switch {}
+	constBlock,             // const x = 1
+	goCode,                 // {{ x := 1 }}
 	callTemplateExpression, // {! TemplateName(a, b, c) }
+	onceExpression,         // @once { <script></script> }
+	fragmentBlock,          // @fragment "name" { <div></div> }
+	jsonScriptExpression,   // @templ.JSONScript("id", data)
+	assetExpression,        // @asset "images/logo.png"
+	rawHTMLExpression,      // @raw { <div>Trusted HTML</div> }
 	templElementExpression, // @TemplateName(a, b, c) { <div>Children</div> }
 	childrenExpression,     // { children... }
+	inlineIfExpression,     // { if ok { "a" } else { "b" } }
+	translationExpression,  // { i18n("greeting", name) }
 	stringExpression,       // { "abc" }
 	whitespaceExpression,   // { " " }
 	textParser,             // anything &amp; everything accepted...
 }
 
+// templateNodeParsers are tried in order to parse each node, see RegisterNodeParser.
+var templateNodeParsers = builtInNodeParsers
+
 func (p templateNodeParser[T]) Parse(pi *parse.Input) (op Nodes, ok bool, err error) {
+	var ignoreNext bool
 	for {
 		// Check if we've reached the end.
 		if p.until != nil {
@@ -94,26 +110,20 @@ func (p templateNodeParser[T]) Parse(pi *parse.Input) (op Nodes, ok bool, err er
 		}
 
 		// Attempt to parse a node.
-		// Loop through the parsers and try to parse a node.
+		var node Node
 		var matched bool
-		for _, p := range templateNodeParsers {
-			var node Node
-			node, matched, err = p.Parse(pi)
-			if err != nil {
-				return Nodes{}, false, err
-			}
-			if n, ok := node.(CallTemplateExpression); ok {
-				op.Diagnostics = append(op.Diagnostics, Diagnostic{
-					Message: "`{! foo }` syntax is deprecated. Use `@foo` syntax instead. Run `templ fmt .` to fix all instances.",
-					Range:   n.Expression.Range,
-				})
-			}
-			if matched {
-				op.Nodes = append(op.Nodes, node)
-				break
-			}
+		if p.preserveWhitespace {
+			node, matched, err = parsePreservedNode(pi)
+		} else {
+			node, matched, err = parseTemplateNode(pi)
+		}
+		if err != nil {
+			return Nodes{}, false, err
 		}
 		if matched {
+			op.Diagnostics = append(op.Diagnostics, nodeDiagnostics(node)...)
+			node, ignoreNext = applyIgnoreDirective(node, ignoreNext)
+			op.Nodes = append(op.Nodes, node)
 			continue
 		}
 
@@ -130,3 +140,42 @@ func (p templateNodeParser[T]) Parse(pi *parse.Input) (op Nodes, ok bool, err er
 
 	return op, true, nil
 }
+
+// parseTemplateNode loops through the templateNodeParsers and tries to parse a node.
+func parseTemplateNode(pi *parse.Input) (node Node, matched bool, err error) {
+	for _, p := range templateNodeParsers {
+		if node, matched, err = p.Parse(pi); err != nil || matched {
+			return node, matched, err
+		}
+	}
+	return nil, false, nil
+}
+
+// nodeDiagnostics returns warnings about the node.
+func nodeDiagnostics(node Node) []Diagnostic {
+	// Named arguments can only be used with the `{! foo }` syntax, so it's not deprecated for them.
+	if n, ok := node.(CallTemplateExpression); ok && len(n.NamedArgs) == 0 {
+		return []Diagnostic{{
+			Message: "`{! foo }` syntax is deprecated. Use `@foo` syntax instead. Run `templ fmt .` to fix all instances.",
+			Range:   n.Expression.Range,
+		}}
+	}
+	return nil
+}
+
+// applyIgnoreDirective marks the element following a `<!-- templ:ignore -->` comment as ignored.
+// Whitespace between the comment and the element is allowed.
+func applyIgnoreDirective(node Node, ignoreNext bool) (Node, bool) {
+	switch n := node.(type) {
+	case HTMLComment:
+		return n, n.IsIgnoreDirective()
+	case Whitespace:
+		return n, ignoreNext
+	case Element:
+		if ignoreNext {
+			n.Ignored = true
+		}
+		return n, false
+	}
+	return node, false
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/a-h/parse"
@@ -167,171 +168,34 @@ func TestTemplateParser(t *testing.T) {
 										},
 									},
 								},
-							},
-						},
-						TrailingSpace: SpaceVertical,
-					},
-				},
-			},
-		},
-		{
-			name: "template: containing nested elements",
-			input: `templ Name(p Parameter) {
-<div>
-  { "div content" }
-  <span>
-	{ "span content" }
-  </span>
-</div>
-}`,
-			expected: HTMLTemplate{
-				Expression: Expression{
-					Value: "Name(p Parameter)",
-					Range: Range{
-						From: Position{
-							Index: 6,
-							Line:  0,
-							Col:   6,
-						},
-						To: Position{
-							Index: 23,
-							Line:  0,
-							Col:   23,
-						},
-					},
-				},
-				Children: []Node{
-					Element{
-						Name: "div",
-						Children: []Node{
-							Whitespace{Value: "\n  "},
-							StringExpression{
-								Expression: Expression{
-									Value: `"div content"`,
-									Range: Range{
-										From: Position{
-											Index: 36,
-											Line:  2,
-											Col:   4,
-										},
-										To: Position{
-											Index: 49,
-											Line:  2,
-											Col:   17,
-										},
+								Range: Range{
+									From: Position{
+										Index: 32,
+										Line:  1,
+										Col:   6,
 									},
-								},
-								TrailingSpace: SpaceVertical,
-							},
-							Element{
-								Name: "span",
-								Children: []Node{
-									Whitespace{Value: "\n\t"},
-									StringExpression{
-										Expression: Expression{
-											Value: `"span content"`,
-											Range: Range{
-												From: Position{
-													Index: 64,
-													Line:  4,
-													Col:   3,
-												},
-												To: Position{
-													Index: 78,
-													Line:  4,
-													Col:   17,
-												},
-											},
-										},
-										TrailingSpace: SpaceVertical,
+									To: Position{
+										Index: 50,
+										Line:  1,
+										Col:   24,
 									},
 								},
-								IndentChildren: true,
-								TrailingSpace:  SpaceVertical,
 							},
 						},
-						IndentChildren: true,
-						TrailingSpace:  SpaceVertical,
-					},
-				},
-			},
-		},
-		{
-			name: "template: containing if element",
-			input: `templ Name(p Parameter) {
-	if p.Test {
-		<span>
-			{ "span content" }
-		</span>
-	}
-}`,
-			expected: HTMLTemplate{
-				Expression: Expression{
-					Value: "Name(p Parameter)",
-					Range: Range{
-						From: Position{
-							Index: 6,
-							Line:  0,
-							Col:   6,
-						},
-						To: Position{
-							Index: 23,
-							Line:  0,
-							Col:   23,
-						},
-					},
-				},
-				Children: []Node{
-					Whitespace{Value: "\t"},
-					IfExpression{
-						Expression: Expression{
-							Value: `p.Test`,
-							Range: Range{
-								From: Position{
-									Index: 30,
-									Line:  1,
-									Col:   4,
-								},
-								To: Position{
-									Index: 36,
-									Line:  1,
-									Col:   10,
-								},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 26,
+								Line:  1,
+								Col:   0,
 							},
-						},
-						Then: []Node{
-							Whitespace{Value: "\t\t"},
-							Element{
-								Name: "span",
-								Children: []Node{
-									Whitespace{"\n\t\t\t"},
-									StringExpression{
-										Expression: Expression{
-											Value: `"span content"`,
-											Range: Range{
-												From: Position{
-													Index: 53,
-													Line:  3,
-													Col:   5,
-												},
-												To: Position{
-													Index: 67,
-													Line:  3,
-													Col:   19,
-												},
-											},
-										},
-										TrailingSpace: SpaceVertical,
-									},
-								},
-								IndentChildren: true,
-								TrailingSpace:  SpaceVertical,
+							To: Position{
+								Index: 57,
+								Line:  1,
+								Col:   31,
 							},
 						},
 					},
-					Whitespace{
-						Value: "\n",
-					},
 				},
 			},
 		},
@@ -362,18 +226,102 @@ func TestTemplateParser(t *testing.T) {
 					Element{
 						Name: "input",
 						Attributes: []Attribute{
-							ConstantAttribute{Name: "type", Value: "text"},
-							ConstantAttribute{Name: "value", Value: "a"},
+							ConstantAttribute{
+								Name:  "type",
+								Value: "text",
+								Range: Range{
+									From: Position{
+										Index: 34,
+										Line:  1,
+										Col:   8,
+									},
+									To: Position{
+										Index: 45,
+										Line:  1,
+										Col:   19,
+									},
+								},
+							},
+							ConstantAttribute{
+								Name:  "value",
+								Value: "a",
+								Range: Range{
+									From: Position{
+										Index: 46,
+										Line:  1,
+										Col:   20,
+									},
+									To: Position{
+										Index: 55,
+										Line:  1,
+										Col:   29,
+									},
+								},
+							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 27,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 58,
+								Line:  1,
+								Col:   32,
+							},
+						},
 					},
 					Element{
 						Name: "input",
 						Attributes: []Attribute{
-							ConstantAttribute{Name: "type", Value: "text"},
-							ConstantAttribute{Name: "value", Value: "b"},
+							ConstantAttribute{
+								Name:  "type",
+								Value: "text",
+								Range: Range{
+									From: Position{
+										Index: 67,
+										Line:  2,
+										Col:   8,
+									},
+									To: Position{
+										Index: 78,
+										Line:  2,
+										Col:   19,
+									},
+								},
+							},
+							ConstantAttribute{
+								Name:  "value",
+								Value: "b",
+								Range: Range{
+									From: Position{
+										Index: 79,
+										Line:  2,
+										Col:   20,
+									},
+									To: Position{
+										Index: 88,
+										Line:  2,
+										Col:   29,
+									},
+								},
+							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 60,
+								Line:  2,
+								Col:   1,
+							},
+							To: Position{
+								Index: 91,
+								Line:  2,
+								Col:   32,
+							},
+						},
 					},
 				},
 			},
@@ -402,6 +350,18 @@ func TestTemplateParser(t *testing.T) {
 				Children: []Node{
 					DocType{
 						Value: "html",
+						Range: Range{
+							From: Position{
+								Index: 15,
+								Line:  1,
+								Col:   0,
+							},
+							To: Position{
+								Index: 30,
+								Line:  1,
+								Col:   15,
+							},
+						},
 					},
 					Whitespace{Value: "\n"},
 				},
@@ -448,6 +408,18 @@ func TestTemplateParser(t *testing.T) {
 							ConstantAttribute{
 								Name:  "href",
 								Value: "/",
+								Range: Range{
+									From: Position{
+										Index: 16,
+										Line:  1,
+										Col:   4,
+									},
+									To: Position{
+										Index: 24,
+										Line:  1,
+										Col:   12,
+									},
+								},
 							},
 						},
 						Children: []Node{
@@ -468,11 +440,49 @@ func TestTemplateParser(t *testing.T) {
 										},
 									},
 								},
+								Range: Range{
+									From: Position{
+										Index: 26,
+										Line:  1,
+										Col:   14,
+									},
+									To: Position{
+										Index: 47,
+										Line:  1,
+										Col:   35,
+									},
+								},
 							},
 							Whitespace{Value: " "},
-							Text{Value: "Home"},
+							Text{
+								Value: "Home",
+								Range: Range{
+									From: Position{
+										Index: 48,
+										Line:  1,
+										Col:   36,
+									},
+									To: Position{
+										Index: 52,
+										Line:  1,
+										Col:   40,
+									},
+								},
+							},
 						},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 13,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 56,
+								Line:  1,
+								Col:   44,
+							},
+						},
 					},
 				},
 			},
@@ -492,7 +502,7 @@ func TestTemplateParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\t"},
-					GoComment{Contents: " Comment", Multiline: false},
+					GoComment{Contents: " Comment", Multiline: false, Range: Range{From: Position{Index: 13, Line: 1, Col: 1}, To: Position{Index: 23, Line: 1, Col: 11}}},
 				},
 			},
 		},
@@ -511,7 +521,7 @@ func TestTemplateParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\t"},
-					GoComment{Contents: " Comment ", Multiline: true},
+					GoComment{Contents: " Comment ", Multiline: true, Range: Range{From: Position{Index: 13, Line: 1, Col: 1}, To: Position{Index: 26, Line: 1, Col: 14}}},
 					Whitespace{Value: "\n"},
 				},
 			},
@@ -533,7 +543,7 @@ func TestTemplateParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\t"},
-					GoComment{Contents: " Line 1\n\t\t Line 2\n\t", Multiline: true},
+					GoComment{Contents: " Line 1\n\t\t Line 2\n\t", Multiline: true, Range: Range{From: Position{Index: 13, Line: 1, Col: 1}, To: Position{Index: 36, Line: 3, Col: 3}}},
 					Whitespace{Value: "\n"},
 				},
 			},
@@ -556,13 +566,77 @@ func TestTemplateParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\t"},
-					HTMLComment{Contents: " Single line "},
+					HTMLComment{
+						Contents: " Single line ",
+						Range: Range{
+							From: Position{Index: 13, Line: 1, Col: 1},
+							To:   Position{Index: 33, Line: 1, Col: 21},
+						},
+					},
 					Whitespace{Value: "\n\t"},
-					HTMLComment{Contents: " \n\t\tMultiline\n\t"},
+					HTMLComment{
+						Contents: " \n\t\tMultiline\n\t",
+						Range: Range{
+							From: Position{Index: 35, Line: 2, Col: 1},
+							To:   Position{Index: 57, Line: 4, Col: 4},
+						},
+					},
 					Whitespace{Value: "\n"},
 				},
 			},
 		},
+		{
+			name: "template: comment end sequence within an attribute is not a comment",
+			input: `templ x() {
+	<div title="-->"></div>
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "x()",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 9, Line: 0, Col: 9},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t"},
+					Element{
+						Name: "div",
+						Attributes: []Attribute{
+							ConstantAttribute{
+								Name:  "title",
+								Value: "-->",
+								Range: Range{
+									From: Position{
+										Index: 18,
+										Line:  1,
+										Col:   6,
+									},
+									To: Position{
+										Index: 29,
+										Line:  1,
+										Col:   17,
+									},
+								},
+							},
+						},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 13,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 36,
+								Line:  1,
+								Col:   24,
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "template: containing spread attributes and children expression",
 			input: `templ Name(children templ.Attributes) {
@@ -591,7 +665,7 @@ func TestTemplateParser(t *testing.T) {
 					Element{
 						Name: "span",
 						Attributes: []Attribute{SpreadAttributes{
-							Expression{
+							Expression: Expression{
 								Value: "children",
 								Range: Range{
 									From: Position{
@@ -606,14 +680,35 @@ func TestTemplateParser(t *testing.T) {
 									},
 								},
 							},
+							Range: Range{
+								From: Position{Index: 48, Line: 1, Col: 8},
+								To:   Position{Index: 63, Line: 1, Col: 23},
+							},
 						}},
 						Children: []Node{
-							Whitespace{"\n\t\t\t"},
-							ChildrenExpression{},
+							Whitespace{Value: "\n\t\t\t"},
+							ChildrenExpression{
+								Range: Range{
+									From: Position{Index: 68, Line: 2, Col: 3},
+									To:   Position{Index: 83, Line: 2, Col: 18},
+								},
+							},
 							Whitespace{Value: "\n\t\t"},
 						},
 						IndentChildren: true,
 						TrailingSpace:  SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 42,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 93,
+								Line:  3,
+								Col:   9,
+							},
+						},
 					},
 				},
 			},
@@ -652,12 +747,18 @@ func TestTemplateParserErrors(t *testing.T) {
 }`,
 			expected: "<span>: malformed open element: line 2, col 0",
 		},
+		{
+			name: "template: missing closing brace",
+			input: `templ Name(p Parameter) {
+	<span>{ p.Name }</span>`,
+			expected: "template closing brace not found",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			input := parse.NewInput(tt.input)
-			_, ok, err := template.Parse(input)
+			result, ok, err := template.Parse(input)
 			if err == nil {
 				t.Fatalf("expected error %q, got nil", tt.expected)
 			}
@@ -667,6 +768,50 @@ func TestTemplateParserErrors(t *testing.T) {
 			if diff := cmp.Diff(tt.expected, err.Error()); diff != "" {
 				t.Errorf(diff)
 			}
+			// A partially parsed template is not returned.
+			if diff := cmp.Diff(HTMLTemplate{}, result); diff != "" {
+				t.Errorf("expected an empty template on error:\n%s", diff)
+			}
 		})
 	}
 }
+
+// benchmarkTemplateSection is repeated to create a large template for BenchmarkTemplateParser.
+const benchmarkTemplateSection = `	<section class="products" id="products">
+		<h2>Products</h2>
+		<p>
+			Showing { strconv.Itoa(len(products)) } products, sorted by <strong>price</strong>.
+		</p>
+		<ul class="product-list">
+			for _, p := range products {
+				<li class={ "product", templ.KV("sale", p.OnSale) } data-id={ p.ID }>
+					<a href={ templ.URL("/products/" + p.ID) }>{ p.Name }</a>
+					if p.OnSale {
+						<span class="price sale">{ p.SalePrice }</span>
+					} else {
+						<span class="price">{ p.Price }</span>
+					}
+					<button type="button" disabled?={ !p.InStock }>Add to cart</button>
+				</li>
+			}
+		</ul>
+		@pagination(page, pageCount)
+		<!-- footer -->
+		<footer>
+			<input type="text" name="q" placeholder="Search"/>
+		</footer>
+	</section>
+`
+
+func BenchmarkTemplateParser(b *testing.B) {
+	input := "templ products(products []Product, page, pageCount int) {\n" +
+		strings.Repeat(benchmarkTemplateSection, 10) + "}\n"
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		_, ok, err := template.Parse(parse.NewInput(input))
+		if err != nil || !ok {
+			b.Fatalf("failed to parse template: %v", err)
+		}
+	}
+}
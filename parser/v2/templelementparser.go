@@ -9,9 +9,11 @@ type templElementExpressionParser struct{}
 
 func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	// Check the prefix first.
-	if _, ok, err = parse.Rune('@').Parse(pi); err != nil || !ok {
-		return
+	start := pi.Index()
+	if !peekPrefix(pi, "@") {
+		return n, false, nil
 	}
+	pi.Take(len("@"))
 
 	var r TemplElementExpression
 	// Parse the Go expresion.
@@ -26,6 +28,7 @@ func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, e
 		return
 	}
 	if !hasOpenBrace {
+		r.Range = sourceRange(pi, start)
 		return r, true, nil
 	}
 
@@ -46,6 +49,7 @@ func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, e
 		err = parse.Error("@"+r.Expression.Value+": missing end (expected '}')", pi.Position())
 		return
 	}
+	r.Range = sourceRange(pi, start)
 
 	return r, true, nil
 }
@@ -32,6 +32,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 14,
+						Line:  0,
+						Col:   14,
+					},
+				},
 			},
 		},
 		{
@@ -53,6 +65,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 24,
+						Line:  0,
+						Col:   24,
+					},
+				},
 			},
 		},
 		{
@@ -80,6 +104,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 60,
+						Line:  3,
+						Col:   4,
+					},
+				},
 			},
 		},
 		{
@@ -105,8 +141,33 @@ func TestTemplElementExpressionParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\n\t"},
-					Text{Value: "some words",
+					Text{
+						Value:         "some words",
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 18,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 28,
+								Line:  1,
+								Col:   11,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 30,
+						Line:  2,
+						Col:   1,
 					},
 				},
 			},
@@ -134,13 +195,51 @@ func TestTemplElementExpressionParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\n\t\t\t"},
-					Element{Name: "a", Attributes: []Attribute{
-						ConstantAttribute{
-							Name:  "href",
-							Value: "someurl",
+					Element{
+						Name: "a",
+						Attributes: []Attribute{
+							ConstantAttribute{
+								Name:  "href",
+								Value: "someurl",
+								Range: Range{
+									From: Position{
+										Index: 22,
+										Line:  1,
+										Col:   6,
+									},
+									To: Position{
+										Index: 36,
+										Line:  1,
+										Col:   20,
+									},
+								},
+							},
 						},
-					},
 						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{
+								Index: 19,
+								Line:  1,
+								Col:   3,
+							},
+							To: Position{
+								Index: 39,
+								Line:  1,
+								Col:   23,
+							},
+						},
+					},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 43,
+						Line:  2,
+						Col:   3,
 					},
 				},
 			},
@@ -176,9 +275,123 @@ func TestTemplElementExpressionParser(t *testing.T) {
 								To:   Position{28, 1, 11},
 							},
 						},
+						Range: Range{
+							From: Position{
+								Index: 21,
+								Line:  1,
+								Col:   4,
+							},
+							To: Position{
+								Index: 28,
+								Line:  1,
+								Col:   11,
+							},
+						},
 					},
 					Whitespace{Value: "\n\t\t\t"},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 33,
+						Line:  2,
+						Col:   4,
+					},
+				},
+			},
+		},
+		{
+			name: "templelement: nested blocks",
+			input: `@Layout() {
+	@Card() {
+		<p>x</p>
+	}
+}`,
+			expected: TemplElementExpression{
+				Expression: Expression{
+					Value: "Layout()",
+					Range: Range{
+						From: Position{1, 0, 1},
+						To:   Position{9, 0, 9},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\n\t"},
+					TemplElementExpression{
+						Expression: Expression{
+							Value: "Card()",
+							Range: Range{
+								From: Position{14, 1, 2},
+								To:   Position{20, 1, 8},
+							},
+						},
+						Children: []Node{
+							Whitespace{Value: "\n\t\t"},
+							Element{
+								Name: "p",
+								Children: []Node{
+									Text{
+										Value: "x",
+										Range: Range{
+											From: Position{
+												Index: 28,
+												Line:  2,
+												Col:   5,
+											},
+											To: Position{
+												Index: 29,
+												Line:  2,
+												Col:   6,
+											},
+										},
+									},
+								},
+								TrailingSpace: SpaceVertical,
+								Range: Range{
+									From: Position{
+										Index: 25,
+										Line:  2,
+										Col:   2,
+									},
+									To: Position{
+										Index: 33,
+										Line:  2,
+										Col:   10,
+									},
+								},
+							},
+						},
+						Range: Range{
+							From: Position{
+								Index: 13,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 36,
+								Line:  3,
+								Col:   2,
+							},
+						},
+					},
+					Whitespace{Value: "\n"},
+				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 38,
+						Line:  4,
+						Col:   1,
+					},
+				},
 			},
 		},
 		{
@@ -201,6 +414,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 21,
+						Line:  0,
+						Col:   21,
+					},
+				},
 			},
 		},
 		{
@@ -222,6 +447,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 31,
+						Line:  0,
+						Col:   31,
+					},
+				},
 			},
 		},
 		{
@@ -243,6 +480,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 31,
+						Line:  0,
+						Col:   31,
+					},
+				},
 			},
 		},
 		{
@@ -264,6 +513,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 15,
+						Line:  0,
+						Col:   15,
+					},
+				},
 			},
 		},
 		{
@@ -285,6 +546,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 19,
+						Line:  0,
+						Col:   19,
+					},
+				},
 			},
 		},
 		{
@@ -306,6 +579,18 @@ func TestTemplElementExpressionParser(t *testing.T) {
 						},
 					},
 				},
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 30,
+						Line:  0,
+						Col:   30,
+					},
+				},
 			},
 		},
 	}
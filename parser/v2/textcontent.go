@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"html"
+	"strings"
+)
+
+// TextContent returns the text within the node, like the DOM's textContent, for search indexing
+// or tests. The text of elements and of every branch of if, switch and for expressions is joined
+// in order. Entities are decoded, and whitespace is kept as it's written, as it is by the DOM,
+// e.g. the newline and indentation between two elements. The parser only records whether the
+// whitespace after a node is a space or a newline, so that's written for the node's
+// TrailingSpace.
+//
+// Expressions that output content at render time, e.g. `{ name }` or `@component()`, are written
+// as the placeholder, which may be empty to skip them. Comments, and the contents of <script> and
+// <style> elements, aren't text, so they're skipped.
+func TextContent(n Node, placeholder string) string {
+	var sb strings.Builder
+	writeTextContent(&sb, n, placeholder)
+	return sb.String()
+}
+
+func writeTextContent(sb *strings.Builder, node Node, placeholder string) {
+	switch n := node.(type) {
+	case Text:
+		if n.Unescaped {
+			sb.WriteString(n.Value)
+		} else {
+			sb.WriteString(html.UnescapeString(n.Value))
+		}
+		sb.WriteString(string(n.TrailingSpace))
+		return
+	case Whitespace:
+		sb.WriteString(n.Value)
+		return
+	case CData:
+		sb.WriteString(n.Value)
+		return
+	case Element:
+		if n.Ignored {
+			return
+		}
+		for _, child := range n.Children {
+			writeTextContent(sb, child, placeholder)
+		}
+		sb.WriteString(string(n.TrailingSpace))
+		return
+	case StringExpression:
+		sb.WriteString(placeholder)
+		sb.WriteString(string(n.TrailingSpace))
+		return
+	case InlineIfExpression:
+		sb.WriteString(placeholder)
+		sb.WriteString(string(n.TrailingSpace))
+		return
+	case TranslationExpression:
+		sb.WriteString(placeholder)
+		sb.WriteString(string(n.TrailingSpace))
+		return
+	case TemplElementExpression, CallTemplateExpression, ChildrenExpression:
+		sb.WriteString(placeholder)
+		return
+	case RawElement, HTMLComment, GoComment:
+		return
+	}
+	for _, child := range ChildNodes(node) {
+		writeTextContent(sb, child, placeholder)
+	}
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTextContent(t *testing.T) {
+	var tests = []struct {
+		name        string
+		input       string
+		placeholder string
+		expected    string
+	}{
+		{
+			name:     "nested elements",
+			input:    `<div><h1>Title</h1><p>Some <b>bold</b> text</p></div>`,
+			expected: "TitleSome bold text",
+		},
+		{
+			name: "whitespace is kept",
+			input: `<ul>
+	<li>One</li>
+	<li>Two</li>
+</ul>`,
+			expected: "\n\tOne\nTwo\n",
+		},
+		{
+			name:     "whitespace within preformatted text is kept",
+			input:    "<pre>a\n  b  c</pre>",
+			expected: "a\n  b  c",
+		},
+		{
+			name:     "entities are decoded",
+			input:    `<p>Fish &amp; chips</p>`,
+			expected: "Fish & chips",
+		},
+		{
+			name: "both branches of an if expression",
+			input: `<div>
+	if ok {
+		<p>Yes</p>
+	} else {
+		<p>No</p>
+	}
+</div>`,
+			expected: "\n\t\t\tYes\nNo\n\n",
+		},
+		{
+			name: "expressions are written as the placeholder",
+			input: `<p>
+	Hello, { name }!
+	@icon("star")
+</p>`,
+			placeholder: "…",
+			expected:    "\n\tHello, …!\n…\n",
+		},
+		{
+			name:     "expressions are skipped without a placeholder",
+			input:    `<p>Hello, { name }!</p>`,
+			expected: "Hello, !",
+		},
+		{
+			name:     "scripts, styles and comments are skipped",
+			input:    `<div><!-- comment --><script>var x = 1;</script><style>p { color: red; }</style>Text</div>`,
+			expected: "Text",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok, err := element.Parse(parse.NewInput(tt.input))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse element: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, TextContent(result, tt.placeholder)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"io"
+	"strings"
+
+	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
+)
+
+// textTemplate parses a template that outputs text instead of HTML, e.g. a config file or SQL.
+// The body isn't parsed as HTML, so it can contain any text, including angle brackets, and
+// only `{ expr }` is interpolated. A single tab of indentation is removed from each line of
+// the body. The template ends at a closing brace at the start of a line.
+var textTemplate = parse.Func(func(pi *parse.Input) (r HTMLTemplate, ok bool, err error) {
+	// templ FuncName(p Person, other Other) {
+	var te templateExpression
+	if te, ok, err = templateExpressionParser.Parse(pi); err != nil || !ok {
+		return
+	}
+	r.Expression = te.Expression
+	r.TextMode = true
+
+	for {
+		if _, ok = pi.Peek(1); !ok {
+			err = parse.Error("templ: "+unterminatedMissingEnd, pi.Position())
+			return HTMLTemplate{}, false, err
+		}
+		if pi.Position().Col == 0 && peekPrefix(pi, "}") {
+			pi.Take(1)
+			return r, true, nil
+		}
+		if peekPrefix(pi, "{") {
+			var se StringExpression
+			if se, err = parseTextModeExpression(pi); err != nil {
+				return HTMLTemplate{}, false, err
+			}
+			r.Children = append(r.Children, se)
+			continue
+		}
+		// Read text up to the next expression, or the end of the line.
+		atLineStart := pi.Position().Col == 0
+		from := pi.Position()
+		var text string
+		if text, _, err = parse.StringUntil(parse.Any(parse.Rune('{'), parse.Rune('\n'))).Parse(pi); err != nil {
+			return HTMLTemplate{}, false, err
+		}
+		if newLine, isNewLine := pi.Peek(1); isNewLine && newLine == "\n" {
+			text += newLine
+			pi.Take(1)
+		}
+		if text == "" {
+			// The remaining input has no expression or new line, so the template is unterminated.
+			err = parse.Error("templ: "+unterminatedMissingEnd, from)
+			return HTMLTemplate{}, false, err
+		}
+		if atLineStart {
+			text = strings.TrimPrefix(text, "\t")
+		}
+		if last := len(r.Children) - 1; last >= 0 {
+			if t, isText := r.Children[last].(Text); isText {
+				t.Value += text
+				r.Children[last] = t
+				continue
+			}
+		}
+		r.Children = append(r.Children, Text{Value: text})
+	}
+})
+
+func parseTextModeExpression(pi *parse.Input) (se StringExpression, err error) {
+	pi.Take(len("{"))
+	_, _, _ = optionalSpaces.Parse(pi)
+	if se.Expression, err = parseGo("text template expression", pi, goexpression.Expression); err != nil {
+		return se, err
+	}
+	if _, ok, err := closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		return se, parse.Error("text template expression: missing closing brace", pi.Position())
+	}
+	return se, nil
+}
+
+// writeTextModeBody writes the body of a TextMode template, restoring the indentation that was
+// removed when it was parsed.
+func writeTextModeBody(w io.Writer, nodes []Node) (err error) {
+	atLineStart := true
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case Text:
+			lines := strings.SplitAfter(n.Value, "\n")
+			for _, line := range lines {
+				if line == "" {
+					continue
+				}
+				if atLineStart && line != "\n" {
+					line = "\t" + line
+				}
+				if _, err = io.WriteString(w, line); err != nil {
+					return err
+				}
+				atLineStart = strings.HasSuffix(line, "\n")
+			}
+		case StringExpression:
+			if atLineStart {
+				if _, err = io.WriteString(w, "\t"); err != nil {
+					return err
+				}
+			}
+			if _, err = io.WriteString(w, "{ "+n.Expression.Value+" }"); err != nil {
+				return err
+			}
+			atLineStart = false
+		}
+	}
+	return nil
+}
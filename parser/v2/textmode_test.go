@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTextModeTemplate(t *testing.T) {
+	input := `package queries
+
+templ activeUsers(table string, minAge int) {
+	SELECT * FROM { table }
+	WHERE age >= { strconv.Itoa(minAge) } AND status <> 'deleted'
+	  AND <name> IS NOT NULL;
+}
+`
+	p := NewTemplateFileParser("main")
+	p.TextMode = true
+	tf, ok, err := p.Parse(parse.NewInput(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected the file to be parsed")
+	}
+	if len(tf.Nodes) != 1 {
+		t.Fatalf("expected 1 node, got %d nodes with content %+v", len(tf.Nodes), tf.Nodes)
+	}
+	tem, ok := tf.Nodes[0].(HTMLTemplate)
+	if !ok {
+		t.Fatalf("expected a template, got %T", tf.Nodes[0])
+	}
+	if !tem.TextMode {
+		t.Error("expected the template to be in text mode")
+	}
+	expected := []Node{
+		Text{Value: "SELECT * FROM "},
+		StringExpression{
+			Expression: Expression{
+				Value: "table",
+				Range: Range{
+					From: Position{Index: 80, Line: 3, Col: 17},
+					To:   Position{Index: 85, Line: 3, Col: 22},
+				},
+			},
+		},
+		Text{Value: "\nWHERE age >= "},
+		StringExpression{
+			Expression: Expression{
+				Value: "strconv.Itoa(minAge)",
+				Range: Range{
+					From: Position{Index: 104, Line: 4, Col: 16},
+					To:   Position{Index: 124, Line: 4, Col: 36},
+				},
+			},
+		},
+		Text{Value: " AND status <> 'deleted'\n  AND <name> IS NOT NULL;\n"},
+	}
+	if diff := cmp.Diff(expected, tem.Children); diff != "" {
+		t.Error(diff)
+	}
+
+	// Formatting restores the indentation.
+	w := new(strings.Builder)
+	if err := tf.Write(w); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if diff := cmp.Diff(input, w.String()); diff != "" {
+		t.Errorf("unexpected formatted output:\n%s", diff)
+	}
+}
+
+func TestTextModeTemplateErrors(t *testing.T) {
+	p := NewTemplateFileParser("main")
+	p.TextMode = true
+	_, _, err := p.Parse(parse.NewInput("package main\n\ntempl a() {\n\tSELECT 1\n"))
+	if err == nil {
+		t.Error("expected an error for an unterminated template")
+	}
+	tem, _, err := textTemplate.Parse(parse.NewInput("templ a() {\n\tSELECT 1\n"))
+	if err == nil {
+		t.Error("expected an error for an unterminated template")
+	}
+	if diff := cmp.Diff(HTMLTemplate{}, tem); diff != "" {
+		t.Errorf("expected an empty template on error:\n%s", diff)
+	}
+}
@@ -7,13 +7,14 @@ import (
 )
 
 var tagTemplOrNewLine = parse.Any(parse.Rune('<'), parse.Rune('{'), parse.Rune('}'), parse.Rune('\n'))
+var stringUntilTagTemplOrNewLine = parse.StringUntil(tagTemplOrNewLine)
 
 var textParser = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
 	from := pi.Position()
 
 	// Read until a tag or templ expression opens.
 	var t Text
-	if t.Value, ok, err = parse.StringUntil(tagTemplOrNewLine).Parse(pi); err != nil || !ok {
+	if t.Value, ok, err = stringUntilTagTemplOrNewLine.Parse(pi); err != nil || !ok {
 		return
 	}
 	if isWhitespace(t.Value) {
@@ -23,6 +24,7 @@ var textParser = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
 		err = parse.Error("textParser: unterminated text, expected tag open, templ expression open, or newline", from)
 		return
 	}
+	t.Range = NewRange(from, pi.Position())
 
 	// Parse trailing whitespace.
 	ws, _, err := parse.Whitespace.Parse(pi)
@@ -18,6 +18,18 @@ func TestTextParser(t *testing.T) {
 			input: `abcdef<a href="https://example.com">More</a>`,
 			expected: Text{
 				Value: "abcdef",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 6,
+						Line:  0,
+						Col:   6,
+					},
+				},
 			},
 		},
 		{
@@ -25,6 +37,18 @@ func TestTextParser(t *testing.T) {
 			input: `abcdef{%= "test" %}`,
 			expected: Text{
 				Value: "abcdef",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 6,
+						Line:  0,
+						Col:   6,
+					},
+				},
 			},
 		},
 		{
@@ -32,6 +56,18 @@ func TestTextParser(t *testing.T) {
 			input: `abcdef ghijk{%= "test" %}`,
 			expected: Text{
 				Value: "abcdef ghijk",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 12,
+						Line:  0,
+						Col:   12,
+					},
+				},
 			},
 		},
 		{
@@ -39,6 +75,18 @@ func TestTextParser(t *testing.T) {
 			input: `abcdef&nbsp;ghijk{%= "test" %}`,
 			expected: Text{
 				Value: "abcdef&nbsp;ghijk",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  0,
+						Col:   17,
+					},
+				},
 			},
 		},
 		{
@@ -46,6 +94,18 @@ func TestTextParser(t *testing.T) {
 			input: `abcdef&#32;ghijk{%= "test" %}`,
 			expected: Text{
 				Value: "abcdef&#32;ghijk",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 16,
+						Line:  0,
+						Col:   16,
+					},
+				},
 			},
 		},
 		{
@@ -53,6 +113,18 @@ func TestTextParser(t *testing.T) {
 			input: `abcdef&#x20;ghijk{%= "test" %}`,
 			expected: Text{
 				Value: "abcdef&#x20;ghijk",
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  0,
+						Col:   17,
+					},
+				},
 			},
 		},
 	}
@@ -0,0 +1,159 @@
+package parser
+
+// Transform returns a copy of the nodes, where each node, including those nested within other
+// nodes, is replaced with the result of fn. Like Walk, the parent is visited before its children,
+// but the children visited are those of the node that fn returns, so fn can replace a node with
+// one of a different type. The nodes passed in aren't changed.
+func Transform(nodes []Node, fn func(n Node) Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	op := make([]Node, len(nodes))
+	for i, n := range nodes {
+		op[i] = mapChildNodeLists(fn(n), func(children []Node) []Node {
+			return Transform(children, fn)
+		})
+	}
+	return op
+}
+
+// mapElements returns a copy of the nodes, where each element, including those nested within
+// other nodes, is replaced with the result of fn. Children are mapped before their parent.
+func mapElements(nodes []Node, fn func(e Element) Element) []Node {
+	if nodes == nil {
+		return nil
+	}
+	op := make([]Node, len(nodes))
+	for i, n := range nodes {
+		op[i] = mapElementsInNode(n, fn)
+	}
+	return op
+}
+
+func mapElementsInNode(node Node, fn func(e Element) Element) Node {
+	switch n := node.(type) {
+	case Element:
+		n.Children = mapElements(n.Children, fn)
+		return fn(n)
+	case TemplElementExpression:
+		n.Children = mapElements(n.Children, fn)
+		return n
+	case OnceExpression:
+		n.Children = mapElements(n.Children, fn)
+		return n
+	case FragmentBlock:
+		n.Children = mapElements(n.Children, fn)
+		return n
+	case IfExpression:
+		n.Then = mapElements(n.Then, fn)
+		if n.ElseIfs != nil {
+			elseIfs := make([]ElseIfExpression, len(n.ElseIfs))
+			for i, elseIf := range n.ElseIfs {
+				elseIf.Then = mapElements(elseIf.Then, fn)
+				elseIfs[i] = elseIf
+			}
+			n.ElseIfs = elseIfs
+		}
+		n.Else = mapElements(n.Else, fn)
+		return n
+	case SwitchExpression:
+		if n.Cases != nil {
+			cases := make([]CaseExpression, len(n.Cases))
+			for i, c := range n.Cases {
+				c.Children = mapElements(c.Children, fn)
+				cases[i] = c
+			}
+			n.Cases = cases
+		}
+		return n
+	case ForExpression:
+		n.Children = mapElements(n.Children, fn)
+		return n
+	}
+	return node
+}
+
+// mapNodeLists returns a copy of the nodes, where every list of nodes, including the top level
+// list and the children of elements, if, switch and for expressions, is replaced with the result
+// of fn. Nested lists are mapped before the list that contains them.
+func mapNodeLists(nodes []Node, fn func(nodes []Node) []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	op := make([]Node, len(nodes))
+	for i, n := range nodes {
+		op[i] = mapNodeListsInNode(n, fn)
+	}
+	return fn(op)
+}
+
+func mapNodeListsInNode(node Node, fn func(nodes []Node) []Node) Node {
+	return mapChildNodeLists(node, func(nodes []Node) []Node {
+		return mapNodeLists(nodes, fn)
+	})
+}
+
+// mapChildNodeLists returns a copy of the node, where each list of nodes directly within it, e.g.
+// the children of an element, or each branch of an if expression, is replaced with the result of
+// fn. Unlike mapNodeLists, the lists within those lists aren't mapped.
+func mapChildNodeLists(node Node, fn func(nodes []Node) []Node) Node {
+	switch n := node.(type) {
+	case Element:
+		n.Children = fn(n.Children)
+		return n
+	case TemplElementExpression:
+		n.Children = fn(n.Children)
+		return n
+	case OnceExpression:
+		n.Children = fn(n.Children)
+		return n
+	case FragmentBlock:
+		n.Children = fn(n.Children)
+		return n
+	case IfExpression:
+		n.Then = fn(n.Then)
+		if n.ElseIfs != nil {
+			elseIfs := make([]ElseIfExpression, len(n.ElseIfs))
+			for i, elseIf := range n.ElseIfs {
+				elseIf.Then = fn(elseIf.Then)
+				elseIfs[i] = elseIf
+			}
+			n.ElseIfs = elseIfs
+		}
+		n.Else = fn(n.Else)
+		return n
+	case SwitchExpression:
+		if n.Cases != nil {
+			cases := make([]CaseExpression, len(n.Cases))
+			for i, c := range n.Cases {
+				c.Children = fn(c.Children)
+				cases[i] = c
+			}
+			n.Cases = cases
+		}
+		return n
+	case ForExpression:
+		n.Children = fn(n.Children)
+		return n
+	}
+	return node
+}
+
+// mapAttributes returns a copy of the attributes, where each attribute, including those within
+// conditional attributes, is replaced with the result of fn.
+func mapAttributes(attrs []Attribute, fn func(a Attribute) Attribute) []Attribute {
+	if attrs == nil {
+		return nil
+	}
+	op := make([]Attribute, len(attrs))
+	for i, a := range attrs {
+		if ca, ok := a.(ConditionalAttribute); ok {
+			ca.Then = mapAttributes(ca.Then, fn)
+			ca.Else = mapAttributes(ca.Else, fn)
+			op[i] = ca
+			continue
+		}
+		op[i] = fn(a)
+	}
+	return op
+}
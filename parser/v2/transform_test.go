@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTransform(t *testing.T) {
+	input := parse.NewInput(`templ nav(items []string) {
+	<nav>
+		<a href="/">Home</a>
+		for _, item := range items {
+			<a href={ templ.URL(item) } class="item">{ item }</a>
+		}
+		<span>Text</span>
+	</nav>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	original := CloneNodes(tem.Children)
+
+	t.Run("can add an attribute to every matching element", func(t *testing.T) {
+		transformed := Transform(tem.Children, func(n Node) Node {
+			if e, ok := n.(Element); ok && e.Name == "a" {
+				e.SetAttr("data-link", "true")
+				return e
+			}
+			return n
+		})
+		var links, others int
+		Walk(transformed, func(n Node) bool {
+			e, ok := n.(Element)
+			if !ok {
+				return true
+			}
+			_, hasAttr := e.Attr("data-link")
+			switch {
+			case e.Name == "a" && hasAttr:
+				links++
+			case e.Name == "a":
+				t.Errorf("expected the data-link attribute on %v", e.Attributes)
+			case hasAttr:
+				t.Errorf("unexpected data-link attribute on <%s>", e.Name)
+			default:
+				others++
+			}
+			return true
+		})
+		if links != 2 {
+			t.Errorf("expected 2 links, got %d", links)
+		}
+		if others != 2 {
+			t.Errorf("expected the <nav> and <span> to be unchanged, got %d", others)
+		}
+		if diff := cmp.Diff(original, tem.Children); diff != "" {
+			t.Errorf("expected the input nodes to be unchanged:\n%s", diff)
+		}
+	})
+	t.Run("returning the node unchanged leaves the tree as-is", func(t *testing.T) {
+		transformed := Transform(tem.Children, func(n Node) Node { return n })
+		if diff := cmp.Diff(tem.Children, transformed); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("can replace a node with a different type", func(t *testing.T) {
+		transformed := Transform(tem.Children, func(n Node) Node {
+			if e, ok := n.(Element); ok && e.Name == "span" {
+				return Text{Value: "Replaced"}
+			}
+			return n
+		})
+		var w strings.Builder
+		for _, n := range transformed {
+			if err := n.Write(&w, 0); err != nil {
+				t.Fatalf("failed to write node: %v", err)
+			}
+		}
+		if strings.Contains(w.String(), "<span>") {
+			t.Errorf("expected the <span> to be replaced, got:\n%s", w.String())
+		}
+		if !strings.Contains(w.String(), "Replaced") {
+			t.Errorf("expected the replacement text, got:\n%s", w.String())
+		}
+	})
+	t.Run("visits the children of the returned node", func(t *testing.T) {
+		var visited []string
+		Transform(tem.Children, func(n Node) Node {
+			if e, ok := n.(Element); ok {
+				visited = append(visited, e.Name)
+				if e.Name == "span" {
+					return Element{Name: "b", Children: []Node{Element{Name: "i"}}}
+				}
+			}
+			return n
+		})
+		expected := []string{"nav", "a", "a", "span", "i"}
+		if diff := cmp.Diff(expected, visited); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
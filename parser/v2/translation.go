@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+)
+
+// TranslationKeys returns the sorted, unique message keys of the i18n expressions in the
+// template, e.g. to build a message catalog. Keys that aren't string literals can't be known
+// until the template is rendered, so they're not included.
+func (t HTMLTemplate) TranslationKeys() (keys []string) {
+	seen := map[string]struct{}{}
+	mapNodeLists(t.Children, func(nodes []Node) []Node {
+		for _, n := range nodes {
+			te, ok := n.(TranslationExpression)
+			if !ok {
+				continue
+			}
+			key, err := strconv.Unquote(te.Key.Value)
+			if err != nil {
+				continue
+			}
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+		return nodes
+	})
+	sort.Strings(keys)
+	return keys
+}
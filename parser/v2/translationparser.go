@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
+)
+
+var translationExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
+
+	// Check the prefix first.
+	if _, ok, err = openBraceWithOptionalSpace.Parse(pi); err != nil || !ok {
+		return
+	}
+	if !peekPrefix(pi, "i18n(") {
+		pi.Seek(start)
+		return n, false, nil
+	}
+
+	// Parse the call. If the call is part of a larger expression, e.g. `{ i18n("a") + "b" }`,
+	// it's left to be parsed as a string expression.
+	from := pi.Index()
+	src, _ := pi.Peek(-1)
+	args, end, err := goexpression.Call(src)
+	if err != nil {
+		pi.Seek(start)
+		return n, false, nil
+	}
+	pi.Take(end)
+	_, _, _ = parse.OptionalWhitespace.Parse(pi)
+	if _, ok, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return n, false, nil
+	}
+	if len(args) == 0 {
+		return n, false, parse.Error("i18n: expected a message key", pi.PositionAt(from))
+	}
+	var r TranslationExpression
+	for i, arg := range args {
+		e := NewExpression(src[arg.Start:arg.End], pi.PositionAt(from+arg.Start), pi.PositionAt(from+arg.End))
+		if i == 0 {
+			r.Key = e
+			continue
+		}
+		r.Args = append(r.Args, e)
+	}
+
+	// Parse trailing whitespace.
+	ws, _, err := parse.Whitespace.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	r.TrailingSpace, err = NewTrailingSpace(ws)
+	if err != nil {
+		return r, false, err
+	}
+
+	return r, true, nil
+})
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTranslationExpressionParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected TranslationExpression
+	}{
+		{
+			name:  "i18n: key only",
+			input: `{ i18n("greeting") }`,
+			expected: TranslationExpression{
+				Key: Expression{
+					Value: `"greeting"`,
+					Range: Range{
+						From: Position{Index: 7, Line: 0, Col: 7},
+						To:   Position{Index: 17, Line: 0, Col: 17},
+					},
+				},
+			},
+		},
+		{
+			name:  "i18n: key and args, with trailing space",
+			input: `{i18n("items", count, user.Name)} `,
+			expected: TranslationExpression{
+				Key: Expression{
+					Value: `"items"`,
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 13, Line: 0, Col: 13},
+					},
+				},
+				Args: []Expression{
+					{
+						Value: "count",
+						Range: Range{
+							From: Position{Index: 15, Line: 0, Col: 15},
+							To:   Position{Index: 20, Line: 0, Col: 20},
+						},
+					},
+					{
+						Value: "user.Name",
+						Range: Range{
+							From: Position{Index: 22, Line: 0, Col: 22},
+							To:   Position{Index: 31, Line: 0, Col: 31},
+						},
+					},
+				},
+				TrailingSpace: SpaceHorizontal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := translationExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTranslationExpressionParserDoesNotMatchOtherExpressions(t *testing.T) {
+	for _, input := range []string{`{ i18n("a") + "b" }`, `{ i18nKey }`, `{ name }`} {
+		pi := parse.NewInput(input)
+		_, ok, err := translationExpression.Parse(pi)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", input, err)
+		}
+		if ok {
+			t.Errorf("%s: expected no match", input)
+		}
+		if pi.Index() != 0 {
+			t.Errorf("%s: expected the input not to be consumed, got index %d", input, pi.Index())
+		}
+	}
+}
+
+func TestTranslationKeys(t *testing.T) {
+	input := parse.NewInput(`templ Greeting(name string, key string) {
+	<h1>{ i18n("greeting", name) }</h1>
+	if name == "" {
+		<p>{ i18n("anonymous") }</p>
+	}
+	<p>{ i18n(key) } { i18n("greeting", name) }</p>
+	<footer>{ i18n("about") }</footer>
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", input.Index())
+	}
+	if diff := cmp.Diff([]string{"about", "anonymous", "greeting"}, tem.TranslationKeys()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// trimWhitespace removes the whitespace around string expressions with trim markers, e.g.
+// `{- name -}`. Whitespace within elements where it's significant, e.g. <pre>, is kept, see
+// Element.PreserveWhitespace.
+func trimWhitespace(nodes []Node) []Node {
+	if !hasTrimMarkers(nodes) {
+		return nodes
+	}
+	return trimWhitespaceInList(nodes)
+}
+
+func hasTrimMarkers(nodes []Node) (ok bool) {
+	Walk(nodes, func(n Node) bool {
+		if se, isStringExpression := n.(StringExpression); isStringExpression && (se.TrimLeft || se.TrimRight) {
+			ok = true
+		}
+		return !ok
+	})
+	return ok
+}
+
+func trimWhitespaceInList(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	op := make([]Node, 0, len(nodes))
+	var trimNext bool
+	for _, n := range nodes {
+		if _, isWhitespace := n.(Whitespace); isWhitespace && trimNext {
+			continue
+		}
+		trimNext = false
+		if e, isElement := n.(Element); isElement && e.PreserveWhitespace {
+			op = append(op, n)
+			continue
+		}
+		if se, isStringExpression := n.(StringExpression); isStringExpression {
+			if se.TrimLeft && len(op) > 0 {
+				if _, isWhitespace := op[len(op)-1].(Whitespace); isWhitespace {
+					op = op[:len(op)-1]
+				}
+				if len(op) > 0 {
+					op[len(op)-1] = withoutTrailingSpace(op[len(op)-1])
+				}
+			}
+			if se.TrimRight {
+				se.TrailingSpace = SpaceNone
+				trimNext = true
+			}
+			n = se
+		}
+		op = append(op, mapChildNodeLists(n, trimWhitespaceInList))
+	}
+	return op
+}
+
+// withoutTrailingSpace removes the trailing space of the node, including any whitespace at the
+// end of text, e.g. `Hello ` within `Hello {- name }`.
+func withoutTrailingSpace(node Node) Node {
+	switch n := node.(type) {
+	case Element:
+		n.TrailingSpace = SpaceNone
+		return n
+	case Text:
+		n.Value = strings.TrimRightFunc(n.Value, unicode.IsSpace)
+		n.TrailingSpace = SpaceNone
+		return n
+	case GoCode:
+		n.TrailingSpace = SpaceNone
+		return n
+	case StringExpression:
+		n.TrailingSpace = SpaceNone
+		return n
+	case InlineIfExpression:
+		n.TrailingSpace = SpaceNone
+		return n
+	case TranslationExpression:
+		n.TrailingSpace = SpaceNone
+		return n
+	}
+	return node
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTrimWhitespace(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "without trim markers, whitespace is kept",
+			input: `templ x() {
+	<p>Hello, { name } !</p>
+}`,
+			expected: `<p>Hello, [name] !</p>`,
+		},
+		{
+			name: "trim markers remove the whitespace around a string expression",
+			input: `templ x() {
+	<p>Hello, {- name -} !</p>
+}`,
+			expected: `<p>Hello,[name]!</p>`,
+		},
+		{
+			name: "trim left only",
+			input: `templ x() {
+	<p>a {- name } b</p>
+}`,
+			expected: `<p>a[name] b</p>`,
+		},
+		{
+			name: "trim right only",
+			input: `templ x() {
+	<p>a { name -} b</p>
+}`,
+			expected: `<p>a [name]b</p>`,
+		},
+		{
+			name: "whitespace nodes and the trailing space of elements are removed",
+			input: `templ x() {
+	<ul>
+		<li>a</li>
+		{- name -}
+		<li>b</li>
+	</ul>
+}`,
+			expected: `<ul> <li>a</li>[name]<li>b</li> </ul>`,
+		},
+		{
+			name: "trim markers within if expressions",
+			input: `templ x() {
+	if ok {
+		<b>a</b> {- name }
+	}
+}`,
+			expected: `<b>a</b>[name]`,
+		},
+		{
+			name: "whitespace within pre elements is kept",
+			input: `templ x() {
+	<pre>a {- name -} b <b>c {- name -} d</b></pre>
+}`,
+			expected: `<pre>a [name] b <b>c [name] d</b></pre>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tem, ok, err := template.Parse(parse.NewInput(tt.input))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			var opts RenderOptions
+			opts.Placeholder = func(w io.Writer, n Node) error {
+				se, ok := n.(StringExpression)
+				if !ok {
+					return RenderWithOptions(w, ChildNodes(n), opts)
+				}
+				s := "[" + se.Expression.Value + "]"
+				if se.TrailingSpace != SpaceNone {
+					s += " "
+				}
+				_, err := io.WriteString(w, s)
+				return err
+			}
+			var sb strings.Builder
+			err = RenderWithOptions(&sb, tem.Children, opts)
+			if err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, strings.TrimSpace(sb.String())); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
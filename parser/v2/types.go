@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"go/format"
+	"html"
 	"io"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -71,18 +73,7 @@ func NewPosition(index int64, line, col uint32) Position {
 func NewExpression(value string, from, to parse.Position) Expression {
 	return Expression{
 		Value: value,
-		Range: Range{
-			From: Position{
-				Index: int64(from.Index),
-				Line:  uint32(from.Line),
-				Col:   uint32(from.Col),
-			},
-			To: Position{
-				Index: int64(to.Index),
-				Line:  uint32(to.Line),
-				Col:   uint32(to.Col),
-			},
-		},
+		Range: NewRange(from, to),
 	}
 }
 
@@ -92,6 +83,22 @@ type Range struct {
 	To   Position
 }
 
+// NewRange creates a range from parser positions.
+func NewRange(from, to parse.Position) Range {
+	return Range{
+		From: Position{
+			Index: int64(from.Index),
+			Line:  uint32(from.Line),
+			Col:   uint32(from.Col),
+		},
+		To: Position{
+			Index: int64(to.Index),
+			Line:  uint32(to.Line),
+			Col:   uint32(to.Col),
+		},
+	}
+}
+
 // Expression containing Go code.
 type Expression struct {
 	Value string
@@ -216,6 +223,9 @@ func (p Package) Write(w io.Writer, indent int) error {
 // Whitespace.
 type Whitespace struct {
 	Value string
+	// Significant is set by MarkWhitespaceSignificance when the whitespace affects how the
+	// neighbouring nodes are displayed.
+	Significant bool
 }
 
 func (ws Whitespace) IsNode() bool { return true }
@@ -327,6 +337,8 @@ func (c ExpressionCSSProperty) Write(w io.Writer, indent int) error {
 // <!DOCTYPE html>
 type DocType struct {
 	Value string
+	// Range of the doctype in the template, from the `<` to the `>`.
+	Range Range
 }
 
 func (dt DocType) IsNode() bool { return true }
@@ -345,6 +357,9 @@ type HTMLTemplate struct {
 	Diagnostics []Diagnostic
 	Expression  Expression
 	Children    []Node
+	// TextMode is set if the template outputs text instead of HTML, see
+	// TemplateFileParser.TextMode.
+	TextMode bool
 }
 
 func (t HTMLTemplate) IsTemplateFileNode() bool { return true }
@@ -353,6 +368,12 @@ func (t HTMLTemplate) Write(w io.Writer, indent int) error {
 	if err := writeIndent(w, indent, "templ ", t.Expression.Value, " {\n"); err != nil {
 		return err
 	}
+	if t.TextMode {
+		if err := writeTextModeBody(w, t.Children); err != nil {
+			return err
+		}
+		return writeIndent(w, indent, "}")
+	}
 	if err := writeNodesIndented(w, indent+1, t.Children); err != nil {
 		return err
 	}
@@ -370,15 +391,20 @@ const (
 	SpaceNone       TrailingSpace = ""
 	SpaceHorizontal TrailingSpace = " "
 	SpaceVertical   TrailingSpace = "\n"
+	// SpaceVerticalDouble is a blank line, which is kept when the template is formatted to
+	// separate groups of nodes. Any number of blank lines are formatted as a single blank line.
+	SpaceVerticalDouble TrailingSpace = "\n\n"
 )
 
 var ErrNonSpaceCharacter = errors.New("non space character found")
 
 func NewTrailingSpace(s string) (ts TrailingSpace, err error) {
 	var hasHorizontalSpace bool
+	var newLines int
 	for _, r := range s {
 		if r == '\n' {
-			return SpaceVertical, nil
+			newLines++
+			continue
 		}
 		if unicode.IsSpace(r) {
 			hasHorizontalSpace = true
@@ -386,6 +412,12 @@ func NewTrailingSpace(s string) (ts TrailingSpace, err error) {
 		}
 		return ts, ErrNonSpaceCharacter
 	}
+	if newLines > 1 {
+		return SpaceVerticalDouble, nil
+	}
+	if newLines == 1 {
+		return SpaceVertical, nil
+	}
 	if hasHorizontalSpace {
 		return SpaceHorizontal, nil
 	}
@@ -411,15 +443,34 @@ type WhitespaceTrailer interface {
 var (
 	_ WhitespaceTrailer = Element{}
 	_ WhitespaceTrailer = Text{}
+	_ WhitespaceTrailer = GoCode{}
 	_ WhitespaceTrailer = StringExpression{}
+	_ WhitespaceTrailer = TranslationExpression{}
 )
 
 // Text node within the document.
 type Text struct {
-	// Value is the raw HTML encoded value.
+	// Value is the raw HTML encoded value, unless Unescaped is set.
 	Value string
 	// TrailingSpace lists what happens after the text.
 	TrailingSpace TrailingSpace
+	// SignificantTrailingSpace, see MarkWhitespaceSignificance.
+	SignificantTrailingSpace bool
+	// Unescaped is set when the HTML entities in the Value have been decoded, see
+	// TemplateFileParser.UnescapeEntities. The Value is HTML encoded again when it's written.
+	Unescaped bool
+	// Range of the text in the template, not including its trailing whitespace.
+	Range Range
+	// Source is the text as it was in the template, see TemplateFileParser.RetainSource.
+	Source string
+}
+
+// HTML returns the HTML encoded value of the text.
+func (t Text) HTML() string {
+	if t.Unescaped {
+		return html.EscapeString(t.Value)
+	}
+	return t.Value
 }
 
 func (t Text) Trailing() TrailingSpace {
@@ -428,18 +479,39 @@ func (t Text) Trailing() TrailingSpace {
 
 func (t Text) IsNode() bool { return true }
 func (t Text) Write(w io.Writer, indent int) error {
-	return writeIndent(w, indent, t.Value)
+	return writeIndent(w, indent, t.HTML())
 }
 
 // <a .../> or <div ...>...</div>
 type Element struct {
-	Name           string
+	Name string
+	// Attributes in the order they're written. Attributes with the same name are all kept, so
+	// that the element is written as it was parsed, see DeduplicateAttributes.
 	Attributes     []Attribute
 	IndentAttrs    bool
 	Children       []Node
 	IndentChildren bool
 	TrailingSpace  TrailingSpace
-	Diagnostics    []Diagnostic
+	// SignificantTrailingSpace is set by MarkWhitespaceSignificance when the TrailingSpace is
+	// rendered between two nodes that may be displayed inline, like Whitespace.Significant.
+	SignificantTrailingSpace bool
+	Diagnostics              []Diagnostic
+	// Ignored is set when the element is preceded by a `<!-- templ:ignore -->` comment.
+	// Ignored elements are kept in the tree, but are not rendered.
+	Ignored bool
+	// Key is the value of the element's `key` or `data-key` attribute, or the Go expression
+	// for an expression attribute, e.g. `key={ item.ID }`. Diff uses it to match elements.
+	// The attribute is still rendered.
+	Key string
+	// PreserveWhitespace is set for elements where whitespace is significant, i.e. <pre> and
+	// <textarea>, and the elements within them. Their whitespace is parsed as Text, so that it's
+	// rendered and formatted verbatim.
+	PreserveWhitespace bool
+	// Range of the element in the template, from the `<` of the start tag to the end of the
+	// close tag.
+	Range Range
+	// Source is the text within the Range, when TemplateFileParser.RetainSource is set.
+	Source string
 }
 
 func (e Element) Trailing() TrailingSpace {
@@ -452,7 +524,7 @@ var voidElements = map[string]struct{}{
 
 // https://www.w3.org/TR/2011/WD-html-markup-20110113/syntax.html#void-element
 func (e Element) IsVoidElement() bool {
-	_, ok := voidElements[e.Name]
+	_, ok := voidElements[strings.ToLower(e.Name)]
 	return ok
 }
 
@@ -510,6 +582,141 @@ func containsNonTextNodes(nodes []Node) bool {
 	return false
 }
 
+// Attr returns the first attribute with the given name. Names are compared case-insensitively.
+// Spread and conditional attributes aren't known until render time, so they're not searched.
+func (e Element) Attr(name string) (attr Attribute, ok bool) {
+	for _, a := range e.Attributes {
+		if strings.EqualFold(attributeName(a), name) {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// elementKey returns the key of an element with the attributes, see Element.Key.
+func elementKey(attrs []Attribute) string {
+	for _, a := range attrs {
+		switch a := a.(type) {
+		case ConstantAttribute:
+			if isKeyAttribute(a.Name) {
+				return a.Value
+			}
+		case ExpressionAttribute:
+			if isKeyAttribute(a.Name) {
+				return a.Expression.Value
+			}
+		}
+	}
+	return ""
+}
+
+func isKeyAttribute(name string) bool {
+	return strings.EqualFold(name, "key") || strings.EqualFold(name, "data-key")
+}
+
+// HasAttr returns true if the element has an attribute with the given name.
+func (e Element) HasAttr(name string) bool {
+	_, ok := e.Attr(name)
+	return ok
+}
+
+// SetAttr sets the value of the first attribute with the given name, replacing it with a
+// constant attribute, whatever kind of attribute it was. If there isn't one, the attribute is
+// added to the end. The attributes are copied, so other copies of the element aren't changed.
+func (e *Element) SetAttr(name, value string) {
+	attrs := make([]Attribute, 0, len(e.Attributes)+1)
+	var replaced bool
+	for _, a := range e.Attributes {
+		if !replaced && strings.EqualFold(attributeName(a), name) {
+			a, replaced = ConstantAttribute{Name: name, Value: value}, true
+		}
+		attrs = append(attrs, a)
+	}
+	if !replaced {
+		attrs = append(attrs, ConstantAttribute{Name: name, Value: value})
+	}
+	e.Attributes = attrs
+	e.Key = elementKey(e.Attributes)
+}
+
+// RemoveAttr removes the attributes with the given name, and returns true if any were removed.
+// Like Attr, conditional attributes aren't searched. The attributes are copied, so other copies
+// of the element aren't changed.
+func (e *Element) RemoveAttr(name string) bool {
+	if !e.HasAttr(name) {
+		return false
+	}
+	attrs := make([]Attribute, 0, len(e.Attributes))
+	for _, a := range e.Attributes {
+		if !strings.EqualFold(attributeName(a), name) {
+			attrs = append(attrs, a)
+		}
+	}
+	e.Attributes = attrs
+	e.Key = elementKey(e.Attributes)
+	return true
+}
+
+// DeduplicateAttributes removes the attributes with the same name as an earlier attribute, so
+// that, like Attr, the first is kept, as it is by browsers. Names are compared
+// case-insensitively, and spread and conditional attributes are kept. In strict mode, an error is
+// returned for the first duplicate instead, and the attributes aren't changed.
+func (e *Element) DeduplicateAttributes(strict bool) error {
+	seen := make(map[string]bool, len(e.Attributes))
+	attrs := make([]Attribute, 0, len(e.Attributes))
+	for _, a := range e.Attributes {
+		name := strings.ToLower(attributeName(a))
+		if name != "" && seen[name] {
+			if strict {
+				return fmt.Errorf("<%s>: duplicate attribute %q", e.Name, name)
+			}
+			continue
+		}
+		seen[name] = true
+		attrs = append(attrs, a)
+	}
+	if len(attrs) == len(e.Attributes) {
+		return nil
+	}
+	e.Attributes = attrs
+	e.Key = elementKey(e.Attributes)
+	return nil
+}
+
+// ClassList returns the classes in the element's class attribute. If the element has no
+// class attribute, or the class attribute is an expression, nil is returned.
+func (e Element) ClassList() []string {
+	attr, ok := e.Attr("class")
+	if !ok {
+		return nil
+	}
+	ca, ok := attr.(ConstantAttribute)
+	if !ok {
+		return nil
+	}
+	return strings.Fields(ca.Value)
+}
+
+func attributeName(attr Attribute) string {
+	switch a := attr.(type) {
+	case BoolConstantAttribute:
+		return a.Name
+	case ConstantAttribute:
+		return a.Name
+	case BoolExpressionAttribute:
+		return a.Name
+	case ExpressionAttribute:
+		return a.Name
+	case ClassAttribute:
+		return a.Name
+	case StyleAttribute:
+		return "style"
+	case SrcsetAttribute:
+		return "srcset"
+	}
+	return ""
+}
+
 func (e Element) IsNode() bool { return true }
 func (e Element) Write(w io.Writer, indent int) error {
 	if err := writeIndent(w, indent, "<", e.Name); err != nil {
@@ -601,9 +808,17 @@ func writeNodes(w io.Writer, level int, nodes []Node, indent bool) error {
 		trailing := SpaceVertical
 		if wst, isWhitespaceTrailer := nodes[i].(WhitespaceTrailer); isWhitespaceTrailer {
 			trailing = wst.Trailing()
+		} else if i+1 < len(nodes) {
+			if ws, isWhitespace := nodes[i+1].(Whitespace); isWhitespace && strings.Count(ws.Value, "\n") > 1 {
+				trailing = SpaceVerticalDouble
+			}
+		}
+		// Blank lines are only kept between indented nodes.
+		if trailing == SpaceVerticalDouble && (!indent || isLastNode(nodes, i)) {
+			trailing = SpaceVertical
 		}
 		// Put a newline after the last node in indentation mode.
-		if indent && ((nextNodeIsBlock(nodes, i) || i == len(nodes)-1) || shouldAlwaysBreakAfter(nodes[i])) {
+		if indent && ((nextNodeIsBlock(nodes, i) || i == len(nodes)-1) || shouldAlwaysBreakAfter(nodes[i])) && trailing != SpaceVerticalDouble {
 			trailing = SpaceVertical
 		}
 		switch trailing {
@@ -611,7 +826,7 @@ func writeNodes(w io.Writer, level int, nodes []Node, indent bool) error {
 			level = 0
 		case SpaceHorizontal:
 			level = 0
-		case SpaceVertical:
+		case SpaceVertical, SpaceVerticalDouble:
 			level = startLevel
 		}
 		if _, err := w.Write([]byte(trailing)); err != nil {
@@ -621,6 +836,16 @@ func writeNodes(w io.Writer, level int, nodes []Node, indent bool) error {
 	return nil
 }
 
+// isLastNode returns true if there are only whitespace nodes after the node at i.
+func isLastNode(nodes []Node, i int) bool {
+	for _, n := range nodes[i+1:] {
+		if _, isWhitespace := n.(Whitespace); !isWhitespace {
+			return false
+		}
+	}
+	return true
+}
+
 func shouldAlwaysBreakAfter(node Node) bool {
 	if el, isElement := node.(Element); isElement {
 		return strings.EqualFold(el.Name, "br") || strings.EqualFold(el.Name, "hr")
@@ -643,6 +868,12 @@ func isBlockNode(node Node) bool {
 		return true
 	case ForExpression:
 		return true
+	case ConstBlock:
+		return true
+	case OnceExpression:
+		return true
+	case FragmentBlock:
+		return true
 	case Element:
 		return n.IsBlockElement() || n.IndentChildren
 	}
@@ -653,6 +884,18 @@ type RawElement struct {
 	Name       string
 	Attributes []Attribute
 	Contents   string
+	// Interpolate is set for a <script> element with the `templ:interpolate` attribute, which
+	// opts in to Go expressions within its contents, see Parts. The attribute isn't included in
+	// Attributes, and isn't rendered.
+	Interpolate bool
+	// Parts of the contents of a <script> element that interpolates Go expressions, e.g.
+	// `var x = {{ data }};`, in order. Each is a Text or a StringExpression. It's nil when there
+	// are no expressions, and Contents is kept verbatim either way.
+	Parts []Node
+	// Range of the element in the template, including the start and end tags.
+	Range Range
+	// Source, see TemplateFileParser.RetainSource.
+	Source string
 }
 
 func (e RawElement) IsNode() bool { return true }
@@ -671,6 +914,11 @@ func (e RawElement) Write(w io.Writer, indent int) error {
 			return err
 		}
 	}
+	if e.Interpolate {
+		if _, err := io.WriteString(w, " "+interpolateAttribute); err != nil {
+			return err
+		}
+	}
 	if _, err := w.Write([]byte(">")); err != nil {
 		return err
 	}
@@ -693,6 +941,8 @@ type Attribute interface {
 // <hr noshade/>
 type BoolConstantAttribute struct {
 	Name string
+	// Range of the attribute's name in the template.
+	Range Range
 }
 
 func (bca BoolConstantAttribute) String() string {
@@ -705,17 +955,24 @@ func (bca BoolConstantAttribute) Write(w io.Writer, indent int) error {
 
 // href=""
 type ConstantAttribute struct {
-	Name        string
-	Value       string
+	Name  string
+	Value string
+	// SingleQuote is true if the value is written in single quotes.
 	SingleQuote bool
+	// SourceSingleQuote is true if the value was single quoted in the template, see
+	// QuoteStylePreserve.
+	SourceSingleQuote bool
+	// Range of the attribute in the template, from the start of its name to the end of its
+	// value.
+	Range Range
 }
 
 func (ca ConstantAttribute) String() string {
-	quote := `"`
+	quote, escaped := `"`, "&quot;"
 	if ca.SingleQuote {
-		quote = `'`
+		quote, escaped = `'`, "&#39;"
 	}
-	return ca.Name + `=` + quote + ca.Value + quote
+	return ca.Name + `=` + quote + strings.ReplaceAll(ca.Value, quote, escaped) + quote
 }
 
 func (ca ConstantAttribute) Write(w io.Writer, indent int) error {
@@ -740,6 +997,18 @@ func (ea BoolExpressionAttribute) Write(w io.Writer, indent int) error {
 type ExpressionAttribute struct {
 	Name       string
 	Expression Expression
+	// Handler is set if the attribute is an event handler that calls a function, e.g.
+	// onclick={ onClickHandler(item.ID) }.
+	Handler *EventHandler
+}
+
+// EventHandler is a call to a function, such as a script template, in an event handler
+// attribute.
+type EventHandler struct {
+	// Name of the function, e.g. `onClickHandler` or `scripts.OnClick`.
+	Name string
+	// Args passed to the function.
+	Args []Expression
 }
 
 func (ea ExpressionAttribute) String() string {
@@ -794,9 +1063,70 @@ func (ea ExpressionAttribute) Write(w io.Writer, indent int) (err error) {
 	return writeIndent(w, indent, "}")
 }
 
+// ClassAttribute is a class attribute with a dynamic class list, where classes can be
+// expressions, or only included if a condition is true.
+// class={ "btn", templ.KV("large", isLarge), "active": isActive }
+type ClassAttribute struct {
+	Name    string
+	Classes []ClassPart
+	// Expression is the class list as it was written, without the braces.
+	Expression Expression
+}
+
+// HasConditionalClasses returns true if any of the classes use the `"class": cond` shorthand.
+func (ca ClassAttribute) HasConditionalClasses() bool {
+	for _, c := range ca.Classes {
+		if c.IsConditional() {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassPart is an entry within a ClassAttribute. Unconditional entries have an Expression,
+// e.g. `"btn"`, while conditional entries have a Class that's included if Cond is true, e.g.
+// `"active": isActive`.
+type ClassPart struct {
+	Expression Expression
+	Class      string
+	Cond       Expression
+}
+
+// IsConditional returns true if the class is only included if Cond is true.
+func (cp ClassPart) IsConditional() bool {
+	return cp.Class != ""
+}
+
+func (cp ClassPart) String() string {
+	if cp.IsConditional() {
+		return strconv.Quote(cp.Class) + ": " + cp.Cond.Value
+	}
+	return cp.Expression.Value
+}
+
+func (ca ClassAttribute) String() string {
+	sb := new(strings.Builder)
+	_ = ca.Write(sb, 0)
+	return sb.String()
+}
+
+func (ca ClassAttribute) Write(w io.Writer, indent int) error {
+	// Without the shorthand, the expression is valid Go, so it's formatted like other expressions.
+	if ca.Expression.Value != "" && !ca.HasConditionalClasses() {
+		return ExpressionAttribute{Name: ca.Name, Expression: ca.Expression}.Write(w, indent)
+	}
+	parts := make([]string, len(ca.Classes))
+	for i, c := range ca.Classes {
+		parts[i] = c.String()
+	}
+	return ExpressionAttribute{Name: ca.Name, Expression: Expression{Value: strings.Join(parts, ", ")}}.Write(w, indent)
+}
+
 // <a { spread... } />
 type SpreadAttributes struct {
 	Expression Expression
+	// Range of the attribute within the file, from the start of `{` to the end of `}`.
+	Range Range
 }
 
 func (sa SpreadAttributes) String() string {
@@ -877,6 +1207,9 @@ func (ca ConditionalAttribute) Write(w io.Writer, indent int) error {
 type GoComment struct {
 	Contents  string
 	Multiline bool
+	// Range of the comment within the file, from the start of `//` or `/*` to the end of the
+	// line, or `*/`.
+	Range Range
 }
 
 func (c GoComment) IsNode() bool { return true }
@@ -887,9 +1220,114 @@ func (c GoComment) Write(w io.Writer, indent int) error {
 	return writeIndent(w, indent, "//", c.Contents)
 }
 
-// HTMLComment.
+// ConstBlock declares Go constants that are scoped to the enclosing template.
+//
+//	const (
+//	  title = "Home"
+//	  maxItems = 10
+//	)
+type ConstBlock struct {
+	// Declarations contains each constant, e.g. `title = "Home"`.
+	Declarations []Expression
+}
+
+func (cb ConstBlock) IsNode() bool { return true }
+func (cb ConstBlock) Write(w io.Writer, indent int) error {
+	if len(cb.Declarations) == 1 {
+		return writeIndent(w, indent, "const ", cb.Declarations[0].Value)
+	}
+	if err := writeIndent(w, indent, "const (\n"); err != nil {
+		return err
+	}
+	for _, d := range cb.Declarations {
+		if err := writeIndent(w, indent+1, d.Value, "\n"); err != nil {
+			return err
+		}
+	}
+	return writeIndent(w, indent, ")")
+}
+
+// GoCode is a block of Go statements within a template, e.g. to declare variables that are
+// used later in the template.
+//
+//	{{ total := len(items) }}
+type GoCode struct {
+	// Expression contains the Go statements, without the surrounding braces.
+	Expression Expression
+	// TrailingSpace lists what happens after the block.
+	TrailingSpace TrailingSpace
+	// SignificantTrailingSpace, see MarkWhitespaceSignificance.
+	SignificantTrailingSpace bool
+	// Multiline is set if the statements start on a new line after the opening braces.
+	Multiline bool
+}
+
+func (gc GoCode) Trailing() TrailingSpace {
+	return gc.TrailingSpace
+}
+
+func (gc GoCode) IsNode() bool { return true }
+func (gc GoCode) Write(w io.Writer, indent int) error {
+	if !gc.Multiline {
+		return writeIndent(w, indent, "{{ ", gc.Expression.Value, " }}")
+	}
+	if err := writeIndent(w, indent, "{{\n"); err != nil {
+		return err
+	}
+	for _, line := range dedentGoCode(gc.Expression.Value) {
+		if line == "" {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeIndent(w, indent+1, line, "\n"); err != nil {
+			return err
+		}
+	}
+	return writeIndent(w, indent, "}}")
+}
+
+// dedentGoCode splits the code into lines, and removes the indentation that's common to all
+// but the first line, since the indentation of the first line isn't part of the code.
+func dedentGoCode(code string) []string {
+	lines := strings.Split(code, "\n")
+	common, found := "", false
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indentation := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !found || len(indentation) < len(common) {
+			common, found = indentation, true
+		}
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = strings.TrimPrefix(line, common)
+	}
+	return lines
+}
+
+// HTMLComment, e.g. `<!-- comment -->`. The contents are kept verbatim, including any
+// whitespace and newlines.
 type HTMLComment struct {
 	Contents string
+	// Range of the comment within the file, from the start of `<!--` to the end of `-->`.
+	Range Range
+	// Source, see TemplateFileParser.RetainSource.
+	Source string
+}
+
+const ignoreDirective = "templ:ignore"
+
+// IsIgnoreDirective returns true if the comment is a `<!-- templ:ignore -->` directive,
+// which excludes the following element from the rendered output.
+func (c HTMLComment) IsIgnoreDirective() bool {
+	return strings.TrimSpace(c.Contents) == ignoreDirective
 }
 
 func (c HTMLComment) IsNode() bool { return true }
@@ -897,19 +1335,48 @@ func (c HTMLComment) Write(w io.Writer, indent int) error {
 	return writeIndent(w, indent, "<!--", c.Contents, "-->")
 }
 
+// CData is a CDATA section, e.g. `<![CDATA[ x < y ]]>`, as used in SVG and MathML. The value
+// is kept verbatim, and isn't escaped when it's rendered.
+type CData struct {
+	Value string
+	// Range of the section within the file, from the start of `<![CDATA[` to the end of `]]>`.
+	Range Range
+}
+
+func (c CData) IsNode() bool { return true }
+func (c CData) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "<![CDATA[", c.Value, "]]>")
+}
+
 // Nodes.
 
 // CallTemplateExpression can be used to create and render a template using data.
 // {! Other(p.First, p.Last) }
 // or it can be used to render a template parameter.
 // {! v }
+//
+// Templates can also be called with named arguments, which are reordered to match the
+// parameters of the template during code generation.
+// {! Card(title: "Hi", body: content) }
 type CallTemplateExpression struct {
 	// Expression returns a template to execute.
 	Expression Expression
+	// NamedArgs are set if the template is called using named arguments.
+	NamedArgs []NamedArg
+}
+
+// NamedArg is an argument in a call expression, e.g. `title: "Hi"`.
+type NamedArg struct {
+	Name  string
+	Value Expression
 }
 
 func (cte CallTemplateExpression) IsNode() bool { return true }
 func (cte CallTemplateExpression) Write(w io.Writer, indent int) error {
+	// Named arguments aren't valid Go, so can't be rewritten to the new call syntax.
+	if len(cte.NamedArgs) > 0 {
+		return writeIndent(w, indent, `{! `, cte.Expression.Value, ` }`)
+	}
 	// Rewrite to new call syntax
 	return writeIndent(w, indent, `@`, cte.Expression.Value)
 }
@@ -924,6 +1391,11 @@ type TemplElementExpression struct {
 	// Children returns the elements in a block element.
 	Children    []Node
 	Diagnostics []Diagnostic
+	// Range of the expression in the template, from the `@` to the end of the expression, or
+	// the closing brace of its children.
+	Range Range
+	// Source, see TemplateFileParser.RetainSource.
+	Source string
 }
 
 func (tee TemplElementExpression) IsNode() bool { return true }
@@ -950,9 +1422,117 @@ func (tee TemplElementExpression) Write(w io.Writer, indent int) error {
 	return nil
 }
 
+// JSONScriptExpression embeds data as JSON within a script element, so that it can be read by
+// client side scripts.
+// @templ.JSONScript("user-data", user)
+type JSONScriptExpression struct {
+	// ID of the script element.
+	ID Expression
+	// Data to encode as JSON.
+	Data Expression
+}
+
+func (jse JSONScriptExpression) IsNode() bool { return true }
+func (jse JSONScriptExpression) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "@templ.JSONScript(", jse.ID.Value, ", ", jse.Data.Value, ")")
+}
+
+// OnceExpression contains nodes that are rendered at most once per render, even if the
+// component that contains them is rendered many times, e.g. to include a script that the
+// component depends on.
+// @once { <script src="/button.js"></script> }
+type OnceExpression struct {
+	Children    []Node
+	Diagnostics []Diagnostic
+}
+
+func (oe OnceExpression) IsNode() bool { return true }
+func (oe OnceExpression) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, "@once {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, oe.Children); err != nil {
+		return err
+	}
+	return writeIndent(w, indent, "}")
+}
+
+// AssetExpression outputs the URL of a local asset, e.g. a fingerprinted URL that's resolved
+// when the template is rendered, see templ.WithAssetResolver.
+// @asset "images/logo.png"
+type AssetExpression struct {
+	Path string
+}
+
+func (ae AssetExpression) IsNode() bool { return true }
+func (ae AssetExpression) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "@asset ", strconv.Quote(ae.Path))
+}
+
+// RawHTML outputs trusted HTML verbatim, without parsing or escaping it, like templ.Raw.
+// @raw { <div>Hello</div> }
+type RawHTML struct {
+	Value string
+}
+
+func (rh RawHTML) IsNode() bool { return true }
+func (rh RawHTML) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "@raw {", rh.Value, "}")
+}
+
+// TranslationExpression outputs localized text for a message key, using the translator set
+// with templ.WithTranslator when the template is rendered.
+// { i18n("greeting", user.Name) }
+type TranslationExpression struct {
+	// Key of the message.
+	Key Expression
+	// Args used to format the message.
+	Args []Expression
+	// TrailingSpace lists what happens after the expression.
+	TrailingSpace TrailingSpace
+	// SignificantTrailingSpace, see MarkWhitespaceSignificance.
+	SignificantTrailingSpace bool
+}
+
+func (te TranslationExpression) Trailing() TrailingSpace {
+	return te.TrailingSpace
+}
+
+func (te TranslationExpression) IsNode() bool { return true }
+func (te TranslationExpression) Write(w io.Writer, indent int) error {
+	args := []string{te.Key.Value}
+	for _, arg := range te.Args {
+		args = append(args, arg.Value)
+	}
+	return writeIndent(w, indent, "{ i18n(", strings.Join(args, ", "), ") }")
+}
+
+// FragmentBlock marks part of a template that can be rendered on its own by name, e.g. to
+// respond to a request for part of a page.
+// @fragment "item-list" { <ul></ul> }
+type FragmentBlock struct {
+	Name        string
+	Children    []Node
+	Diagnostics []Diagnostic
+}
+
+func (fb FragmentBlock) IsNode() bool { return true }
+func (fb FragmentBlock) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, "@fragment ", strconv.Quote(fb.Name), " {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, fb.Children); err != nil {
+		return err
+	}
+	return writeIndent(w, indent, "}")
+}
+
 // ChildrenExpression can be used to rended the children of a templ element.
 // { children ... }
-type ChildrenExpression struct{}
+type ChildrenExpression struct {
+	// Range of the expression within the file, from the start of `{` to the end of `}`.
+	Range Range
+}
 
 func (ChildrenExpression) IsNode() bool { return true }
 func (ChildrenExpression) Write(w io.Writer, indent int) error {
@@ -970,6 +1550,10 @@ type IfExpression struct {
 	ElseIfs     []ElseIfExpression
 	Else        []Node
 	Diagnostics []Diagnostic
+	// Range of the expression in the template, from `if` to the closing brace.
+	Range Range
+	// Source includes each branch, see TemplateFileParser.RetainSource.
+	Source string
 }
 
 type ElseIfExpression struct {
@@ -978,6 +1562,28 @@ type ElseIfExpression struct {
 	Diagnostics []Diagnostic
 }
 
+// Nested returns the if expression with its else if branches nested, so that each branch is an
+// IfExpression that's the only node in the Else of the branch before it, and the final else is
+// the Else of the last branch, e.g. for code that handles one condition at a time. The parser
+// keeps the branches in ElseIfs, so that they're formatted as they were written.
+func (n IfExpression) Nested() IfExpression {
+	if len(n.ElseIfs) == 0 {
+		n.ElseIfs = nil
+		return n
+	}
+	first := n.ElseIfs[0]
+	next := IfExpression{
+		Expression:  first.Expression,
+		Then:        first.Then,
+		ElseIfs:     n.ElseIfs[1:],
+		Else:        n.Else,
+		Diagnostics: first.Diagnostics,
+	}
+	n.ElseIfs = nil
+	n.Else = []Node{next.Nested()}
+	return n
+}
+
 func (n IfExpression) IsNode() bool { return true }
 func (n IfExpression) Write(w io.Writer, indent int) error {
 	if err := writeIndent(w, indent, "if ", n.Expression.Value, " {\n"); err != nil {
@@ -1018,6 +1624,10 @@ func (n IfExpression) Write(w io.Writer, indent int) error {
 type SwitchExpression struct {
 	Expression Expression
 	Cases      []CaseExpression
+	// Range of the expression in the template, from `switch` to the closing brace.
+	Range Range
+	// Source, see TemplateFileParser.RetainSource.
+	Source string
 }
 
 func (se SwitchExpression) IsNode() bool { return true }
@@ -1056,6 +1666,10 @@ type ForExpression struct {
 	Expression  Expression
 	Children    []Node
 	Diagnostics []Diagnostic
+	// Range of the expression in the template, from `for` to the closing brace.
+	Range Range
+	// Source, see TemplateFileParser.RetainSource.
+	Source string
 }
 
 func (fe ForExpression) IsNode() bool { return true }
@@ -1078,6 +1692,16 @@ type StringExpression struct {
 	Expression Expression
 	// TrailingSpace lists what happens after the expression.
 	TrailingSpace TrailingSpace
+	// SignificantTrailingSpace, see MarkWhitespaceSignificance.
+	SignificantTrailingSpace bool
+	// TrimLeft and TrimRight are set by the `{- ` and ` -}` trim markers, e.g. `{- name -}`, which
+	// remove the whitespace before and after the expression, except within a <pre> element.
+	TrimLeft  bool
+	TrimRight bool
+	// Range of the expression in the template, including the braces.
+	Range Range
+	// Source includes the braces, see TemplateFileParser.RetainSource.
+	Source string
 }
 
 func (se StringExpression) Trailing() TrailingSpace {
@@ -1090,7 +1714,44 @@ func (se StringExpression) Write(w io.Writer, indent int) error {
 	if isWhitespace(se.Expression.Value) {
 		se.Expression.Value = ""
 	}
-	return writeIndent(w, indent, `{ `, se.Expression.Value, ` }`)
+	open, close := `{ `, ` }`
+	if se.TrimLeft {
+		open = `{- `
+	}
+	if se.TrimRight {
+		close = ` -}`
+	}
+	return writeIndent(w, indent, open, se.Expression.Value, close)
+}
+
+// InlineIfExpression is a string expression that chooses which string to output with an if
+// statement, where each branch contains a single expression.
+// { if user != nil { user.Name } else { "Guest" } }
+type InlineIfExpression struct {
+	Expression Expression
+	Then       Expression
+	// Else is optional, if it's empty, nothing is output when the condition is false.
+	Else Expression
+	// TrailingSpace lists what happens after the expression.
+	TrailingSpace TrailingSpace
+}
+
+func (ie InlineIfExpression) Trailing() TrailingSpace {
+	return ie.TrailingSpace
+}
+
+func (ie InlineIfExpression) IsNode() bool { return true }
+func (ie InlineIfExpression) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, `{ if `, ie.Expression.Value, ` { `, ie.Then.Value, ` }`); err != nil {
+		return err
+	}
+	if ie.Else.Value != "" {
+		if _, err := io.WriteString(w, ` else { `+ie.Else.Value+` }`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, ` }`)
+	return err
 }
 
 // ScriptTemplate is a script block.
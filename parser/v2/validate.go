@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is a problem with a template that parses, but breaks one of the rules of HTML,
+// e.g. an element with two id attributes.
+type ValidationError struct {
+	Message string
+	// Node that breaks the rule.
+	Node Node
+	// Range of the problem within the file, e.g. of the duplicate attribute, or the element.
+	Range Range
+}
+
+// Rule checks a node, and returns an error for each problem it finds. Rules are called with every
+// node in the template, so they don't need to check the nodes within the node.
+type Rule func(n Node) []ValidationError
+
+// DefaultRules are the rules checked by Validate when no rules are passed.
+var DefaultRules = []Rule{DuplicateAttributes, VoidElementChildren, UnknownDocType}
+
+// Validate checks each node in the template against the rules, or DefaultRules if rules is nil,
+// and returns the errors, in the order of the nodes.
+func Validate(t HTMLTemplate, rules []Rule) (errs []ValidationError) {
+	if rules == nil {
+		rules = DefaultRules
+	}
+	Walk(t.Children, func(n Node) bool {
+		for _, rule := range rules {
+			errs = append(errs, rule(n)...)
+		}
+		return true
+	})
+	return errs
+}
+
+// DuplicateAttributes reports attributes that are set more than once on an element, e.g.
+// `<div id="a" id="b">`. Attribute names aren't case sensitive. Conditional and spread
+// attributes aren't checked, since they may not be set.
+func DuplicateAttributes(n Node) (errs []ValidationError) {
+	e, ok := n.(Element)
+	if !ok {
+		return nil
+	}
+	seen := make(map[string]bool, len(e.Attributes))
+	for _, attr := range e.Attributes {
+		name := strings.ToLower(attributeName(attr))
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			r := attributeRange(attr)
+			if r == (Range{}) {
+				r = validationRange(e)
+			}
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("<%s>: duplicate attribute %q", e.Name, name),
+				Node:    e,
+				Range:   r,
+			})
+			continue
+		}
+		seen[name] = true
+	}
+	return errs
+}
+
+// VoidElementChildren reports void elements, e.g. <br>, that have children. The parser doesn't
+// allow them, but they can be added to the parsed nodes, e.g. with Transform.
+func VoidElementChildren(n Node) []ValidationError {
+	e, ok := n.(Element)
+	if !ok || !e.IsVoidElement() || !e.hasNonWhitespaceChildren() {
+		return nil
+	}
+	return []ValidationError{{
+		Message: fmt.Sprintf("<%s>: void elements can't have children", e.Name),
+		Node:    e,
+		Range:   validationRange(e),
+	}}
+}
+
+// UnknownDocType reports doctypes other than `<!DOCTYPE html>`, and the legacy doctypes that
+// start with `html PUBLIC` or `html SYSTEM`.
+func UnknownDocType(n Node) []ValidationError {
+	dt, ok := n.(DocType)
+	if !ok {
+		return nil
+	}
+	value := strings.ToLower(strings.Join(strings.Fields(dt.Value), " "))
+	if value == "html" || strings.HasPrefix(value, "html public ") || strings.HasPrefix(value, "html system ") {
+		return nil
+	}
+	return []ValidationError{{
+		Message: fmt.Sprintf("unknown doctype %q, expected <!DOCTYPE html>", dt.Value),
+		Node:    dt,
+		Range:   dt.Range,
+	}}
+}
+
+// attributeRange returns the range of the attribute, or of its expression.
+func attributeRange(attr Attribute) Range {
+	switch a := attr.(type) {
+	case ConstantAttribute:
+		return a.Range
+	case BoolConstantAttribute:
+		return a.Range
+	case ExpressionAttribute:
+		return a.Expression.Range
+	case BoolExpressionAttribute:
+		return a.Expression.Range
+	case ClassAttribute:
+		return a.Expression.Range
+	case SpreadAttributes:
+		return a.Expression.Range
+	case ConditionalAttribute:
+		return a.Expression.Range
+	}
+	return Range{}
+}
+
+// validationRange returns the range of the node, or the first range recorded within it, e.g.
+// of an attribute, or a zero Range.
+func validationRange(node Node) (r Range) {
+	if r = nodeRange(node); r != (Range{}) {
+		return r
+	}
+	if e, ok := node.(Element); ok {
+		for _, attr := range e.Attributes {
+			if r = attributeRange(attr); r != (Range{}) {
+				return r
+			}
+		}
+	}
+	for _, child := range ChildNodes(node) {
+		if r = validationRange(child); r != (Range{}) {
+			return r
+		}
+	}
+	return r
+}
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	parseTemplate := func(t *testing.T, src string) HTMLTemplate {
+		t.Helper()
+		tem, ok, err := template.Parse(parse.NewInput(src))
+		if err != nil || !ok {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		return tem
+	}
+	messages := func(errs []ValidationError) (msgs []string) {
+		for _, err := range errs {
+			msgs = append(msgs, err.Message)
+		}
+		return msgs
+	}
+
+	t.Run("valid templates have no errors", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x() {
+	<!DOCTYPE html>
+	<div id="a" class="b"><br/>Text</div>
+}`)
+		if errs := Validate(tem, nil); len(errs) != 0 {
+			t.Errorf("expected no errors, got %v", messages(errs))
+		}
+	})
+	t.Run("duplicate attributes are reported", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x(id string) {
+	<ul>
+		<li id="a" class="b" ID={ id }>Text</li>
+	</ul>
+}`)
+		errs := Validate(tem, nil)
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %v", messages(errs))
+		}
+		if diff := cmp.Diff(`<li>: duplicate attribute "id"`, errs[0].Message); diff != "" {
+			t.Error(diff)
+		}
+		expectedRange := Range{
+			From: Position{Index: 55, Line: 2, Col: 28},
+			To:   Position{Index: 57, Line: 2, Col: 30},
+		}
+		if diff := cmp.Diff(expectedRange, errs[0].Range); diff != "" {
+			t.Error(diff)
+		}
+		if e, ok := errs[0].Node.(Element); !ok || e.Name != "li" {
+			t.Errorf("expected the <li> element, got %v", errs[0].Node)
+		}
+	})
+	t.Run("duplicate constant attributes are reported at the duplicate", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x() {
+	<div id="a" id="b"></div>
+}`)
+		errs := Validate(tem, nil)
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %v", messages(errs))
+		}
+		expectedRange := Range{
+			From: Position{Index: 25, Line: 1, Col: 13},
+			To:   Position{Index: 31, Line: 1, Col: 19},
+		}
+		if diff := cmp.Diff(expectedRange, errs[0].Range); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("void elements with children are reported", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x() {
+	<div><br/></div>
+}`)
+		tem.Children = Transform(tem.Children, func(n Node) Node {
+			if e, ok := n.(Element); ok && e.Name == "br" {
+				e.Children = []Node{Text{Value: "Text"}}
+				return e
+			}
+			return n
+		})
+		expected := []string{"<br>: void elements can't have children"}
+		if diff := cmp.Diff(expected, messages(Validate(tem, nil))); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("unknown doctypes are reported", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x() {
+	<!DOCTYPE html PUBLIC "-//W3C//DTD HTML 4.01//EN" "http://www.w3.org/TR/html4/strict.dtd">
+	<!DOCTYPE xhtml>
+}`)
+		errs := Validate(tem, nil)
+		expected := []string{`unknown doctype "xhtml", expected <!DOCTYPE html>`}
+		if diff := cmp.Diff(expected, messages(errs)); diff != "" {
+			t.Fatal(diff)
+		}
+		expectedRange := Range{
+			From: Position{Index: 105, Line: 2, Col: 1},
+			To:   Position{Index: 121, Line: 2, Col: 17},
+		}
+		if diff := cmp.Diff(expectedRange, errs[0].Range); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("custom rules can be used", func(t *testing.T) {
+		tem := parseTemplate(t, `templ x() {
+	<div id="a" id="b"><li>Text</li></div>
+}`)
+		listItemOutsideList := func(n Node) (errs []ValidationError) {
+			e, ok := n.(Element)
+			if !ok || e.Name == "ul" || e.Name == "ol" {
+				return nil
+			}
+			for _, child := range e.Children {
+				if c, ok := child.(Element); ok && c.Name == "li" {
+					errs = append(errs, ValidationError{Message: "<li> must be within a list", Node: c})
+				}
+			}
+			return errs
+		}
+		expected := []string{"<li> must be within a list"}
+		if diff := cmp.Diff(expected, messages(Validate(tem, []Rule{listItemOutsideList}))); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
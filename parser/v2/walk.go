@@ -0,0 +1,84 @@
+package parser
+
+// Walk visits each node, and the nodes within it, depth first, in the order they appear in the
+// template. If fn returns false, the nodes within the node aren't visited, but Walk carries on
+// with the node's siblings. To walk a template, pass its Children.
+func Walk(nodes []Node, fn func(n Node) bool) {
+	for _, n := range nodes {
+		if fn(n) {
+			Walk(ChildNodes(n), fn)
+		}
+	}
+}
+
+// ChildNodes returns the nodes directly within the node, e.g. the children of an element, or
+// all of the branches of an if expression, in order. Nodes that can't contain other nodes
+// return nil.
+func ChildNodes(node Node) []Node {
+	switch n := node.(type) {
+	case Element:
+		return n.Children
+	case TemplElementExpression:
+		return n.Children
+	case OnceExpression:
+		return n.Children
+	case FragmentBlock:
+		return n.Children
+	case IfExpression:
+		children := append([]Node{}, n.Then...)
+		for _, elseIf := range n.ElseIfs {
+			children = append(children, elseIf.Then...)
+		}
+		return append(children, n.Else...)
+	case SwitchExpression:
+		var children []Node
+		for _, c := range n.Cases {
+			children = append(children, c.Children...)
+		}
+		return children
+	case ForExpression:
+		return n.Children
+	}
+	return nil
+}
+
+// nodeRange returns the range of the node within the template. Nodes that don't record their own
+// range return the range of their expression, e.g. a template call, or a zero Range if they have
+// neither.
+func nodeRange(node Node) Range {
+	switch n := node.(type) {
+	case Element:
+		return n.Range
+	case RawElement:
+		return n.Range
+	case Text:
+		return n.Range
+	case DocType:
+		return n.Range
+	case HTMLComment:
+		return n.Range
+	case CData:
+		return n.Range
+	case GoComment:
+		return n.Range
+	case ChildrenExpression:
+		return n.Range
+	case StringExpression:
+		return n.Range
+	case IfExpression:
+		return n.Range
+	case SwitchExpression:
+		return n.Range
+	case ForExpression:
+		return n.Range
+	case TemplElementExpression:
+		return n.Range
+	case InlineIfExpression:
+		return n.Expression.Range
+	case TranslationExpression:
+		return n.Key.Range
+	case CallTemplateExpression:
+		return n.Expression.Range
+	}
+	return Range{}
+}
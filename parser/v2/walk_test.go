@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWalk(t *testing.T) {
+	input := parse.NewInput(`templ x(items []string) {
+	<div>
+		if len(items) == 0 {
+			<p>None</p>
+		} else if len(items) == 1 {
+			<p>One</p>
+		} else {
+			<ul>
+				for _, item := range items {
+					<li>{ item }</li>
+				}
+			</ul>
+		}
+	</div>
+	switch len(items) {
+		case 0:
+			<span>a</span>
+		default:
+			<i>b</i>
+	}
+}`)
+	tem, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	describe := func(n Node) string {
+		switch n := n.(type) {
+		case Element:
+			return "<" + n.Name + ">"
+		case Text:
+			return n.Value
+		case StringExpression:
+			return "{ " + n.Expression.Value + " }"
+		}
+		return fmt.Sprintf("%T", n)
+	}
+
+	t.Run("visits nodes depth first", func(t *testing.T) {
+		var visited []string
+		Walk(tem.Children, func(n Node) bool {
+			if _, isWhitespace := n.(Whitespace); !isWhitespace {
+				visited = append(visited, describe(n))
+			}
+			return true
+		})
+		expected := []string{
+			"<div>",
+			"parser.IfExpression",
+			"<p>", "None",
+			"<p>", "One",
+			"<ul>", "parser.ForExpression", "<li>", "{ item }",
+			"parser.SwitchExpression",
+			"<span>", "a",
+			"<i>", "b",
+		}
+		if diff := cmp.Diff(expected, visited); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("returning false skips the node's children", func(t *testing.T) {
+		var visited []string
+		Walk(tem.Children, func(n Node) bool {
+			if _, isWhitespace := n.(Whitespace); !isWhitespace {
+				visited = append(visited, describe(n))
+			}
+			_, isIf := n.(IfExpression)
+			return !isIf
+		})
+		expected := []string{
+			"<div>",
+			"parser.IfExpression",
+			"parser.SwitchExpression",
+			"<span>", "a",
+			"<i>", "b",
+		}
+		if diff := cmp.Diff(expected, visited); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
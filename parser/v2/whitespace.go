@@ -0,0 +1,68 @@
+package parser
+
+// MarkWhitespaceSignificance returns a copy of the template where each Whitespace node has
+// Significant set if it's rendered between two nodes that may be displayed inline, e.g.
+// between two <span> elements. Whitespace next to a block element, such as a <div>, or at the
+// start or end of a list of nodes is collapsed by browsers, so it's insignificant, and can be
+// removed or reformatted.
+//
+// The parser keeps most of the whitespace between nodes as the TrailingSpace of the node before
+// it, e.g. the space in `<span>a</span> <span>b</span>`, so nodes with a TrailingSpace have
+// SignificantTrailingSpace set in the same way.
+//
+// Nodes whose display can't be known until the template is rendered, such as template calls
+// and expressions, are treated as inline, so whitespace next to them is kept.
+func MarkWhitespaceSignificance(t HTMLTemplate) HTMLTemplate {
+	t.Children = mapNodeLists(t.Children, markWhitespaceSignificance)
+	return t
+}
+
+func markWhitespaceSignificance(nodes []Node) []Node {
+	for i, n := range nodes {
+		betweenInline := i > 0 && isInlineDisplay(nodes[i-1]) && i < len(nodes)-1 && isInlineDisplay(nodes[i+1])
+		if ws, ok := n.(Whitespace); ok {
+			ws.Significant = ws.Value != "" && betweenInline
+			nodes[i] = ws
+			continue
+		}
+		significant := isInlineDisplay(n) && i < len(nodes)-1 && isInlineDisplay(nodes[i+1])
+		nodes[i] = withTrailingSpaceSignificance(n, significant)
+	}
+	return nodes
+}
+
+// withTrailingSpaceSignificance sets SignificantTrailingSpace on the node, if it has a
+// TrailingSpace.
+func withTrailingSpaceSignificance(node Node, significant bool) Node {
+	if wt, ok := node.(WhitespaceTrailer); !ok || wt.Trailing() == SpaceNone {
+		significant = false
+	}
+	switch n := node.(type) {
+	case Element:
+		n.SignificantTrailingSpace = significant
+		return n
+	case Text:
+		n.SignificantTrailingSpace = significant
+		return n
+	case GoCode:
+		n.SignificantTrailingSpace = significant
+		return n
+	case StringExpression:
+		n.SignificantTrailingSpace = significant
+		return n
+	case TranslationExpression:
+		n.SignificantTrailingSpace = significant
+		return n
+	}
+	return node
+}
+
+func isInlineDisplay(n Node) bool {
+	switch n := n.(type) {
+	case Element:
+		return !n.IsBlockElement()
+	case RawElement, DocType, Whitespace:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMarkWhitespaceSignificance(t *testing.T) {
+	span := func(text string) Element {
+		return Element{Name: "span", Children: []Node{Text{Value: text}}}
+	}
+	div := func(text string) Element {
+		return Element{Name: "div", Children: []Node{Text{Value: text}}}
+	}
+	tem := HTMLTemplate{
+		Children: []Node{
+			Element{
+				Name: "p",
+				Children: []Node{
+					Whitespace{Value: "\n\t"},
+					span("A"),
+					Whitespace{Value: " "},
+					span("B"),
+					Whitespace{Value: "\n"},
+				},
+			},
+			div("A"),
+			Whitespace{Value: "\n"},
+			div("B"),
+		},
+	}
+
+	var actual []bool
+	mapNodeLists(MarkWhitespaceSignificance(tem).Children, func(nodes []Node) []Node {
+		for _, n := range nodes {
+			if ws, ok := n.(Whitespace); ok {
+				actual = append(actual, ws.Significant)
+			}
+		}
+		return nodes
+	})
+	// Whitespace at the start and end of the <p>, between the <span> elements, and between
+	// the <div> elements.
+	expected := []bool{false, true, false, false}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMarkWhitespaceSignificanceOfTrailingSpace(t *testing.T) {
+	tem, ok, err := template.Parse(parse.NewInput(`templ x() {
+	<p><span>a</span> <span>b</span></p>
+	<div>A</div>
+	<div>B</div>
+}`))
+	if err != nil || !ok {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	actual := map[string]bool{}
+	Walk(MarkWhitespaceSignificance(tem).Children, func(n Node) bool {
+		if e, ok := n.(Element); ok && e.TrailingSpace != SpaceNone {
+			actual[e.Name+":"+strings.TrimSpace(TextContent(e, ""))] = e.SignificantTrailingSpace
+		}
+		return true
+	})
+	// The space between the <span> elements is significant, the new lines after the <p> and
+	// <div> elements aren't.
+	expected := map[string]bool{
+		"span:a": true,
+		"p:a b":  false,
+		"div:A":  false,
+		"div:B":  false,
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -49,9 +49,33 @@ func WithChildren(ctx context.Context, children Component) context.Context {
 func ClearChildren(ctx context.Context) context.Context {
 	_, v := getContext(ctx)
 	v.children = nil
+	v.slots = nil
 	return ctx
 }
 
+// WithSlots sets the content to render in the named slots of the next component rendered with
+// ctx, e.g. `<slot name="header">`. Like children, slots are cleared by ClearChildren.
+func WithSlots(ctx context.Context, slots map[string]Component) context.Context {
+	ctx, v := getContext(ctx)
+	v.slots = slots
+	return ctx
+}
+
+// GetSlots returns the content of the named slots from the context, see WithSlots.
+func GetSlots(ctx context.Context) map[string]Component {
+	_, v := getContext(ctx)
+	return v.slots
+}
+
+// Slot returns the content of the named slot, or fallback if the slot hasn't been filled.
+// It's used by generated code to render `<slot>` elements.
+func Slot(slots map[string]Component, name string, fallback Component) Component {
+	if c, ok := slots[name]; ok && c != nil {
+		return c
+	}
+	return fallback
+}
+
 // NopComponent is a component that doesn't render anything.
 var NopComponent = ComponentFunc(func(ctx context.Context, w io.Writer) error { return nil })
 
@@ -132,6 +156,150 @@ func WithErrorHandler(eh func(r *http.Request, err error) http.Handler) func(*Co
 	}
 }
 
+// RenderStreaming renders each of the components to w in turn. If w implements
+// http.Flusher, it is flushed after each component is rendered, so that clients
+// receive the content progressively, rather than waiting for the whole page.
+// Pass each top-level part of the page as a separate component, e.g. the header,
+// the main content and the footer, since flushing happens between components.
+func RenderStreaming(ctx context.Context, w io.Writer, components ...Component) (err error) {
+	f, canFlush := w.(http.Flusher)
+	for _, c := range components {
+		if err = c.Render(ctx, w); err != nil {
+			return err
+		}
+		if canFlush {
+			f.Flush()
+		}
+	}
+	return nil
+}
+
+const fragmentContextKey = contextKeyType(2)
+
+type fragmentContext struct {
+	w io.Writer
+	// rendered records whether each requested fragment has been rendered.
+	rendered map[string]bool
+}
+
+// ErrFragmentNotFound is returned by RenderFragments when a requested fragment isn't rendered
+// by the component.
+var ErrFragmentNotFound = errors.New("templ: fragment not found")
+
+// RenderFragments renders only the named @fragment blocks of the component to w, e.g. to
+// respond to a request for part of a page. The rest of the component's output is discarded.
+// An error wrapping ErrFragmentNotFound is returned if any of the named fragments isn't
+// rendered.
+func RenderFragments(ctx context.Context, w io.Writer, c Component, names ...string) error {
+	fc := &fragmentContext{
+		w:        w,
+		rendered: make(map[string]bool, len(names)),
+	}
+	for _, name := range names {
+		fc.rendered[name] = false
+	}
+	ctx = context.WithValue(ctx, fragmentContextKey, fc)
+	if err := c.Render(ctx, io.Discard); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if !fc.rendered[name] {
+			return fmt.Errorf("%w: %q", ErrFragmentNotFound, name)
+		}
+	}
+	return nil
+}
+
+// Fragment returns a component that renders its children, which are set with WithChildren.
+// It's used by generated code to render @fragment blocks, so that RenderFragments can render
+// the children of the named fragment on their own.
+func Fragment(name string) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		children := GetChildren(ctx)
+		ctx = ClearChildren(ctx)
+		if fc, ok := ctx.Value(fragmentContextKey).(*fragmentContext); ok {
+			if _, requested := fc.rendered[name]; requested {
+				fc.rendered[name] = true
+				w = fc.w
+			}
+		}
+		return children.Render(ctx, w)
+	})
+}
+
+const assetResolverKey = contextKeyType(4)
+
+// AssetResolver returns the URL to use for a local asset, e.g. a fingerprinted URL such as
+// "/images/logo.3f2a1b.png" for the path "images/logo.png".
+type AssetResolver func(path string) (url string, err error)
+
+// WithAssetResolver sets the resolver used to produce the URLs of @asset expressions rendered
+// with ctx.
+func WithAssetResolver(ctx context.Context, resolver AssetResolver) context.Context {
+	return context.WithValue(ctx, assetResolverKey, resolver)
+}
+
+// ResolveAsset returns the URL of the asset at path, using the AssetResolver set with
+// WithAssetResolver. If no resolver has been set, the path is returned unchanged.
+// It's used by generated code to render @asset expressions.
+func ResolveAsset(ctx context.Context, path string) (url string, err error) {
+	resolver, ok := ctx.Value(assetResolverKey).(AssetResolver)
+	if !ok {
+		return path, nil
+	}
+	if url, err = resolver(path); err != nil {
+		return "", fmt.Errorf("templ: failed to resolve asset %q: %w", path, err)
+	}
+	return url, nil
+}
+
+const translatorKey = contextKeyType(5)
+
+// Translator returns the localized text for a message key, e.g. by looking it up in a message
+// catalog for the user's language and formatting it with args.
+type Translator func(key string, args ...any) string
+
+// WithTranslator sets the translator used to render i18n expressions with ctx.
+func WithTranslator(ctx context.Context, translator Translator) context.Context {
+	return context.WithValue(ctx, translatorKey, translator)
+}
+
+// Translate returns the text for the message key, using the Translator set with
+// WithTranslator. If no translator has been set, the key is returned unchanged.
+// It's used by generated code to render i18n expressions.
+func Translate(ctx context.Context, key string, args ...any) string {
+	translator, ok := ctx.Value(translatorKey).(Translator)
+	if !ok {
+		return key
+	}
+	return translator(key, args...)
+}
+
+// JSONScript returns a component that renders data as JSON within a
+// <script type="application/json"> element with the given id, so that it can be read by
+// client side scripts. The JSON is HTML escaped, so data containing "</script>" can't end the
+// element early.
+func JSONScript(id string, data any) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		enc, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		return writeStrings(w, `<script type="application/json" id="`, EscapeString(id), `">`, string(enc), `</script>`)
+	})
+}
+
+// JSONString returns v encoded as JSON, for a Go expression interpolated within a <script>
+// element, e.g. `var user = {{ user }};`. Like JSONScript, the JSON is HTML escaped, so a value
+// containing "</script>" can't end the element.
+func JSONString(v any) (string, error) {
+	enc, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(enc), nil
+}
+
 // EscapeString escapes HTML text within templates.
 func EscapeString(s string) string {
 	return html.EscapeString(s)
@@ -577,6 +745,10 @@ const contextKey = contextKeyType(0)
 type contextValue struct {
 	ss       map[string]struct{}
 	children *Component
+	slots    map[string]Component
+	// limitRenderDepth is set if a maximum depth was set with WithMaxRenderDepth, so that
+	// EnterComponent only tracks the depth of components when there's a limit.
+	limitRenderDepth bool
 }
 
 func (v *contextValue) addScript(s string) {
@@ -609,16 +781,163 @@ func (v *contextValue) hasClassBeenRendered(s string) (ok bool) {
 	return
 }
 
+func (v *contextValue) addOnce(s string) (added bool) {
+	if v.ss == nil {
+		v.ss = map[string]struct{}{}
+	}
+	if _, ok := v.ss["once_"+s]; ok {
+		return false
+	}
+	v.ss["once_"+s] = struct{}{}
+	return true
+}
+
+// Once returns true the first time it's called with the id within a render, and false
+// afterwards. It's used by generated code to render the contents of @once blocks once.
+func Once(ctx context.Context, id string) bool {
+	_, v := getContext(ctx)
+	return v.addOnce(id)
+}
+
 // InitializeContext initializes context used to store internal state used during rendering.
 func InitializeContext(ctx context.Context) context.Context {
 	if _, ok := ctx.Value(contextKey).(*contextValue); ok {
 		return ctx
 	}
 	v := &contextValue{}
+	_, v.limitRenderDepth = ctx.Value(renderDepthKey).(renderDepth)
 	ctx = context.WithValue(ctx, contextKey, v)
 	return ctx
 }
 
+const renderDepthKey = contextKeyType(1)
+
+type renderDepth struct {
+	depth int
+	max   int
+}
+
+// ErrMaxRenderDepthExceeded is returned when components are nested more deeply than the limit
+// set with WithMaxRenderDepth.
+var ErrMaxRenderDepthExceeded = errors.New("templ: maximum render depth exceeded")
+
+// WithMaxRenderDepth limits how deeply components can be nested when rendered with ctx.
+// This guards against infinite recursion in components that render themselves based on
+// data, e.g. a tree view component rendering cyclic data.
+func WithMaxRenderDepth(ctx context.Context, depth int) context.Context {
+	if v, ok := ctx.Value(contextKey).(*contextValue); ok {
+		v.limitRenderDepth = true
+	}
+	return context.WithValue(ctx, renderDepthKey, renderDepth{max: depth})
+}
+
+// EnterComponent is called by generated code when a component starts rendering. It initializes
+// the context, like InitializeContext. If a maximum render depth has been set with
+// WithMaxRenderDepth, the depth of nested components is tracked, and an error is returned if the
+// limit is exceeded.
+func EnterComponent(ctx context.Context) (context.Context, error) {
+	ctx, v := getContext(ctx)
+	if !v.limitRenderDepth {
+		return ctx, nil
+	}
+	rd, ok := ctx.Value(renderDepthKey).(renderDepth)
+	if !ok {
+		return ctx, nil
+	}
+	rd.depth++
+	if rd.depth > rd.max {
+		return ctx, fmt.Errorf("%w: components are nested more than %d levels deep, check for recursive components rendering cyclic or unbounded data", ErrMaxRenderDepthExceeded, rd.max)
+	}
+	return context.WithValue(ctx, renderDepthKey, rd), nil
+}
+
+const duplicateIDsKey = contextKeyType(3)
+
+// IDLocation is where an element id was rendered from. Ids set with an expression are located
+// at the expression, and constant ids at the attribute.
+type IDLocation struct {
+	// FileName of the template file.
+	FileName string
+	// Line index of the id.
+	Line int
+	// Col index of the id.
+	Col int
+}
+
+func (l IDLocation) String() string {
+	return fmt.Sprintf("%s:%d:%d", l.FileName, l.Line, l.Col)
+}
+
+// DuplicateID is an element id that was rendered more than once.
+type DuplicateID struct {
+	ID        string
+	Locations []IDLocation
+}
+
+// DuplicateIDsError is returned by the function returned from DetectDuplicateIDs when an
+// element id was rendered more than once.
+type DuplicateIDsError struct {
+	Duplicates []DuplicateID
+}
+
+func (e DuplicateIDsError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("templ: duplicate element ids:")
+	for _, d := range e.Duplicates {
+		sb.WriteString(" ")
+		sb.WriteString(strconv.Quote(d.ID))
+		sb.WriteString(" rendered at")
+		for i, l := range d.Locations {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(" ")
+			sb.WriteString(l.String())
+		}
+		sb.WriteString(";")
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+type idRecorder struct {
+	ids       []string
+	locations map[string][]IDLocation
+}
+
+// DetectDuplicateIDs records the id attributes of elements rendered with the returned context.
+// After rendering, call check to get a DuplicateIDsError listing any id that was rendered more
+// than once, or nil if there were no duplicates. Only the ids of components generated with
+// `templ generate -record-ids` are recorded.
+func DetectDuplicateIDs(ctx context.Context) (_ context.Context, check func() error) {
+	r := &idRecorder{locations: map[string][]IDLocation{}}
+	check = func() error {
+		var e DuplicateIDsError
+		for _, id := range r.ids {
+			if locations := r.locations[id]; len(locations) > 1 {
+				e.Duplicates = append(e.Duplicates, DuplicateID{ID: id, Locations: locations})
+			}
+		}
+		if len(e.Duplicates) == 0 {
+			return nil
+		}
+		return e
+	}
+	return context.WithValue(ctx, duplicateIDsKey, r), check
+}
+
+// RecordID is called by generated code when an element's id attribute is rendered, so that
+// duplicates can be reported if DetectDuplicateIDs is in use.
+func RecordID(ctx context.Context, id string, fileName string, line, col int) {
+	r, ok := ctx.Value(duplicateIDsKey).(*idRecorder)
+	if !ok {
+		return
+	}
+	if _, seen := r.locations[id]; !seen {
+		r.ids = append(r.ids, id)
+	}
+	r.locations[id] = append(r.locations[id], IDLocation{FileName: fileName, Line: line, Col: col})
+}
+
 func getContext(ctx context.Context) (context.Context, *contextValue) {
 	v, ok := ctx.Value(contextKey).(*contextValue)
 	if !ok {
@@ -472,6 +472,71 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+type recordingFlusher struct {
+	events []string
+}
+
+func (rf *recordingFlusher) Write(p []byte) (n int, err error) {
+	rf.events = append(rf.events, "write: "+string(p))
+	return len(p), nil
+}
+
+func (rf *recordingFlusher) Flush() {
+	rf.events = append(rf.events, "flush")
+}
+
+func TestRenderStreaming(t *testing.T) {
+	text := func(s string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		})
+	}
+	t.Run("the writer is flushed after each component", func(t *testing.T) {
+		w := &recordingFlusher{}
+		err := templ.RenderStreaming(context.Background(), w, text("<header></header>"), text("<main></main>"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{
+			"write: <header></header>",
+			"flush",
+			"write: <main></main>",
+			"flush",
+		}
+		if diff := cmp.Diff(expected, w.events); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("writers that can't be flushed receive the content", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		err := templ.RenderStreaming(context.Background(), w, text("<header></header>"), text("<main></main>"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<header></header><main></main>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("rendering stops at the first error", func(t *testing.T) {
+		w := &recordingFlusher{}
+		errorComponent := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return errors.New("render error")
+		})
+		err := templ.RenderStreaming(context.Background(), w, text("<header></header>"), errorComponent, text("<main></main>"))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		expected := []string{
+			"write: <header></header>",
+			"flush",
+		}
+		if diff := cmp.Diff(expected, w.events); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
 func TestRenderScriptItems(t *testing.T) {
 	s1 := templ.ComponentScript{
 		Name:     "s1",
@@ -725,3 +790,165 @@ func TestGoHTMLComponents(t *testing.T) {
 		}
 	})
 }
+
+func TestEnterComponent(t *testing.T) {
+	t.Run("without a maximum depth, components can be nested without limit", func(t *testing.T) {
+		ctx := context.Background()
+		var err error
+		for i := 0; i < 1000; i++ {
+			if ctx, err = templ.EnterComponent(ctx); err != nil {
+				t.Fatalf("unexpected error at depth %d: %v", i, err)
+			}
+		}
+	})
+	t.Run("components can be nested up to the maximum depth", func(t *testing.T) {
+		ctx := templ.WithMaxRenderDepth(context.Background(), 3)
+		var err error
+		for i := 0; i < 3; i++ {
+			if ctx, err = templ.EnterComponent(ctx); err != nil {
+				t.Fatalf("unexpected error at depth %d: %v", i, err)
+			}
+		}
+		if _, err = templ.EnterComponent(ctx); !errors.Is(err, templ.ErrMaxRenderDepthExceeded) {
+			t.Errorf("expected ErrMaxRenderDepthExceeded, got %v", err)
+		}
+	})
+	t.Run("the maximum depth can be set within a render", func(t *testing.T) {
+		ctx, err := templ.EnterComponent(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ctx = templ.WithMaxRenderDepth(ctx, 1)
+		if ctx, err = templ.EnterComponent(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err = templ.EnterComponent(ctx); !errors.Is(err, templ.ErrMaxRenderDepthExceeded) {
+			t.Errorf("expected ErrMaxRenderDepthExceeded, got %v", err)
+		}
+	})
+}
+
+func TestOnce(t *testing.T) {
+	ctx := templ.InitializeContext(context.Background())
+	if !templ.Once(ctx, "a") {
+		t.Error("expected the first call to return true")
+	}
+	if templ.Once(ctx, "a") {
+		t.Error("expected the second call to return false")
+	}
+	if !templ.Once(ctx, "b") {
+		t.Error("expected a different id to return true")
+	}
+	if !templ.Once(templ.InitializeContext(context.Background()), "a") {
+		t.Error("expected a new render context to return true")
+	}
+}
+
+func TestJSONScript(t *testing.T) {
+	data := map[string]any{
+		"name": "</script><script>alert(1)</script>",
+		"age":  42,
+	}
+	w := new(bytes.Buffer)
+	if err := templ.JSONScript("user-data", data).Render(context.Background(), w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `<script type="application/json" id="user-data">{"age":42,"name":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"}</script>`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+	if bytes.Count(w.Bytes(), []byte("</script>")) != 1 {
+		t.Errorf("expected the data to be escaped, got %q", w.String())
+	}
+}
+
+func TestJSONScriptMarshalError(t *testing.T) {
+	err := templ.JSONScript("id", make(chan int)).Render(context.Background(), io.Discard)
+	if err == nil {
+		t.Error("expected an error for data that can't be marshalled")
+	}
+}
+
+func TestJSONString(t *testing.T) {
+	s, err := templ.JSONString([]string{"a", "</script>"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(`["a","\u003c/script\u003e"]`, s); diff != "" {
+		t.Error(diff)
+	}
+	if _, err := templ.JSONString(make(chan int)); err == nil {
+		t.Error("expected an error for a value that can't be marshalled")
+	}
+}
+
+func TestDetectDuplicateIDs(t *testing.T) {
+	ctx, check := templ.DetectDuplicateIDs(context.Background())
+	templ.RecordID(ctx, "a", "a.templ", 1, 2)
+	templ.RecordID(ctx, "b", "a.templ", 3, 4)
+	templ.RecordID(ctx, "a", "b.templ", 5, 6)
+	err := check()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expected := `templ: duplicate element ids: "a" rendered at a.templ:1:2, b.templ:5:6`
+	if diff := cmp.Diff(expected, err.Error()); diff != "" {
+		t.Error(diff)
+	}
+	// Recording without detection enabled is a no-op.
+	templ.RecordID(context.Background(), "a", "a.templ", 1, 2)
+}
+
+func TestResolveAsset(t *testing.T) {
+	url, err := templ.ResolveAsset(context.Background(), "images/logo.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "images/logo.png" {
+		t.Errorf("expected the path to be returned without a resolver, got %q", url)
+	}
+	errNotFound := errors.New("not found")
+	ctx := templ.WithAssetResolver(context.Background(), func(path string) (string, error) {
+		return "", errNotFound
+	})
+	if _, err = templ.ResolveAsset(ctx, "missing.png"); !errors.Is(err, errNotFound) {
+		t.Errorf("expected the resolver error to be wrapped, got %v", err)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if text := templ.Translate(context.Background(), "greeting"); text != "greeting" {
+		t.Errorf("expected the key to be returned without a translator, got %q", text)
+	}
+	ctx := templ.WithTranslator(context.Background(), func(key string, args ...any) string {
+		return fmt.Sprintf("fr:"+key+":%v", args...)
+	})
+	if text := templ.Translate(ctx, "greeting", "Alice"); text != "fr:greeting:Alice" {
+		t.Errorf("expected the translator to be called with the args, got %q", text)
+	}
+}
+
+func TestSlots(t *testing.T) {
+	render := func(c templ.Component) string {
+		var buf bytes.Buffer
+		if err := c.Render(context.Background(), &buf); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		return buf.String()
+	}
+	fallback := templ.Raw("<h1>Fallback</h1>")
+	ctx := templ.WithSlots(templ.InitializeContext(context.Background()), map[string]templ.Component{
+		"header": templ.Raw("<h1>Header</h1>"),
+	})
+	slots := templ.GetSlots(ctx)
+	if actual := render(templ.Slot(slots, "header", fallback)); actual != "<h1>Header</h1>" {
+		t.Errorf("expected the slot content to be used, got %q", actual)
+	}
+	if actual := render(templ.Slot(slots, "footer", fallback)); actual != "<h1>Fallback</h1>" {
+		t.Errorf("expected the fallback to be used for an unfilled slot, got %q", actual)
+	}
+	ctx = templ.ClearChildren(ctx)
+	if slots := templ.GetSlots(ctx); slots != nil {
+		t.Errorf("expected slots to be cleared with children, got %v", slots)
+	}
+}
@@ -0,0 +1,34 @@
+package vdom
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/a-h/templ"
+)
+
+// ChangedRoots renders both components and returns the indices of the root nodes whose
+// output differs, so that only the changed top-level nodes need to be sent to a client.
+// Indices that only exist in one of the renders are also returned. Components that render a
+// whole document have two roots, the doctype and the <html> element, see Render.
+func ChangedRoots(ctx context.Context, previous, next templ.Component) ([]int, error) {
+	previousNodes, err := Render(ctx, previous)
+	if err != nil {
+		return nil, err
+	}
+	nextNodes, err := Render(ctx, next)
+	if err != nil {
+		return nil, err
+	}
+	n := len(previousNodes)
+	if len(nextNodes) > n {
+		n = len(nextNodes)
+	}
+	changed := []int{}
+	for i := 0; i < n; i++ {
+		if i >= len(previousNodes) || i >= len(nextNodes) || !reflect.DeepEqual(previousNodes[i], nextNodes[i]) {
+			changed = append(changed, i)
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,90 @@
+package vdom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+type profile struct {
+	Name    string
+	Email   string
+	Friends []string
+}
+
+func profileComponent(p profile) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := fmt.Fprintf(w, "<h1>%s</h1><p>%s</p>", templ.EscapeString(p.Name), templ.EscapeString(p.Email)); err != nil {
+			return err
+		}
+		for _, f := range p.Friends {
+			if _, err := fmt.Fprintf(w, "<span>%s</span>", templ.EscapeString(f)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestChangedRoots(t *testing.T) {
+	previous := profile{Name: "Alice", Email: "alice@example.com", Friends: []string{"Bob"}}
+	var tests = []struct {
+		name     string
+		next     profile
+		expected []int
+	}{
+		{
+			name:     "unchanged data reports no changes",
+			next:     previous,
+			expected: []int{},
+		},
+		{
+			name:     "changing a field reports the root that renders it",
+			next:     profile{Name: "Alice", Email: "alice@example.org", Friends: []string{"Bob"}},
+			expected: []int{1},
+		},
+		{
+			name:     "added roots are reported",
+			next:     profile{Name: "Alice", Email: "alice@example.com", Friends: []string{"Bob", "Charlie"}},
+			expected: []int{3},
+		},
+		{
+			name:     "removed roots are reported",
+			next:     profile{Name: "Alice", Email: "alice@example.com"},
+			expected: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ChangedRoots(context.Background(), profileComponent(previous), profileComponent(tt.next))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func pageComponent(title string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "<!DOCTYPE html><html><head><title>%s</title></head><body></body></html>", templ.EscapeString(title))
+		return err
+	})
+}
+
+func TestChangedRootsDocument(t *testing.T) {
+	actual, err := ChangedRoots(context.Background(), pageComponent("Home"), pageComponent("About"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int{1}, actual); diff != "" {
+		t.Error(diff)
+	}
+}
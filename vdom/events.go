@@ -0,0 +1,49 @@
+package vdom
+
+// events are the names of the DOM events that can be handled with an on* attribute, e.g. "click"
+// for onclick. Other attributes that start with "on", e.g. "one" or "only", aren't events.
+var events = map[string]bool{
+	// Window and document.
+	"afterprint": true, "beforeprint": true, "beforeunload": true, "hashchange": true,
+	"languagechange": true, "message": true, "messageerror": true, "offline": true,
+	"online": true, "pagehide": true, "pageshow": true, "popstate": true,
+	"rejectionhandled": true, "storage": true, "unhandledrejection": true, "unload": true,
+	"load": true, "resize": true, "scroll": true, "scrollend": true,
+	// Focus.
+	"blur": true, "focus": true, "focusin": true, "focusout": true,
+	// Forms.
+	"beforeinput": true, "change": true, "formdata": true, "input": true, "invalid": true,
+	"reset": true, "search": true, "select": true, "selectionchange": true,
+	"selectstart": true, "submit": true,
+	// Keyboard.
+	"keydown": true, "keypress": true, "keyup": true,
+	// Mouse.
+	"auxclick": true, "click": true, "contextmenu": true, "dblclick": true,
+	"mousedown": true, "mouseenter": true, "mouseleave": true, "mousemove": true,
+	"mouseout": true, "mouseover": true, "mouseup": true, "wheel": true,
+	// Pointers.
+	"gotpointercapture": true, "lostpointercapture": true, "pointercancel": true,
+	"pointerdown": true, "pointerenter": true, "pointerleave": true, "pointermove": true,
+	"pointerout": true, "pointerover": true, "pointerup": true,
+	// Touch.
+	"touchcancel": true, "touchend": true, "touchmove": true, "touchstart": true,
+	// Drag and drop.
+	"drag": true, "dragend": true, "dragenter": true, "dragleave": true, "dragover": true,
+	"dragstart": true, "drop": true,
+	// Clipboard.
+	"copy": true, "cut": true, "paste": true,
+	// Media.
+	"abort": true, "canplay": true, "canplaythrough": true, "cuechange": true,
+	"durationchange": true, "emptied": true, "ended": true, "error": true,
+	"loadeddata": true, "loadedmetadata": true, "loadstart": true, "pause": true,
+	"play": true, "playing": true, "progress": true, "ratechange": true, "seeked": true,
+	"seeking": true, "stalled": true, "suspend": true, "timeupdate": true,
+	"volumechange": true, "waiting": true,
+	// Animations and transitions.
+	"animationcancel": true, "animationend": true, "animationiteration": true,
+	"animationstart": true, "transitioncancel": true, "transitionend": true,
+	"transitionrun": true, "transitionstart": true,
+	// Elements.
+	"beforetoggle": true, "cancel": true, "close": true, "securitypolicyviolation": true,
+	"slotchange": true, "toggle": true,
+}
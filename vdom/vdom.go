@@ -0,0 +1,139 @@
+// Package vdom renders templ components to a virtual DOM, so that they can be rendered by
+// a JavaScript client instead of as HTML.
+package vdom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/a-h/templ"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// NodeType is the type of a VNode.
+type NodeType string
+
+const (
+	ElementNode NodeType = "element"
+	TextNode    NodeType = "text"
+	CommentNode NodeType = "comment"
+	DoctypeNode NodeType = "doctype"
+)
+
+// VNode is a node within a virtual DOM.
+type VNode struct {
+	Type NodeType `json:"type"`
+	// Tag is the name of an element, e.g. "div".
+	Tag string `json:"tag,omitempty"`
+	// Attrs of an element, excluding event handlers.
+	Attrs map[string]string `json:"attrs,omitempty"`
+	// Events maps the name of each event an element handles, e.g. "click" for an onclick
+	// attribute, to the JavaScript call that handles it, e.g. "__templ_onClick_1234()".
+	// The client resolves the handler, since functions can't be encoded as JSON.
+	Events map[string]string `json:"events,omitempty"`
+	// Text contents of text and comment nodes, or the name of a doctype.
+	Text     string  `json:"text,omitempty"`
+	Children []VNode `json:"children,omitempty"`
+}
+
+// Render renders the component and returns the resulting nodes. Output that starts with a
+// doctype or an <html> element is parsed as a whole document, so the nodes are the doctype and
+// the <html> element, otherwise it's parsed as the contents of a <body> element.
+func Render(ctx context.Context, c templ.Component) ([]VNode, error) {
+	buf := new(bytes.Buffer)
+	if err := c.Render(ctx, buf); err != nil {
+		return nil, err
+	}
+	if isDocument(buf.Bytes()) {
+		doc, err := html.Parse(buf)
+		if err != nil {
+			return nil, err
+		}
+		var nodes []*html.Node
+		for n := doc.FirstChild; n != nil; n = n.NextSibling {
+			nodes = append(nodes, n)
+		}
+		return convertNodes(nodes), nil
+	}
+	body := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	}
+	nodes, err := html.ParseFragment(buf, body)
+	if err != nil {
+		return nil, err
+	}
+	return convertNodes(nodes), nil
+}
+
+// RenderJSON renders the component and returns the resulting nodes as JSON.
+func RenderJSON(ctx context.Context, c templ.Component) ([]byte, error) {
+	nodes, err := Render(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(nodes)
+}
+
+// isDocument returns true if the HTML starts with a doctype or an <html> element, ignoring
+// leading whitespace.
+func isDocument(b []byte) bool {
+	b = bytes.TrimLeft(b, " \t\r\n\f")
+	if hasPrefixFold(b, "<!doctype") {
+		return true
+	}
+	return hasPrefixFold(b, "<html") && (len(b) == 5 || bytes.IndexByte([]byte(" \t\r\n\f/>"), b[5]) >= 0)
+}
+
+func hasPrefixFold(b []byte, prefix string) bool {
+	return len(b) >= len(prefix) && bytes.EqualFold(b[:len(prefix)], []byte(prefix))
+}
+
+func convertNodes(nodes []*html.Node) []VNode {
+	op := make([]VNode, 0, len(nodes))
+	for _, n := range nodes {
+		if vn, ok := convertNode(n); ok {
+			op = append(op, vn)
+		}
+	}
+	return op
+}
+
+func convertNode(n *html.Node) (vn VNode, ok bool) {
+	switch n.Type {
+	case html.TextNode:
+		return VNode{Type: TextNode, Text: n.Data}, true
+	case html.CommentNode:
+		return VNode{Type: CommentNode, Text: n.Data}, true
+	case html.DoctypeNode:
+		return VNode{Type: DoctypeNode, Text: n.Data}, true
+	case html.ElementNode:
+		vn = VNode{Type: ElementNode, Tag: n.Data}
+		for _, a := range n.Attr {
+			if event, isEvent := strings.CutPrefix(a.Key, "on"); isEvent && events[event] {
+				if vn.Events == nil {
+					vn.Events = map[string]string{}
+				}
+				vn.Events[event] = a.Val
+				continue
+			}
+			if vn.Attrs == nil {
+				vn.Attrs = map[string]string{}
+			}
+			vn.Attrs[a.Key] = a.Val
+		}
+		var children []*html.Node
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			children = append(children, c)
+		}
+		if len(children) > 0 {
+			vn.Children = convertNodes(children)
+		}
+		return vn, true
+	}
+	return vn, false
+}
@@ -0,0 +1,125 @@
+package vdom
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderJSON(t *testing.T) {
+	component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<div class="counter"><span>Count: 0</span><button type="button" onclick="__templ_increment_1234()">+</button></div>`)
+		return err
+	})
+
+	actual, err := RenderJSON(context.Background(), component)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `[
+		{
+			"type": "element",
+			"tag": "div",
+			"attrs": {"class": "counter"},
+			"children": [
+				{
+					"type": "element",
+					"tag": "span",
+					"children": [{"type": "text", "text": "Count: 0"}]
+				},
+				{
+					"type": "element",
+					"tag": "button",
+					"attrs": {"type": "button"},
+					"events": {"click": "__templ_increment_1234()"},
+					"children": [{"type": "text", "text": "+"}]
+				}
+			]
+		}
+	]`
+	var expectedValue, actualValue any
+	if err = json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Fatalf("invalid expected JSON: %v", err)
+	}
+	if err = json.Unmarshal(actual, &actualValue); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if diff := cmp.Diff(expectedValue, actualValue); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return io.ErrUnexpectedEOF
+	})
+	if _, err := RenderJSON(context.Background(), component); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected the render error to be returned, got %v", err)
+	}
+}
+
+func TestRenderDocument(t *testing.T) {
+	component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<!DOCTYPE html><html lang="en"><head><title>Page</title></head><body><p>Hi</p></body></html>`)
+		return err
+	})
+
+	actual, err := Render(context.Background(), component)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []VNode{
+		{Type: DoctypeNode, Text: "html"},
+		{
+			Type:  ElementNode,
+			Tag:   "html",
+			Attrs: map[string]string{"lang": "en"},
+			Children: []VNode{
+				{
+					Type: ElementNode,
+					Tag:  "head",
+					Children: []VNode{
+						{Type: ElementNode, Tag: "title", Children: []VNode{{Type: TextNode, Text: "Page"}}},
+					},
+				},
+				{
+					Type: ElementNode,
+					Tag:  "body",
+					Children: []VNode{
+						{Type: ElementNode, Tag: "p", Children: []VNode{{Type: TextNode, Text: "Hi"}}},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRenderEvents(t *testing.T) {
+	component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<div one="1" only="x" on="y" onclick="__templ_click_1234()"></div>`)
+		return err
+	})
+
+	actual, err := Render(context.Background(), component)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []VNode{
+		{
+			Type:   ElementNode,
+			Tag:    "div",
+			Attrs:  map[string]string{"one": "1", "only": "x", "on": "y"},
+			Events: map[string]string{"click": "__templ_click_1234()"},
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}